@@ -1,19 +1,38 @@
 package cli
 
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
 type Argument interface {
 	name() string
 	usage() string
 	isRequired() bool
+	isVariadic() bool
 	typeText() string
+	getChoices() []string
+	checkChoice(value string) error
+	complete(c *Command, toComplete string) []string
+	completeFiles() (bool, []string)
+	defaultText() string
+	setDefault(*Command)
 	validateArg(*Command) error
 }
 
 type ArgumentTyped[T any] struct {
-	Name        string               // Name of the argument
-	Usage       string               // Usage description for the argument
-	Required    bool                 // Whether this flag is required
-	AssignTo    *T                   // Optional pointer to the variable where the value should be stored
-	ValidateArg func(*Command) error // Optional validation for the argument
+	Name           string                                       // Name of the argument
+	Usage          string                                       // Usage description for the argument
+	Required       bool                                         // Whether this flag is required
+	DefaultValue   T                                            // Value used when an optional argument isn't given
+	Choices        []string                                     // Values the argument accepts, e.g. "active", "paused", any value is accepted if empty
+	AssignTo       *T                                           // Optional pointer to the variable where the value should be stored
+	ValidateArg    func(*Command) error                         // Optional validation for the argument
+	CompleteFunc   func(c *Command, toComplete string) []string // Optional function returning the shell completions for the argument, e.g. project names from an API
+	CompleteFiles  bool                                         // Whether the argument is completed by the shell as a file path
+	FileExtensions []string                                     // Extensions the file completion is limited to, e.g. "yaml", "yml", directories are always offered
 }
 
 func (a *ArgumentTyped[T]) name() string {
@@ -28,6 +47,55 @@ func (a *ArgumentTyped[T]) isRequired() bool {
 	return a.Required
 }
 
+// isVariadic reports whether the argument takes all the remaining positional arguments, which slice arguments do
+func (a *ArgumentTyped[T]) isVariadic() bool {
+	return reflect.TypeFor[T]().Kind() == reflect.Slice
+}
+
+func (a *ArgumentTyped[T]) getChoices() []string {
+	return a.Choices
+}
+
+// checkChoice checks a value given for the argument is one of its Choices, if any are set
+func (a *ArgumentTyped[T]) checkChoice(value string) error {
+	if len(a.Choices) == 0 || slices.Contains(a.Choices, value) {
+		return nil
+	}
+	return fmt.Errorf("invalid value %q for argument %s, must be one of: %s", value, a.Name, strings.Join(a.Choices, ", "))
+}
+
+// complete returns the shell completions for the argument, its Choices followed by the values from CompleteFunc
+func (a *ArgumentTyped[T]) complete(c *Command, toComplete string) []string {
+	values := append([]string{}, a.Choices...)
+	if a.CompleteFunc != nil {
+		values = append(values, a.CompleteFunc(c, toComplete)...)
+	}
+	return values
+}
+
+func (a *ArgumentTyped[T]) completeFiles() (bool, []string) {
+	return a.CompleteFiles, a.FileExtensions
+}
+
+// defaultText returns the default value as shown in the help, or "" if the argument doesn't have one
+func (a *ArgumentTyped[T]) defaultText() string {
+	if reflect.ValueOf(&a.DefaultValue).Elem().IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%v", a.DefaultValue)
+}
+
+// setDefault stores the default value, if there is one, as the value of the argument
+func (a *ArgumentTyped[T]) setDefault(c *Command) {
+	if reflect.ValueOf(&a.DefaultValue).Elem().IsZero() {
+		return
+	}
+	c.parsedArgs[a.Name] = a.DefaultValue
+	if a.AssignTo != nil {
+		*a.AssignTo = a.DefaultValue
+	}
+}
+
 // Add a typeText method to ArgumentTyped similar to FlagTyped
 func (a *ArgumentTyped[T]) typeText() string {
 	var zero T
@@ -41,6 +109,16 @@ func (a *ArgumentTyped[T]) validateArg(c *Command) error {
 	return nil
 }
 
+// hasVariadicArg reports whether the command has a variadic argument
+func (c *Command) hasVariadicArg() bool {
+	for _, arg := range c.Arguments {
+		if arg.isVariadic() {
+			return true
+		}
+	}
+	return false
+}
+
 type StringArg = ArgumentTyped[string]
 type IntArg = ArgumentTyped[int]
 type Int8Arg = ArgumentTyped[int8]
@@ -55,3 +133,10 @@ type Uint64Arg = ArgumentTyped[uint64]
 type Float32Arg = ArgumentTyped[float32]
 type Float64Arg = ArgumentTyped[float64]
 type BoolArg = ArgumentTyped[bool]
+
+type StringSliceArg = ArgumentTyped[[]string]
+type IntSliceArg = ArgumentTyped[[]int]
+type Int64SliceArg = ArgumentTyped[[]int64]
+type UintSliceArg = ArgumentTyped[[]uint]
+type Uint64SliceArg = ArgumentTyped[[]uint64]
+type Float64SliceArg = ArgumentTyped[[]float64]
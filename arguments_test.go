@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -429,3 +430,341 @@ func TestArgumentsWithSubcommands_EdgeCases(t *testing.T) {
 	}
 }
 
+
+func TestIntArgumentBasePrefix(t *testing.T) {
+	var value int
+
+	cmd := &Command{
+		Name: "test",
+		Arguments: []Argument{
+			&IntArg{
+				Name:     "mask",
+				AssignTo: &value,
+			},
+		},
+		Run: func(ctx context.Context, cmd *Command) error {
+			return nil
+		},
+	}
+
+	os.Args = []string{"test", "0xFF"}
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if value != 255 || cmd.GetIntArg("mask") != 255 {
+		t.Fatalf("expected 255, got %d", value)
+	}
+}
+
+func TestUintArgumentErrors(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr string
+	}{
+		{"-1", "argument count must be a non-negative integer, got -1"},
+		{"many", "argument count must be a non-negative integer, got many"},
+		{"256", "argument count must be at most 255, got 256"},
+	}
+
+	for _, tt := range tests {
+		cmd := &Command{
+			Name:      "test",
+			Arguments: []Argument{&Uint8Arg{Name: "count"}},
+			Run:       func(ctx context.Context, cmd *Command) error { return nil },
+		}
+
+		os.Args = []string{"test", "--", tt.value}
+		err := cmd.Execute(context.Background())
+		if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("value %q: expected error %q, got %v", tt.value, tt.wantErr, err)
+		}
+	}
+}
+
+func TestFlagForbiddingArguments(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		wantErr string
+		wantRun bool
+	}{
+		{"flag alone", []string{"--list"}, "", "", true},
+		{"flag with argument", []string{"--list", "target"}, "", "flag --list can't be used with arguments", false},
+		{"argument after flag terminator", []string{"--list", "--", "target"}, "", "flag --list can't be used with arguments", false},
+		{"argument alone", []string{"target"}, "", "", true},
+		{"missing required argument", []string{}, "", "missing required argument: target", false},
+		{"false flag with argument", []string{"--list=false", "target"}, "", "", true},
+		{"negated flag with argument", []string{"--no-list", "target"}, "", "", true},
+		{"false environment variable with argument", []string{"target"}, "false", "", true},
+		{"environment variable with argument", []string{"target"}, "true", "flag --list can't be used with arguments", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("APP_LIST", tt.env)
+			}
+
+			ran := false
+			cmd := &Command{
+				Name:      "test",
+				Flags:     []Flag{&BoolFlag{Name: "list", EnvVars: []string{"APP_LIST"}, ForbidArgs: true}},
+				Arguments: []Argument{&StringArg{Name: "target", Required: true}},
+				Run: func(ctx context.Context, cmd *Command) error {
+					ran = true
+					return nil
+				},
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+			if ran != tt.wantRun {
+				t.Errorf("expected run %v, got %v", tt.wantRun, ran)
+			}
+		})
+	}
+}
+
+func TestValidArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"valid", []string{"pods"}, ""},
+		{"valid with more args", []string{"services", "web"}, ""},
+		{"no args", []string{}, ""},
+		{"invalid", []string{"pod"}, "invalid argument 'pod', must be 'pods', 'services', or 'deployments'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			cmd := &Command{
+				Name:      "get",
+				MaxArgs:   UnlimitedArgs,
+				ValidArgs: []string{"pods", "services", "deployments"},
+				Run: func(ctx context.Context, cmd *Command) error {
+					if args := cmd.GetArgs(); len(args) > 0 {
+						got = args[0]
+					}
+					return nil
+				},
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tt.args) > 0 && got != tt.args[0] {
+				t.Errorf("expected first argument %q, got %q", tt.args[0], got)
+			}
+		})
+	}
+}
+
+func TestVariadicArgument(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxArgs int
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{name: "takes the rest", maxArgs: UnlimitedArgs, args: []string{"out", "a.txt", "b.txt", "c.txt"}, want: []string{"a.txt", "b.txt", "c.txt"}},
+		{name: "within MaxArgs", maxArgs: 2, args: []string{"out", "a.txt", "b.txt"}, want: []string{"a.txt", "b.txt"}},
+		{name: "over MaxArgs", maxArgs: 2, args: []string{"out", "a.txt", "b.txt", "c.txt"}, wantErr: "argument files accepts at most 2 values, got 3"},
+		{name: "NoArgs", args: []string{"out", "a.txt"}, wantErr: "argument files accepts at most 0 values, got 1"},
+		{name: "required but missing", maxArgs: UnlimitedArgs, args: []string{"out"}, wantErr: "missing required argument: files"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var assigned []string
+			var got []string
+			cmd := &Command{
+				Name:    "copy",
+				MaxArgs: tt.maxArgs,
+				Arguments: []Argument{
+					&StringArg{Name: "dest", Required: true},
+					&StringSliceArg{Name: "files", Required: true, AssignTo: &assigned},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					got = cmd.GetStringSliceArg("files")
+					if len(cmd.GetArgs()) != 0 {
+						t.Errorf("expected no unnamed arguments, got %v", cmd.GetArgs())
+					}
+					return nil
+				},
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !slices.Equal(got, tt.want) || !slices.Equal(assigned, tt.want) {
+				t.Errorf("expected %v, got %v and assigned %v", tt.want, got, assigned)
+			}
+		})
+	}
+}
+
+func TestVariadicArgument_MinArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		defaults []string
+		wantErr  string
+	}{
+		{"no values", []string{}, nil, "argument files needs at least 2 values, got 0"},
+		{"too few values", []string{"a.txt"}, nil, "argument files needs at least 2 values, got 1"},
+		{"enough values", []string{"a.txt", "b.txt"}, nil, ""},
+		{"enough default values", []string{}, []string{"a.txt", "b.txt"}, ""},
+		{"too few default values", []string{}, []string{"a.txt"}, "argument files needs at least 2 values, got 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			cmd := &Command{
+				Name:      "merge",
+				MinArgs:   2,
+				MaxArgs:   UnlimitedArgs,
+				Arguments: []Argument{&StringSliceArg{Name: "files", DefaultValue: tt.defaults}},
+				Run: func(ctx context.Context, cmd *Command) error {
+					ran = true
+					return nil
+				},
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			} else if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+			if ran != (tt.wantErr == "") {
+				t.Errorf("expected run %v, got %v", tt.wantErr == "", ran)
+			}
+		})
+	}
+}
+
+func TestVariadicNumericArgument(t *testing.T) {
+	cmd := &Command{
+		Name:      "sum",
+		MaxArgs:   UnlimitedArgs,
+		Arguments: []Argument{&IntSliceArg{Name: "values"}},
+		Run:       func(ctx context.Context, cmd *Command) error { return nil },
+	}
+
+	cmd.SetArgs([]string{"1", "0x10", "-3"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := cmd.GetIntSliceArg("values"); !slices.Equal(got, []int{1, 16, -3}) {
+		t.Errorf("expected [1 16 -3], got %v", got)
+	}
+	if !strings.Contains(cmd.HelpString(), "[values...]") {
+		t.Errorf("expected the usage to show the variadic argument, got:\n%s", cmd.HelpString())
+	}
+
+	cmd.SetArgs([]string{"1", "two"})
+	if err := cmd.Execute(context.Background()); err == nil || err.Error() != "invalid int value for argument values: two" {
+		t.Errorf("expected invalid value error, got %v", err)
+	}
+}
+
+func TestArgumentDefaultValue(t *testing.T) {
+	var port int
+	cmd := &Command{
+		Name: "serve",
+		Arguments: []Argument{
+			&StringArg{Name: "host", Required: true},
+			&IntArg{Name: "port", DefaultValue: 8080, AssignTo: &port},
+			&StringArg{Name: "mode", DefaultValue: "dev"},
+		},
+		Run: func(ctx context.Context, cmd *Command) error { return nil },
+	}
+
+	cmd.SetArgs([]string{"localhost"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cmd.GetIntArg("port") != 8080 || port != 8080 || cmd.GetStringArg("mode") != "dev" {
+		t.Errorf("expected defaults 8080 and dev, got %d (assigned %d) and %q", cmd.GetIntArg("port"), port, cmd.GetStringArg("mode"))
+	}
+	if cmd.HasArg("port") || !cmd.HasArg("host") {
+		t.Errorf("expected HasArg to be true only for given arguments")
+	}
+
+	cmd.SetArgs([]string{"localhost", "9000"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if port != 9000 || cmd.GetStringArg("mode") != "dev" {
+		t.Errorf("expected 9000 and default dev, got %d and %q", port, cmd.GetStringArg("mode"))
+	}
+
+	help := cmd.HelpString()
+	if !strings.Contains(help, "<host> [port=8080] [mode=dev]") || !strings.Contains(help, "(default: 8080)") {
+		t.Errorf("expected the defaults in the help, got:\n%s", help)
+	}
+}
+
+func TestArgumentChoices(t *testing.T) {
+	cmd := &Command{
+		Name:    "status",
+		MaxArgs: UnlimitedArgs,
+		Arguments: []Argument{
+			&StringArg{Name: "state", Required: true, Choices: []string{"active", "paused", "archived"}},
+			&StringSliceArg{Name: "tags", Choices: []string{"red", "blue"}},
+		},
+		Run: func(ctx context.Context, cmd *Command) error { return nil },
+	}
+
+	cmd.SetArgs([]string{"paused", "red", "blue"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cmd.GetStringArg("state") != "paused" {
+		t.Errorf("expected paused, got %q", cmd.GetStringArg("state"))
+	}
+
+	cmd.SetArgs([]string{"foo"})
+	err := cmd.Execute(context.Background())
+	if err == nil || err.Error() != `invalid value "foo" for argument state, must be one of: active, paused, archived` {
+		t.Errorf("expected invalid choice error, got %v", err)
+	}
+
+	cmd.SetArgs([]string{"active", "red", "green"})
+	err = cmd.Execute(context.Background())
+	if err == nil || err.Error() != `invalid value "green" for argument tags, must be one of: red, blue` {
+		t.Errorf("expected invalid choice error for the variadic argument, got %v", err)
+	}
+
+	if help := cmd.HelpString(); !strings.Contains(help, "(choices: active, paused, archived) (Required)") {
+		t.Errorf("expected the choices in the help, got:\n%s", help)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
 )
 
@@ -15,45 +17,87 @@ const (
 )
 
 type Command struct {
-	Name           string                                                           // Name of the command, e.g. "server", "config", etc.
-	Version        string                                                           // Version of the command, e.g. "1.0.0"
-	Usage          string                                                           // Short description of the command, e.g. "Start the server", "Show config", etc.
-	Description    string                                                           // Longer description of the command, e.g. "This command starts the server with the given configuration", "This command shows the current configuration", etc.
-	Flags          []Flag                                                           // Flags that are available for this command only
-	Arguments      []Argument                                                       // Arguments that can be passed to this command, e.g. "server start <config-file>", "config show <section>", etc.
-	MinArgs        int                                                              // Minimum number of unnamed arguments that are required for this command e.g. 0 for no minimum.
-	MaxArgs        int                                                              // Maximum number of unnamed arguments that are allowed for this command e.g. 0 for no arguments, -1 for unlimited, or a specific number like 2 for "server start <config-file> <port>
-	ConfigFile     ConfigFileSource                                                 // Configuration file reader.
-	Commands       []*Command                                                       // Subcommands that can be executed under this command, e.g. "server start", "server stop", etc.
-	Run            func(ctx context.Context, cmd *Command) error                    // Function to run when this command is executed, e.g. to start the server, show the config, etc.
-	PreRun         func(ctx context.Context, cmd *Command) (context.Context, error) // Function to run before any command is executed, e.g. to set up logging, read config files, etc.
-	PostRun        func(ctx context.Context, cmd *Command) error                    // Function to run after any command is executed, e.g. to clean up resources, log the result, etc.
-	DisableHelp    bool                                                             // Disable the automatic help command for this command
-	DisableVersion bool                                                             // Disable the automatic version command for this command
-	Suggestions    bool                                                             // Enable suggestions for unknown commands, if true then the command will try to suggest similar commands if the command is not found
-	parsedFlags    map[string]interface{}                                           // Parsed flags for this command
-	parsedArgs     map[string]interface{}                                           // Parsed arguments for this command
-	givenFlags     map[string]bool                                                  // Flags that were given and not defaulted
-	remainingArgs  []string                                                         // Remaining arguments after parsing flags and subcommands
-	globalFlags    []Flag                                                           // Global flags that are available for this command and all subcommands
-	commandChain   []*Command                                                       // Tack the command chain to the active command
+	Name              string                                                           // Name of the command, e.g. "server", "config", etc.
+	Aliases           []string                                                         // Alternative names the command can be invoked by, e.g. "rm" and "delete" for "remove"
+	Group             string                                                           // Heading the command is listed under in the help of its parent, e.g. "Management Commands"
+	Version           string                                                           // Version of the command, e.g. "1.0.0"
+	Usage             string                                                           // Short description of the command, e.g. "Start the server", "Show config", etc.
+	Description       string                                                           // Longer description of the command, e.g. "This command starts the server with the given configuration", "This command shows the current configuration", etc.
+	Examples          []string                                                         // Example command lines shown in the help, e.g. "app server start --port 8080", may span several lines
+	Flags             []Flag                                                           // Flags that are available for this command only
+	Arguments         []Argument                                                       // Arguments that can be passed to this command, e.g. "server start <config-file>", "config show <section>", etc.
+	ValidArgs         []string                                                         // Values accepted as the first positional argument, used for validation and completion, e.g. "pods", "services"
+	MinArgs           int                                                              // Minimum number of unnamed arguments that are required for this command e.g. 0 for no minimum.
+	MaxArgs           int                                                              // Maximum number of unnamed arguments that are allowed for this command e.g. 0 for no arguments, -1 for unlimited, or a specific number like 2 for "server start <config-file> <port>
+	ConfigFile        ConfigFileSource                                                 // Configuration file reader.
+	FlagDefaults      map[string]any                                                   // Defaults for flags, including those inherited from parent commands, used in place of the flag's DefaultValue for this command and its subcommands
+	EnvPrefix         string                                                           // Prefix for the environment variables of flags without EnvVars, e.g. "MYAPP" reads MYAPP_SERVER_PORT for --server-port
+	DotEnvFiles       []string                                                         // .env files loaded into the environment before flags are processed, set on the root command
+	DotEnvSearchPath  SearchPathFunc                                                   // Function to define the search paths for .env files not found as given
+	DotEnvRequired    bool                                                             // Fail if a .env file can't be found, by default missing files are ignored
+	StrictConfigKeys  bool                                                             // Fail if the configuration file contains keys that aren't used by any flag, set on the root command
+	IgnoreConfigKeys  []string                                                         // Config keys, or sections, read directly by the application and allowed when StrictConfigKeys is set
+	Commands          []*Command                                                       // Subcommands that can be executed under this command, e.g. "server start", "server stop", etc.
+	Run               func(ctx context.Context, cmd *Command) error                    // Function to run when this command is executed, e.g. to start the server, show the config, etc.
+	PreRun            func(ctx context.Context, cmd *Command) (context.Context, error) // Function to run before any command is executed, e.g. to set up logging, read config files, etc.
+	PostRun           func(ctx context.Context, cmd *Command) error                    // Function to run after any command is executed, e.g. to clean up resources, log the result, etc.
+	PostRunE          func(ctx context.Context, cmd *Command, runErr error) error      // Alternative to PostRun that receives the error from Run, the error returned replaces it, e.g. to log or wrap failures
+	PersistentPreRun  func(ctx context.Context, cmd *Command) (context.Context, error) // Function run before PreRun for this command and all its subcommands, every one in the chain is run from the root down
+	PersistentPostRun func(ctx context.Context, cmd *Command) error                    // Function run after PostRun for this command and all its subcommands, every one in the chain is run from the command up to the root
+	OnValidationError func(c *Command, err error) error                                // Function called when flag validation fails, the returned error replaces the original, e.g. to append usage guidance, returning nil ignores the failure
+	OnFlagChanged     func(name string, oldValue, newValue any)                        // Function called by ReloadFlags for each flag whose resolved value changed, e.g. to reconfigure only what changed
+	HelpFunc          func(c *Command)                                                 // Function called with the matched command in place of the built-in help, e.g. to add a banner, the one closest to the command is used
+	Output            io.Writer                                                        // Where help, version and command suggestions are written, defaults to os.Stdout, set on the root command
+	DisableHelp       bool                                                             // Disable the automatic help command for this command
+	DisableVersion    bool                                                             // Disable the automatic version command for this command
+	Suggestions       bool                                                             // Enable suggestions for unknown commands, if true then the command will try to suggest similar commands if the command is not found
+	AllowFlagPrefix   bool                                                             // Allow long flags to be abbreviated to any unique prefix, e.g. --verb for --verbose, set on the root command
+	SortFlags         bool                                                             // Sort flags in the help by name with required flags first, rather than in the order they're defined
+	CompleteNegated   bool                                                             // Include the --no- form of bool flags in shell completions, e.g. --no-verbose, set on the root command
+	StrictInit        bool                                                             // Validate the command tree with Validate before executing, to catch duplicate flags and commands early
+	Confirm           string                                                           // Prompt shown to confirm the command before it is run, e.g. "Delete all data?", a --yes flag is added to skip the prompt
+	MutuallyExclusive [][]string                                                       // Groups of flags that can't be given together on the command line, e.g. {{"json", "yaml"}}
+	RequiredOneOf     [][]string                                                       // Groups of flags where exactly one must be set, e.g. {{"file", "stdin", "url"}}
+	AggregateErrors   bool                                                             // Report every flag validation failure together rather than only the first, set on the root command
+	ShowUsageOnError  bool                                                             // Print a hint on getting help to stderr when the flags or arguments are invalid, set on the root command
+	parsedFlags       map[string]interface{}                                           // Parsed flags for this command
+	parsedArgs        map[string]interface{}                                           // Parsed arguments for this command
+	givenArgs         map[string]bool                                                  // Arguments that were given and not defaulted
+	givenFlags        map[string]bool                                                  // Flags that were given and not defaulted
+	flagSources       map[string]FlagSource                                            // Where the value of each flag came from
+	declined          bool                                                             // Whether the confirmation prompt was declined when the command was last executed
+	remainingArgs     []string                                                         // Remaining arguments after parsing flags and subcommands
+	args              []string                                                         // Arguments to parse in place of os.Args, set by SetArgs or InvokeSubcommand
+	globalFlags       []Flag                                                           // Global flags that are available for this command and all subcommands
+	commandChain      []*Command                                                       // Tack the command chain to the active command
 }
 
 func (c *Command) Execute(ctx context.Context) error {
+	c.declined = false
+	if c.StrictInit {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.loadDotEnvFiles(); err != nil {
+		return err
+	}
+
 	remainingArgs, matchedCommand, commandSequence, suggestions, err := c.processFlags()
 	if err != nil {
-		return err
+		return matchedCommand.usageError(err)
 	}
 
 	// Are we showing version information
-	if !matchedCommand.DisableVersion && matchedCommand.HasFlag("version") {
-		fmt.Printf("%s version %s\n", matchedCommand.Name, matchedCommand.Version)
+	if matchedCommand.WantsVersion() {
+		fmt.Fprintf(matchedCommand.output(), "%s version %s\n", matchedCommand.Name, matchedCommand.Version)
 		return nil
 	}
 
 	// Are we showing help
-	if !matchedCommand.DisableHelp && matchedCommand.HasFlag("help") {
-		matchedCommand.ShowHelp()
+	if matchedCommand.WantsHelp() {
+		matchedCommand.displayHelp()
 		return nil
 	}
 
@@ -63,30 +107,61 @@ func (c *Command) Execute(ctx context.Context) error {
 		return fmt.Errorf("unknown command")
 	}
 
-	// Parse named arguments
-	matchedCommand.remainingArgs, err = matchedCommand.parseArgs(remainingArgs)
-	if err != nil {
-		return err
-	}
+	// A flag such as --list can't be combined with arguments, and makes any required arguments optional
+	if name := matchedCommand.argsForbiddenBy(); name != "" {
+		if len(remainingArgs) > 0 {
+			return matchedCommand.usageError(fmt.Errorf("flag --%s can't be used with arguments", name))
+		}
+		matchedCommand.parsedArgs = make(map[string]interface{})
+		matchedCommand.givenArgs = make(map[string]bool)
+		matchedCommand.remainingArgs = nil
+	} else {
+		if err := matchedCommand.checkValidArgs(remainingArgs); err != nil {
+			return matchedCommand.usageError(err)
+		}
 
-	// Check the limits on the number of unnamed arguments
-	// Skip this check if the command has subcommands, as the remaining args might be intended for a subcommand
-	if len(matchedCommand.Commands) == 0 {
-		if matchedCommand.MaxArgs != UnlimitedArgs && len(matchedCommand.remainingArgs) > matchedCommand.MaxArgs {
-			return fmt.Errorf("too many arguments")
+		// Parse named arguments
+		matchedCommand.remainingArgs, err = matchedCommand.parseArgs(remainingArgs)
+		if err != nil {
+			return matchedCommand.usageError(err)
 		}
-		if matchedCommand.MinArgs > 0 && len(matchedCommand.remainingArgs) < matchedCommand.MinArgs {
-			return fmt.Errorf("too few arguments")
+
+		// Check the limits on the number of unnamed arguments
+		// Skip this check if the command has subcommands, as the remaining args might be intended for a subcommand,
+		// or a variadic argument, which takes the remaining args and applies the limits itself
+		if len(matchedCommand.Commands) == 0 && !matchedCommand.hasVariadicArg() {
+			if (matchedCommand.MaxArgs != UnlimitedArgs && len(matchedCommand.remainingArgs) > matchedCommand.MaxArgs) ||
+				(matchedCommand.MinArgs > 0 && len(matchedCommand.remainingArgs) < matchedCommand.MinArgs) {
+				return matchedCommand.usageError(matchedCommand.argCountError(len(remainingArgs)))
+			}
 		}
 	}
 
+	// Ask for confirmation before doing anything destructive, declining isn't a failure so the command ends without error
+	if err := matchedCommand.confirm(); err != nil {
+		if err == errConfirmationDeclined {
+			c.declined = true
+			return nil
+		}
+		return err
+	}
+
 	// Execute, PreRun, Run, and PostRun
 	var preErr error
 	var runErr error
 	var postErr error
 
+	// Run every PersistentPreRun from the root down to the command, stopping at the first error
+	for _, cmd := range commandSequence {
+		if cmd.PersistentPreRun != nil {
+			if ctx, preErr = cmd.PersistentPreRun(ctx, matchedCommand); preErr != nil {
+				break
+			}
+		}
+	}
+
 	// From the command look back towards the root for the first PreRun command
-	for i := len(commandSequence) - 1; i >= 0; i-- {
+	for i := len(commandSequence) - 1; i >= 0 && preErr == nil; i-- {
 		if commandSequence[i].PreRun != nil {
 			ctx, preErr = commandSequence[i].PreRun(ctx, matchedCommand)
 			break
@@ -111,61 +186,217 @@ func (c *Command) Execute(ctx context.Context) error {
 			if len(matchedCommand.Commands) > 0 && len(remainingArgs) > 0 {
 				runErr = fmt.Errorf("unknown command")
 			} else if len(suggestions) == 0 && !matchedCommand.DisableHelp {
-				matchedCommand.ShowHelp()
+				matchedCommand.displayHelp()
 			} else {
 				if len(remainingArgs) > 0 {
-					fmt.Printf("Unknown command: %s\n", remainingArgs[0])
+					fmt.Fprintf(matchedCommand.output(), "Unknown command: %s\n", remainingArgs[0])
 				} else {
-					fmt.Printf("Unknown command\n")
+					fmt.Fprintf(matchedCommand.output(), "Unknown command\n")
 				}
 			}
 		}
 	}
 
-	// From the command look back towards the root for the first PostRun command
+	// From the command look back towards the root for the first PostRunE or PostRun command
 	for i := len(commandSequence) - 1; i >= 0; i-- {
+		if commandSequence[i].PostRunE != nil {
+			runErr = commandSequence[i].PostRunE(ctx, matchedCommand, runErr)
+			break
+		}
 		if commandSequence[i].PostRun != nil {
 			postErr = commandSequence[i].PostRun(ctx, matchedCommand)
 			break
 		}
 	}
 
+	// Run every PersistentPostRun from the command back up to the root, so teardown mirrors setup
+	for i := len(commandSequence) - 1; i >= 0; i-- {
+		if commandSequence[i].PersistentPostRun != nil {
+			postErr = errors.Join(postErr, commandSequence[i].PersistentPostRun(ctx, matchedCommand))
+		}
+	}
+
 	return errors.Join(preErr, runErr, postErr)
 }
 
+// InvokeSubcommand runs the command tree with args in place of the command line, e.g. []string{"server", "start", "--port", "80"},
+// allowing a REPL or TUI to run the same commands as the CLI.
+func (c *Command) InvokeSubcommand(ctx context.Context, args []string) error {
+	previous := c.args
+	c.args = args
+	if c.args == nil {
+		c.args = []string{}
+	}
+	defer func() { c.args = previous }()
+
+	return c.Execute(ctx)
+}
+
+// SetArgs sets the arguments to parse in place of os.Args, excluding the program name, e.g. []string{"server", "--port", "80"}.
+// Passing nil reverts to reading os.Args, tests should prefer SetArgs over changing the global os.Args.
+func (c *Command) SetArgs(args []string) {
+	c.args = args
+}
+
+// flagDefault returns the default for the flag from the FlagDefaults of the command or its closest parent that sets one
+func (c *Command) flagDefault(flag Flag) (any, bool) {
+	chain := c.commandChain
+	if len(chain) == 0 {
+		chain = []*Command{c}
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		if value, ok := chain[i].FlagDefaults[flag.getName()]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// flagEnvVars returns the environment variables for a flag, if the flag doesn't set EnvVars the name is derived from
+// the EnvPrefix closest to the command, e.g. MYAPP_SERVER_PORT for --server-port. The help, version and yes flags added
+// by the library are never read from the environment.
+func (c *Command) flagEnvVars(flag Flag) []string {
+	if envVars := flag.getEnvVars(); len(envVars) > 0 || flag.isBuiltin() {
+		return envVars
+	}
+
+	chain := c.commandChain
+	if len(chain) == 0 {
+		chain = []*Command{c}
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].EnvPrefix != "" {
+			name := strings.TrimSuffix(chain[i].EnvPrefix, "_") + "_" + strings.ReplaceAll(flag.getName(), "-", "_")
+			return []string{strings.ToUpper(name)}
+		}
+	}
+
+	return nil
+}
+
+// output returns the writer for help, version and suggestions, the Output of the command or of the root command, or os.Stdout
+func (c *Command) output() io.Writer {
+	if c.Output != nil {
+		return c.Output
+	}
+	if root := c.GetRootCmd(); root.Output != nil {
+		return root.Output
+	}
+	return os.Stdout
+}
+
+// matchesName reports whether name is the name of the command or one of its aliases
+func (c *Command) matchesName(name string) bool {
+	if name == c.Name {
+		return true
+	}
+	for _, alias := range c.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFlag checks if the command defines a flag with the given name
+func (c *Command) hasFlag(name string) bool {
+	for _, flag := range c.Flags {
+		if flag.getName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// combinedFlags returns the inherited global flags followed by the flags of the command, a flag of the command replaces
+// an inherited global flag with the same name
+func (c *Command) combinedFlags() []Flag {
+	flags := make([]Flag, 0, len(c.globalFlags)+len(c.Flags))
+	for _, flag := range c.globalFlags {
+		if !c.hasFlag(flag.getName()) {
+			flags = append(flags, flag)
+		}
+	}
+	return append(flags, c.Flags...)
+}
+
+// inheritsFlag returns true if a global flag with the name is inherited from a parent command
+func (c *Command) inheritsFlag(name string) bool {
+	for _, flag := range c.globalFlags {
+		if flag.getName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// usesFlagName returns true if a flag of the command, or a global flag it inherits, has the name or alias
+func (c *Command) usesFlagName(name string) bool {
+	for _, flags := range [][]Flag{c.globalFlags, c.Flags} {
+		for _, flag := range flags {
+			if flag.getName() == name || slices.Contains(flag.getAliases(), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flagGiven returns true if the flag was given rather than defaulted and, for a bool flag, is true, so --stdin=false
+// and --no-stdin don't count as given
+func (c *Command) flagGiven(name string) bool {
+	if !c.givenFlags[name] {
+		return false
+	}
+	if b, ok := c.parsedFlags[name].(bool); ok {
+		return b
+	}
+	return true
+}
+
 func (c *Command) ReloadFlags() error {
-	_, _, _, _, err := c.processFlags()
+	previous := make(map[*Command]map[string]interface{})
+	c.snapshotParsedFlags(previous)
+
+	_, matchedCommand, commandSequence, _, err := c.processFlags()
 	if err != nil {
 		return err
 	}
 
+	if oldFlags, ok := previous[matchedCommand]; ok {
+		matchedCommand.notifyFlagChanges(commandSequence, oldFlags)
+	}
+
 	return nil
 }
 
 func (c *Command) processFlags() ([]string, *Command, []*Command, []string, error) {
-	args := os.Args
-	if len(args) > 0 {
-		args = args[1:]
+	var args []string
+	if c.args != nil {
+		args = c.args
+	} else if len(os.Args) > 0 {
+		args = os.Args[1:]
 	}
 
 	// Match subcommands and collect flags in a single pass
 	remainingArgs, matchedCommand, commandSequence, suggestions := c.matchSubcommands(args)
 	matchedCommand.commandChain = commandSequence
 
-	// Inject help and version flags
-	if !matchedCommand.DisableHelp {
+	// Inject help and version flags, once as the command can be processed more than once, a subcommand uses the help
+	// flag of its parent if the parent already has one
+	if !matchedCommand.DisableHelp && !matchedCommand.hasFlag("help") && !matchedCommand.inheritsFlag("help") {
 		matchedCommand.Flags = append(matchedCommand.Flags, &BoolFlag{
-			Name:         "help",
-			Aliases:      []string{"h"},
-			Usage:        "Show help for the command",
-			DefaultValue: true,
-			Global:       true,
-			HideDefault:  true,
-			HideType:     true,
+			Name:        "help",
+			Aliases:     []string{"h"},
+			Usage:       "Show help for the command",
+			Global:      true,
+			HideDefault: true,
+			HideType:    true,
+			builtin:     true,
 		})
 	}
 
-	if c == matchedCommand && !c.DisableVersion && c.Version != "" {
+	if c == matchedCommand && !c.DisableVersion && c.Version != "" && !c.hasFlag("version") {
 		matchedCommand.Flags = append(matchedCommand.Flags, &BoolFlag{
 			Name:         "version",
 			Aliases:      []string{"v"},
@@ -173,28 +404,56 @@ func (c *Command) processFlags() ([]string, *Command, []*Command, []string, erro
 			DefaultValue: true,
 			HideDefault:  true,
 			HideType:     true,
+			builtin:      true,
+		})
+	}
+
+	// The -y alias is only added if the command doesn't already use it for a flag of its own
+	if matchedCommand.Confirm != "" && !matchedCommand.hasFlag("yes") {
+		var aliases []string
+		if !matchedCommand.usesFlagName("y") {
+			aliases = []string{"y"}
+		}
+		matchedCommand.Flags = append(matchedCommand.Flags, &BoolFlag{
+			Name:        "yes",
+			Aliases:     aliases,
+			Usage:       "Skip the confirmation prompt",
+			HideDefault: true,
+			HideType:    true,
+			builtin:     true,
 		})
 	}
 
 	// Parse the command line flags first
 	remainingArgs, parseErr := matchedCommand.parseFlags(remainingArgs)
 	if parseErr != nil {
-		return nil, nil, nil, nil, parseErr
+		return nil, matchedCommand, nil, nil, parseErr
 	}
 
-	// Merge the global and command flags
-	combinedFlags := make([]Flag, 0, len(matchedCommand.globalFlags)+len(matchedCommand.Flags))
-	combinedFlags = append(combinedFlags, matchedCommand.globalFlags...)
-	combinedFlags = append(combinedFlags, matchedCommand.Flags...)
+	// Remember the flags given on the command line, before the other sources are applied
+	cliFlags := make(map[string]bool, len(matchedCommand.parsedFlags))
+	matchedCommand.flagSources = make(map[string]FlagSource, len(matchedCommand.parsedFlags))
+	for name := range matchedCommand.parsedFlags {
+		cliFlags[name] = true
+		matchedCommand.flagSources[name] = SourceCLI
+	}
+
+	combinedFlags := matchedCommand.combinedFlags()
 
 	// For flags that are not set on the command line see if they can be set from an environment variable
 	for _, flag := range combinedFlags {
 		if _, ok := matchedCommand.parsedFlags[flag.getName()]; !ok {
-			flag.setFromEnvVar(matchedCommand.parsedFlags)
+			if err := flag.setFromEnvVar(matchedCommand.parsedFlags, matchedCommand.flagEnvVars(flag)); err != nil {
+				return nil, matchedCommand, nil, nil, err
+			}
+			if _, ok := matchedCommand.parsedFlags[flag.getName()]; ok {
+				matchedCommand.flagSources[flag.getName()] = SourceEnv
+			}
 		}
 	}
 
 	// For flags that are still not set, check if they can be set from a config
+	var configErr error
 	if c.ConfigFile != nil {
 
 		// Ask the config file to load
@@ -202,38 +461,59 @@ func (c *Command) processFlags() ([]string, *Command, []*Command, []string, erro
 		if err := c.ConfigFile.LoadData(); err != nil {
 			// No config file is not a fatal error
 			if err != ConfigFileNotFoundError {
-				return nil, nil, nil, nil, err
+				return nil, matchedCommand, nil, nil, err
 			}
 			hasConfigFile = false
 		}
 
 		if hasConfigFile {
+			if c.StrictConfigKeys {
+				configErr = c.checkConfigKeys()
+			}
+
 			for _, flag := range combinedFlags {
 				if _, ok := matchedCommand.parsedFlags[flag.getName()]; !ok {
 					cfgPaths := flag.configPaths()
 					if len(cfgPaths) > 0 {
 						for _, path := range cfgPaths {
 							if v, ok := c.ConfigFile.GetValue(path); ok {
+								// A table can only set a map flag, each key becoming a key=value pair
+								if table, isTable := v.(map[string]interface{}); isTable {
+									if flag.valueType().Kind() == reflect.Map {
+										for key, val := range table {
+											flag.parseString(key+"="+configValueString(val), true, matchedCommand.parsedFlags)
+										}
+									}
+									continue
+								}
+
 								isSlice := reflect.TypeOf(v).Kind() == reflect.Slice
 								if isSlice == flag.isSlice() {
 									if isSlice {
-										switch vals := v.(type) {
-										case []interface{}:
-											for _, val := range vals {
-												flag.parseString(fmt.Sprintf("%v", val), true, matchedCommand.parsedFlags)
-											}
-										case []string:
-											for _, val := range vals {
-												flag.parseString(fmt.Sprintf("%v", val), true, matchedCommand.parsedFlags)
+										// Parse into a separate map so a value that fails part way through isn't kept, as with environment variables
+										parsed := make(map[string]interface{})
+										if value, ok := matchedCommand.parsedFlags[flag.getName()]; ok {
+											parsed[flag.getName()] = value
+										}
+										valid := true
+										for i, n := 0, reflect.ValueOf(v).Len(); i < n && valid; i++ {
+											valid = flag.parseString(configValueString(reflect.ValueOf(v).Index(i).Interface()), true, parsed) == nil
+										}
+										if valid {
+											if value, ok := parsed[flag.getName()]; ok {
+												matchedCommand.parsedFlags[flag.getName()] = value
 											}
-										default:
 										}
 									} else {
-										flag.parseString(fmt.Sprintf("%v", v), true, matchedCommand.parsedFlags)
+										flag.parseString(configValueString(v), true, matchedCommand.parsedFlags)
 									}
 								}
 							}
 						}
+
+						if _, ok := matchedCommand.parsedFlags[flag.getName()]; ok {
+							matchedCommand.flagSources[flag.getName()] = SourceConfig
+						}
 					}
 				}
 			}
@@ -244,32 +524,141 @@ func (c *Command) processFlags() ([]string, *Command, []*Command, []string, erro
 	matchedCommand.givenFlags = make(map[string]bool)
 	for _, flag := range combinedFlags {
 		if _, ok := matchedCommand.parsedFlags[flag.getName()]; !ok {
-			flag.setFromDefault(matchedCommand.parsedFlags)
+			if value, ok := matchedCommand.flagDefault(flag); ok {
+				if err := flag.setDefault(value, matchedCommand.parsedFlags); err != nil {
+					return nil, matchedCommand, nil, nil, err
+				}
+			} else {
+				flag.setFromDefault(matchedCommand.parsedFlags)
+			}
+			matchedCommand.flagSources[flag.getName()] = SourceDefault
 		} else {
 			matchedCommand.givenFlags[flag.getName()] = true
 		}
+		flag.makeUnique(matchedCommand.parsedFlags)
 	}
 
-	// Check if we're showing help or version - if so, skip required flag validation
-	showingHelp := !matchedCommand.DisableHelp && matchedCommand.givenFlags["help"]
-	showingVersion := !matchedCommand.DisableVersion && matchedCommand.givenFlags["version"]
-
 	// Check required flags are present and pass any validation (skip if showing help or version)
-	if !showingHelp && !showingVersion {
+	if !matchedCommand.WantsHelp() && !matchedCommand.WantsVersion() {
+		// Stop at the first failure unless all of them are to be reported together
+		var errs []error
+		addErr := func(err error) bool {
+			if err != nil {
+				errs = append(errs, err)
+			}
+			return len(errs) > 0 && !c.GetRootCmd().AggregateErrors
+		}
+
+		stop := addErr(configErr) ||
+			addErr(checkMutuallyExclusive(commandSequence, cliFlags)) ||
+			addErr(checkRequiredOneOf(commandSequence, matchedCommand))
 		for _, flag := range combinedFlags {
-			if _, ok := matchedCommand.parsedFlags[flag.getName()]; !ok {
-				if flag.isRequired() {
-					return nil, nil, nil, nil, fmt.Errorf("required flag '%s' not set", flag.getName())
-				}
-			} else if err := flag.validateFlag(matchedCommand); err != nil {
-				return nil, nil, nil, nil, err
+			if stop {
+				break
 			}
+			stop = addErr(matchedCommand.checkFlag(flag))
+		}
+
+		// The hook can return nil to ignore the failure, the command is then run as if the flags were valid
+		var err error
+		if len(errs) == 1 {
+			err = matchedCommand.validationError(commandSequence, errs[0])
+		} else if len(errs) > 1 {
+			err = matchedCommand.validationError(commandSequence, errors.Join(errs...))
+		}
+		if err != nil {
+			return nil, matchedCommand, nil, nil, err
+		}
+
+		// Pass the final values to OnSet now they've been checked, so it's called once for each flag
+		for _, flag := range combinedFlags {
+			flag.notifySet(matchedCommand.parsedFlags)
 		}
 	}
 
 	return remainingArgs, matchedCommand, commandSequence, suggestions, nil
 }
 
+// checkFlag checks a required flag is set and that the value of a set flag is valid
+func (c *Command) checkFlag(flag Flag) error {
+	if _, ok := c.parsedFlags[flag.getName()]; !ok {
+		if flag.isRequired() {
+			return fmt.Errorf("required flag '%s' not set", flag.getName())
+		}
+		return nil
+	}
+
+	if err := flag.validateRequires(c); err != nil {
+		return err
+	}
+	if err := flag.validateChoice(c); err != nil {
+		return err
+	}
+	return flag.validateFlag(c)
+}
+
+// usageError prints a hint on how to get help for the command to stderr if ShowUsageOnError is set on the root command,
+// err is returned unchanged
+func (c *Command) usageError(err error) error {
+	if c.GetRootCmd().ShowUsageOnError && !c.DisableHelp {
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", strings.Join(c.commandPath(), " "))
+	}
+	return err
+}
+
+// argCountError describes the number of arguments the command expects, counting both the named and unnamed arguments,
+// e.g. "server start: expected between 1 and 2 arguments, got 3 (usage: app server start <config> [args...])"
+func (c *Command) argCountError(got int) error {
+	path := c.commandPath()
+	name := strings.Join(path, " ")
+	if len(path) > 1 {
+		name = strings.Join(path[1:], " ")
+	}
+
+	// Only the required named arguments count towards the minimum
+	minArgs := max(c.MinArgs, 0)
+	for _, arg := range c.Arguments {
+		if arg.isRequired() {
+			minArgs++
+		}
+	}
+	maxArgs := len(c.Arguments) + c.MaxArgs
+
+	var expected string
+	switch {
+	case c.MaxArgs == UnlimitedArgs || maxArgs < minArgs:
+		expected = fmt.Sprintf("at least %d %s", minArgs, pluralArguments(minArgs))
+	case maxArgs == 0:
+		expected = "no arguments"
+	case minArgs == maxArgs:
+		expected = fmt.Sprintf("%d %s", maxArgs, pluralArguments(maxArgs))
+	case minArgs == 0:
+		expected = fmt.Sprintf("at most %d %s", maxArgs, pluralArguments(maxArgs))
+	default:
+		expected = fmt.Sprintf("between %d and %d arguments", minArgs, maxArgs)
+	}
+
+	return fmt.Errorf("%s: expected %s, got %d (usage: %s)", name, expected, got,
+		strings.TrimSpace(strings.Join(c.commandPath(), " ")+" "+c.argsUsage()))
+}
+
+func pluralArguments(n int) string {
+	if n == 1 {
+		return "argument"
+	}
+	return "arguments"
+}
+
+// validationError passes a validation failure to the OnValidationError hook closest to the command
+func (c *Command) validationError(commandSequence []*Command, err error) error {
+	for i := len(commandSequence) - 1; i >= 0; i-- {
+		if commandSequence[i].OnValidationError != nil {
+			return commandSequence[i].OnValidationError(c, err)
+		}
+	}
+	return err
+}
+
 // matchSubcommands walks through args to find the deepest matching subcommand
 // and separates flags from commands and positional arguments in a single pass
 func (c *Command) matchSubcommands(args []string) ([]string, *Command, []*Command, []string) {
@@ -293,8 +682,8 @@ func (c *Command) matchSubcommands(args []string) ([]string, *Command, []*Comman
 			break
 		}
 
-		// Check if it's a flag
-		if strings.HasPrefix(arg, "-") {
+		// Check if it's a flag, negative numbers such as -5 are positional arguments
+		if c.isFlagArgInCommand(arg, current) {
 			// Collect the flag and its value (if any)
 			flagWithValue := c.collectFlag(arg, args, &i, current)
 			flags = append(flags, flagWithValue...)
@@ -302,7 +691,7 @@ func (c *Command) matchSubcommands(args []string) ([]string, *Command, []*Comman
 			// Check if it's a subcommand
 			found := false
 			for _, subcmd := range current.Commands {
-				if arg == subcmd.Name {
+				if subcmd.matchesName(arg) {
 					// Save the global flags from the parent command
 					for _, flag := range current.Flags {
 						if flag.isGlobal() {
@@ -361,11 +750,22 @@ func (c *Command) collectFlag(arg string, args []string, i *int, current *Comman
 
 		// Determine if we need to consume next arg as value
 		flagObj := c.lookupFlagInCommand(flagName, current)
+
+		// A --no-flag form of a bool flag never takes a value
+		if name, ok := strings.CutPrefix(flagName, "no-"); ok && flagObj == nil {
+			if _, isBool := c.lookupFlagInCommand(name, current).(*BoolFlag); isBool {
+				return result
+			}
+		}
+
+		if flagObj == nil && c.AllowFlagPrefix {
+			flagObj = c.lookupFlagPrefixInCommand(flagName, current)
+		}
 		if flagObj != nil {
-			// Check if it's a bool flag
-			if _, isBool := flagObj.(*BoolFlag); !isBool {
+			// Check if it's a flag that needs a value, e.g. not a bool flag
+			if flagObj.takesValue() {
 				// Non-bool flag needs a value
-				if *i+1 < len(args) && !strings.HasPrefix(args[*i+1], "-") && args[*i+1] != "--" {
+				if *i+1 < len(args) && !c.isFlagArgInCommand(args[*i+1], current) {
 					result = append(result, args[*i+1])
 					*i++
 				}
@@ -381,9 +781,9 @@ func (c *Command) collectFlag(arg string, args []string, i *int, current *Comman
 			lastChar := string(flagChars[len(flagChars)-1])
 			flagObj := c.lookupFlagInCommand(lastChar, current)
 			if flagObj != nil {
-				if _, isBool := flagObj.(*BoolFlag); !isBool {
+				if flagObj.takesValue() {
 					// Non-bool flag needs a value
-					if *i+1 < len(args) && !strings.HasPrefix(args[*i+1], "-") && args[*i+1] != "--" {
+					if *i+1 < len(args) && !c.isFlagArgInCommand(args[*i+1], current) {
 						result = append(result, args[*i+1])
 						*i++
 					}
@@ -395,6 +795,15 @@ func (c *Command) collectFlag(arg string, args []string, i *int, current *Comman
 	return result
 }
 
+// isFlagArgInCommand returns true if arg is a flag for the command rather than a value or positional argument,
+// a negative number is only a flag if the command has a short flag named after its first digit
+func (c *Command) isFlagArgInCommand(arg string, current *Command) bool {
+	if isNegativeNumber(arg) {
+		return c.lookupFlagInCommand(arg[1:2], current) != nil
+	}
+	return strings.HasPrefix(arg, "-")
+}
+
 // lookupFlagInCommand searches for a flag in the current command and its ancestors' global flags
 func (c *Command) lookupFlagInCommand(flagName string, current *Command) Flag {
 	// Check in current command's flags
@@ -438,20 +847,44 @@ func (c *Command) lookupFlagInCommand(flagName string, current *Command) Flag {
 	return nil
 }
 
+// lookupFlagPrefixInCommand searches for the flag uniquely matching an abbreviated long flag name
+func (c *Command) lookupFlagPrefixInCommand(prefix string, current *Command) Flag {
+	longFlags := make(map[string]Flag)
+	shortFlags := make(map[string]Flag)
+
+	for _, flag := range c.Flags {
+		if flag.isGlobal() {
+			flag.register(longFlags, shortFlags)
+		}
+	}
+	for _, flag := range current.globalFlags {
+		flag.register(longFlags, shortFlags)
+	}
+	for _, flag := range current.Flags {
+		flag.register(longFlags, shortFlags)
+	}
+
+	flag, err := matchFlagPrefix(prefix, longFlags)
+	if err != nil {
+		return nil
+	}
+	return flag
+}
+
 // Args returns the list of arguments that were passed to the command and have not been consumed by subcommands, flags and arguments
 func (c *Command) GetArgs() []string {
 	return c.remainingArgs
 }
 
-// HasFlag checks if a flag with the given name was set for this command
+// HasFlag checks if a flag with the given name, or alias, was set for this command
 func (c *Command) HasFlag(name string) bool {
-	_, ok := c.givenFlags[name]
+	_, ok := c.givenFlags[c.resolveFlagKey(name)]
 	return ok
 }
 
+// HasArg checks if an argument with the given name was given for this command, an argument using its default wasn't
 func (c *Command) HasArg(name string) bool {
-	_, ok := c.parsedArgs[name]
-	return ok
+	return c.givenArgs[name]
 }
 
 func (c *Command) GetRootCmd() *Command {
@@ -460,3 +893,13 @@ func (c *Command) GetRootCmd() *Command {
 	}
 	return c
 }
+
+// WantsHelp reports whether help was requested on the command line, honoring DisableHelp
+func (c *Command) WantsHelp() bool {
+	return !c.DisableHelp && c.givenFlags["help"]
+}
+
+// WantsVersion reports whether version information was requested on the command line, honoring DisableVersion
+func (c *Command) WantsVersion() bool {
+	return !c.DisableVersion && c.givenFlags["version"]
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// structFlagTag holds the flag settings read from the tags of a struct field
+type structFlagTag struct {
+	name         string   // Name of the flag from the flag tag or the field name
+	usage        string   // Usage from the usage tag
+	envVars      []string // Environment variables from the env tag, separated by commas
+	defaultValue string   // Default from the default tag, parsed as if given on the command line
+	hasDefault   bool     // Whether the field has a default tag
+}
+
+// structFlagBinders creates the flag for each type of struct field that can be bound, keyed by the field type
+var structFlagBinders = map[reflect.Type]func(reflect.Value, structFlagTag) (Flag, error){
+	reflect.TypeFor[string]():            bindStructFlag[string],
+	reflect.TypeFor[int]():               bindStructFlag[int],
+	reflect.TypeFor[int8]():              bindStructFlag[int8],
+	reflect.TypeFor[int16]():             bindStructFlag[int16],
+	reflect.TypeFor[int32]():             bindStructFlag[int32],
+	reflect.TypeFor[int64]():             bindStructFlag[int64],
+	reflect.TypeFor[uint]():              bindStructFlag[uint],
+	reflect.TypeFor[uint8]():             bindStructFlag[uint8],
+	reflect.TypeFor[uint16]():            bindStructFlag[uint16],
+	reflect.TypeFor[uint32]():            bindStructFlag[uint32],
+	reflect.TypeFor[uint64]():            bindStructFlag[uint64],
+	reflect.TypeFor[float32]():           bindStructFlag[float32],
+	reflect.TypeFor[float64]():           bindStructFlag[float64],
+	reflect.TypeFor[bool]():              bindStructFlag[bool],
+	reflect.TypeFor[[]string]():          bindStructFlag[[]string],
+	reflect.TypeFor[[]int]():             bindStructFlag[[]int],
+	reflect.TypeFor[[]int8]():            bindStructFlag[[]int8],
+	reflect.TypeFor[[]int16]():           bindStructFlag[[]int16],
+	reflect.TypeFor[[]int32]():           bindStructFlag[[]int32],
+	reflect.TypeFor[[]int64]():           bindStructFlag[[]int64],
+	reflect.TypeFor[[]uint]():            bindStructFlag[[]uint],
+	reflect.TypeFor[[]uint8]():           bindStructFlag[[]uint8],
+	reflect.TypeFor[[]uint16]():          bindStructFlag[[]uint16],
+	reflect.TypeFor[[]uint32]():          bindStructFlag[[]uint32],
+	reflect.TypeFor[[]uint64]():          bindStructFlag[[]uint64],
+	reflect.TypeFor[[]float32]():         bindStructFlag[[]float32],
+	reflect.TypeFor[[]float64]():         bindStructFlag[[]float64],
+	reflect.TypeFor[map[string]string](): bindStructFlag[map[string]string],
+	reflect.TypeFor[net.IP]():            bindStructFlag[net.IP],
+	reflect.TypeFor[*net.IPNet]():        bindStructFlag[*net.IPNet],
+	reflect.TypeFor[[]net.IP]():          bindStructFlag[[]net.IP],
+	reflect.TypeFor[[]*net.IPNet]():      bindStructFlag[[]*net.IPNet],
+	reflect.TypeFor[*url.URL]():          bindStructFlag[*url.URL],
+	reflect.TypeFor[[]*url.URL]():        bindStructFlag[[]*url.URL],
+}
+
+// BindStruct adds a flag to the command for each exported field of the struct ptr points to, the flag is assigned to
+// the field when parsed.
+//
+// The flag name is taken from the flag tag or else the field name in kebab case, e.g. PoolSize becomes pool-size, and
+// fields tagged `flag:"-"` are skipped. The usage tag sets the usage, the env tag the environment variables, separated
+// by commas, and the default tag the default value, parsed as if given on the command line. Without a default tag the
+// value of the field when BindStruct is called is the default. Embedded structs add their fields as flags, other struct
+// fields add theirs with the name of the field as a prefix, e.g. --db-host. If a field can't be bound or a flag name or
+// alias is already in use an error is returned and no flags are added.
+func (c *Command) BindStruct(ptr any) error {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("command '%s': BindStruct needs a pointer to a struct, got %T", c.Name, ptr)
+	}
+
+	// Create all the flags first so the command is unchanged if any field can't be bound
+	flags, err := structFlags(rv.Elem(), "")
+	if err != nil {
+		return fmt.Errorf("command '%s': %w", c.Name, err)
+	}
+
+	// Check the flags against those of the command and each other before adding any of them
+	combined := append([]Flag{}, c.Flags...)
+	for _, flag := range flags {
+		if err := c.checkFlagConflictWith(combined, flag); err != nil {
+			return err
+		}
+		combined = append(combined, flag)
+	}
+	c.Flags = combined
+
+	return nil
+}
+
+// structFlags returns the flags for the fields of the struct, prefix is added to the start of the flag names
+func structFlags(v reflect.Value, prefix string) ([]Flag, error) {
+	var flags []Flag
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, hasName := field.Tag.Lookup("flag")
+		if name == "-" {
+			continue
+		}
+
+		binder, canBind := structFlagBinders[field.Type]
+		if !canBind && field.Type.Kind() == reflect.Struct && (field.Anonymous || field.IsExported()) {
+			nestedPrefix := prefix
+			if !field.Anonymous || hasName {
+				if !hasName {
+					name = kebabCase(field.Name)
+				}
+				nestedPrefix += name + "-"
+			}
+
+			nested, err := structFlags(v.Field(i), nestedPrefix)
+			if err != nil {
+				return nil, err
+			}
+			flags = append(flags, nested...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if !canBind {
+			return nil, fmt.Errorf("field %s has unsupported flag type %s", field.Name, field.Type)
+		}
+
+		if !hasName {
+			name = kebabCase(field.Name)
+		}
+		tag := structFlagTag{name: prefix + name, usage: field.Tag.Get("usage")}
+		tag.defaultValue, tag.hasDefault = field.Tag.Lookup("default")
+		if env := field.Tag.Get("env"); env != "" {
+			for _, envVar := range strings.Split(env, ",") {
+				tag.envVars = append(tag.envVars, strings.TrimSpace(envVar))
+			}
+		}
+
+		flag, err := binder(v.Field(i), tag)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, flag)
+	}
+
+	return flags, nil
+}
+
+// bindStructFlag returns a flag assigned to the struct field
+func bindStructFlag[T any](field reflect.Value, tag structFlagTag) (Flag, error) {
+	assignTo := field.Addr().Interface().(*T)
+	flag := &FlagTyped[T]{
+		Name:         tag.name,
+		Usage:        tag.usage,
+		EnvVars:      tag.envVars,
+		DefaultValue: *assignTo,
+		AssignTo:     assignTo,
+	}
+
+	if tag.hasDefault {
+		// Parse the default without the flag assigned so the field keeps its value until the flags are parsed
+		parse := *flag
+		parse.AssignTo = nil
+		parsed := make(map[string]interface{})
+
+		values := []string{tag.defaultValue}
+		if flag.isSlice() || flag.isMap() {
+			values = strings.Split(tag.defaultValue, ",")
+		}
+		for _, value := range values {
+			if err := parse.parseString(strings.TrimSpace(value), true, parsed); err != nil {
+				return nil, fmt.Errorf("default for flag --%s: %w", tag.name, err)
+			}
+		}
+		flag.DefaultValue, _ = parsed[tag.name].(T)
+	}
+
+	return flag, nil
+}
+
+// kebabCase converts a field name to a flag name, e.g. PoolSize to pool-size and APIKey to api-key
+func kebabCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word at an upper case letter following a lower case one, or ending a run of upper case letters
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
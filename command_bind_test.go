@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type bindLogging struct {
+	Level string `usage:"Log level" default:"info"`
+}
+
+type bindDatabase struct {
+	Host string `usage:"Database host"`
+	Port int    `default:"5432"`
+}
+
+type bindConfig struct {
+	bindLogging
+	Listen      string            `flag:"listen" usage:"Address to listen on" env:"BIND_LISTEN, BIND_ADDR" default:":8080"`
+	PoolSize    int               `usage:"Connection pool size"`
+	Verbose     bool              `default:"true"`
+	Tags        []string          `default:"a,b"`
+	Labels      map[string]string `flag:"label"`
+	APIEndpoint *url.URL
+	DB          bindDatabase `flag:"db"`
+	Ignored     string       `flag:"-"`
+	internal    string
+}
+
+func TestBindStruct(t *testing.T) {
+	config := bindConfig{PoolSize: 10, internal: "x"}
+
+	cmd := &Command{Name: "test", Run: func(ctx context.Context, cmd *Command) error { return nil }}
+	if err := cmd.BindStruct(&config); err != nil {
+		t.Fatalf("BindStruct failed: %v", err)
+	}
+
+	var names []string
+	for _, flag := range cmd.Flags {
+		names = append(names, flag.getName())
+	}
+	want := []string{"level", "listen", "pool-size", "verbose", "tags", "label", "api-endpoint", "db-host", "db-port"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("flags = %v, want %v", names, want)
+	}
+
+	listen := cmd.Flags[1].(*StringFlag)
+	if listen.Usage != "Address to listen on" || !reflect.DeepEqual(listen.EnvVars, []string{"BIND_LISTEN", "BIND_ADDR"}) {
+		t.Errorf("listen flag = %+v", listen)
+	}
+
+	// The field keeps its value until the flags are parsed
+	if config.Listen != "" {
+		t.Errorf("Listen = %q before parsing, want it unchanged", config.Listen)
+	}
+
+	t.Setenv("BIND_ADDR", ":9000")
+	os.Args = []string{"test", "--db-host", "db.local", "--label", "env=prod", "--api-endpoint", "https://api.example.com", "--no-verbose"}
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if config.Level != "info" || config.Listen != ":9000" || config.PoolSize != 10 || config.Verbose {
+		t.Errorf("scalars = %+v", config)
+	}
+	if !reflect.DeepEqual(config.Tags, []string{"a", "b"}) || config.Labels["env"] != "prod" {
+		t.Errorf("tags = %v, labels = %v", config.Tags, config.Labels)
+	}
+	if config.APIEndpoint == nil || config.APIEndpoint.Host != "api.example.com" {
+		t.Errorf("APIEndpoint = %v", config.APIEndpoint)
+	}
+	if config.DB.Host != "db.local" || config.DB.Port != 5432 {
+		t.Errorf("DB = %+v", config.DB)
+	}
+}
+
+func TestBindStructErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ptr     any
+		wantErr string
+	}{
+		{"not a pointer", bindConfig{}, "needs a pointer to a struct"},
+		{"not a struct", new(string), "needs a pointer to a struct"},
+		{"unsupported type", &struct{ Ch chan int }{}, "field Ch has unsupported flag type chan int"},
+		{"invalid default", &struct {
+			Port int `default:"http"`
+		}{}, "default for flag --port"},
+		{"conflict", &struct {
+			Host string
+			Name string `flag:"existing"`
+		}{}, "conflicts with flag 'existing'"},
+		{"conflict between fields", &struct {
+			Host   string
+			Server struct{ Port int }
+			Other  int `flag:"server-port"`
+		}{}, "flag 'server-port' conflicts with flag 'server-port'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Command{Name: "test", Flags: []Flag{&StringFlag{Name: "existing"}}}
+			err := cmd.BindStruct(tt.ptr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("BindStruct() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if len(cmd.Flags) != 1 {
+				t.Errorf("flags were added on error: %d", len(cmd.Flags))
+			}
+		})
+	}
+}
+
+func TestKebabCase(t *testing.T) {
+	tests := map[string]string{
+		"Port":      "port",
+		"PoolSize":  "pool-size",
+		"APIKey":    "api-key",
+		"APIURL":    "apiurl",
+		"ListenV6":  "listen-v6",
+		"TLSConfig": "tls-config",
+	}
+	for name, want := range tests {
+		if got := kebabCase(name); got != want {
+			t.Errorf("kebabCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cli
+
+// Config returns a typed accessor for the command's configuration file, for reading keys that aren't bound to flags.
+// If the command has no configuration file an empty configuration is returned, so the getters return zero values.
+func (c *Command) Config() ConfigFileTyped {
+	source := c.ConfigFile
+	if source == nil {
+		source = c.GetRootCmd().ConfigFile
+	}
+	if source == nil {
+		return NewTypedConfigObject()
+	}
+	if typed, ok := source.(ConfigFileTyped); ok {
+		return typed
+	}
+	return NewTypedConfigFile(source)
+}
+
+// Config getters, the path is a dotted path into the configuration file, e.g. "server.tls.cert"
+func (c *Command) ConfigString(path string) string {
+	return c.Config().GetString(path)
+}
+
+func (c *Command) ConfigInt(path string) int {
+	return c.Config().GetInt(path)
+}
+
+func (c *Command) ConfigInt64(path string) int64 {
+	return c.Config().GetInt64(path)
+}
+
+func (c *Command) ConfigUint(path string) uint {
+	return c.Config().GetUint(path)
+}
+
+func (c *Command) ConfigUint64(path string) uint64 {
+	return c.Config().GetUint64(path)
+}
+
+func (c *Command) ConfigFloat64(path string) float64 {
+	return c.Config().GetFloat64(path)
+}
+
+func (c *Command) ConfigBool(path string) bool {
+	return c.Config().GetBool(path)
+}
+
+func (c *Command) ConfigStringSlice(path string) []string {
+	return c.Config().GetStringSlice(path)
+}
+
+func (c *Command) ConfigIntSlice(path string) []int {
+	return c.Config().GetIntSlice(path)
+}
+
+func (c *Command) ConfigStringMap(path string) map[string]string {
+	return c.Config().GetStringMap(path)
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCommandConfigGetters(t *testing.T) {
+	cfg, _ := newJSONConfigBase(t, `{
+		"server": {"port": 8080, "tls": {"enabled": true, "cert": "cert.pem"}},
+		"limits": {"ratio": 0.5, "hosts": ["a", "b"], "ports": [80, 443]},
+		"labels": {"env": "prod", "replicas": 3}
+	}`)
+
+	var checked bool
+	root := &Command{
+		Name:       "app",
+		ConfigFile: cfg,
+		Flags:      []Flag{&IntFlag{Name: "port", ConfigPath: []string{"server.port"}}},
+		Commands: []*Command{
+			{
+				Name: "serve",
+				Run: func(ctx context.Context, cmd *Command) error {
+					checked = true
+					if got := cmd.ConfigString("server.tls.cert"); got != "cert.pem" {
+						t.Errorf("ConfigString: got %q", got)
+					}
+					if !cmd.ConfigBool("server.tls.enabled") {
+						t.Error("ConfigBool: expected true")
+					}
+					if got := cmd.ConfigInt("server.port"); got != 8080 {
+						t.Errorf("ConfigInt: got %d", got)
+					}
+					if got := cmd.ConfigFloat64("limits.ratio"); got != 0.5 {
+						t.Errorf("ConfigFloat64: got %v", got)
+					}
+					if got := cmd.ConfigStringSlice("limits.hosts"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+						t.Errorf("ConfigStringSlice: got %v", got)
+					}
+					if got := cmd.ConfigIntSlice("limits.ports"); !reflect.DeepEqual(got, []int{80, 443}) {
+						t.Errorf("ConfigIntSlice: got %v", got)
+					}
+					if got := cmd.ConfigStringMap("labels"); !reflect.DeepEqual(got, map[string]string{"env": "prod", "replicas": "3"}) {
+						t.Errorf("ConfigStringMap: got %v", got)
+					}
+					if got := cmd.ConfigString("server.missing"); got != "" {
+						t.Errorf("expected missing key to be empty, got %q", got)
+					}
+					return nil
+				},
+			},
+		},
+	}
+
+	root.SetArgs([]string{"serve"})
+	if err := root.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !checked {
+		t.Fatal("expected command to run")
+	}
+}
+
+func TestCommandConfigWithoutConfigFile(t *testing.T) {
+	cmd := &Command{Name: "app"}
+	if got := cmd.ConfigString("server.host"); got != "" {
+		t.Errorf("expected empty string, got %q", got)
+	}
+	if got := cmd.ConfigInt("server.port"); got != 0 {
+		t.Errorf("expected 0, got %d", got)
+	}
+	if got := cmd.ConfigStringSlice("hosts"); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// errConfirmationDeclined is returned by confirm when the user declines, Execute ends without error
+var errConfirmationDeclined = errors.New("operation not confirmed")
+
+var (
+	confirmInput      io.Reader = os.Stdin // Source of the confirmation answer
+	confirmIsTerminal           = stdinIsTerminal
+)
+
+// stdinIsTerminal reports if stdin is attached to a terminal
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Declined reports whether the user declined the confirmation prompt during the last call to Execute, in which case
+// Execute returned nil without running the command
+func (c *Command) Declined() bool {
+	return c.declined
+}
+
+// confirm asks the user to confirm the command before it is run, returns errConfirmationDeclined if the user declines
+func (c *Command) confirm() error {
+	if c.Confirm == "" || c.GetBool("yes") {
+		return nil
+	}
+
+	if !confirmIsTerminal() {
+		return fmt.Errorf("confirmation required: re-run with --yes to proceed without a prompt")
+	}
+
+	fmt.Fprintf(c.output(), "%s [y/N]: ", c.Confirm)
+	answer, err := bufio.NewReader(confirmInput).ReadString('\n')
+	if err != nil && answer == "" {
+		return errConfirmationDeclined
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errConfirmationDeclined
+	}
+}
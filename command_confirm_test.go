@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func withConfirmInput(t *testing.T, input string, isTerminal bool) {
+	t.Helper()
+	oldInput, oldIsTerminal := confirmInput, confirmIsTerminal
+	confirmInput = strings.NewReader(input)
+	confirmIsTerminal = func() bool { return isTerminal }
+	t.Cleanup(func() {
+		confirmInput, confirmIsTerminal = oldInput, oldIsTerminal
+	})
+}
+
+func newConfirmCommand(executed *bool) *Command {
+	return &Command{
+		Name:    "test",
+		Confirm: "Delete everything?",
+		Run: func(ctx context.Context, cmd *Command) error {
+			*executed = true
+			return nil
+		},
+	}
+}
+
+func TestConfirm_Accepted(t *testing.T) {
+	for _, answer := range []string{"y\n", "yes\n", "Y\n"} {
+		withConfirmInput(t, answer, true)
+
+		executed := false
+		os.Args = []string{"test"}
+		if err := newConfirmCommand(&executed).Execute(context.Background()); err != nil {
+			t.Fatalf("answer %q: expected no error, got %v", answer, err)
+		}
+		if !executed {
+			t.Fatalf("answer %q: expected command to be executed", answer)
+		}
+	}
+}
+
+func TestConfirm_Declined(t *testing.T) {
+	for _, answer := range []string{"n\n", "\n", "nope\n", ""} {
+		withConfirmInput(t, answer, true)
+
+		executed := false
+		os.Args = []string{"test"}
+		cmd := newConfirmCommand(&executed)
+		if err := cmd.Execute(context.Background()); err != nil {
+			t.Fatalf("answer %q: expected no error, got %v", answer, err)
+		}
+		if !cmd.Declined() {
+			t.Fatalf("answer %q: expected Declined to be true", answer)
+		}
+		if executed {
+			t.Fatalf("answer %q: expected command not to be executed", answer)
+		}
+	}
+}
+
+func TestConfirm_YesBypass(t *testing.T) {
+	for _, flag := range []string{"--yes", "-y"} {
+		withConfirmInput(t, "n\n", false)
+
+		executed := false
+		os.Args = []string{"test", flag}
+		if err := newConfirmCommand(&executed).Execute(context.Background()); err != nil {
+			t.Fatalf("%s: expected no error, got %v", flag, err)
+		}
+		if !executed {
+			t.Fatalf("%s: expected command to be executed", flag)
+		}
+	}
+}
+
+func TestConfirm_NonTerminal(t *testing.T) {
+	withConfirmInput(t, "y\n", false)
+
+	executed := false
+	os.Args = []string{"test"}
+	err := newConfirmCommand(&executed).Execute(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Fatalf("expected error mentioning --yes, got %v", err)
+	}
+	if executed {
+		t.Fatal("expected command not to be executed")
+	}
+}
+
+func TestConfirm_PromptWrittenToOutput(t *testing.T) {
+	withConfirmInput(t, "y\n", true)
+
+	var out strings.Builder
+	executed := false
+	cmd := newConfirmCommand(&executed)
+	cmd.Output = &out
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.String() != "Delete everything? [y/N]: " {
+		t.Errorf("expected the prompt on the command output, got %q", out.String())
+	}
+	if cmd.Declined() {
+		t.Error("expected Declined to be false")
+	}
+}
+
+func TestConfirm_KeepsUserShortFlag(t *testing.T) {
+	withConfirmInput(t, "n\n", false)
+
+	executed := false
+	cmd := newConfirmCommand(&executed)
+	cmd.Flags = []Flag{&StringFlag{Name: "year", Aliases: []string{"y"}}}
+
+	// -y belongs to --year, so only --yes skips the prompt
+	cmd.SetArgs([]string{"-y", "2024", "--yes"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !executed || cmd.GetString("year") != "2024" {
+		t.Errorf("expected the command to run with year 2024, got executed %v, year %q", executed, cmd.GetString("year"))
+	}
+}
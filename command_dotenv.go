@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paularlott/cli/env"
+)
+
+// loadDotEnvFiles loads the .env files configured on the command into the environment, variables already set in the
+// environment are kept while later files override earlier ones
+func (c *Command) loadDotEnvFiles() error {
+	var paths []string
+	for _, name := range c.DotEnvFiles {
+		path, found := c.findDotEnvFile(name)
+		if !found {
+			if c.DotEnvRequired {
+				return fmt.Errorf("env file '%s' not found", name)
+			}
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+
+	if len(paths) == 0 {
+		return nil
+	}
+	if err := env.LoadWithOptions(env.LoadOptions{Override: false}, paths...); err != nil {
+		return fmt.Errorf("failed to load env files '%s': %w", strings.Join(paths, "', '"), err)
+	}
+
+	return nil
+}
+
+// findDotEnvFile returns the path to the .env file, trying the search path if it can't be found as given
+func (c *Command) findDotEnvFile(name string) (string, bool) {
+	if fileExists(name) {
+		return name, true
+	}
+
+	if c.DotEnvSearchPath != nil && !filepath.IsAbs(name) {
+		for _, dir := range c.DotEnvSearchPath() {
+			path := filepath.Join(dir, name)
+			if fileExists(path) {
+				return path, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return !errors.Is(err, os.ErrNotExist)
+	}
+	return !info.IsDir()
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeDotEnv(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+	return path
+}
+
+func newDotEnvCommand(got *string) *Command {
+	return &Command{
+		Name: "test",
+		Flags: []Flag{
+			&StringFlag{Name: "token", EnvVars: []string{"CLI_DOTENV_TEST_TOKEN"}},
+		},
+		Run: func(ctx context.Context, cmd *Command) error {
+			*got = cmd.GetString("token")
+			return nil
+		},
+	}
+}
+
+func TestDotEnvFiles_PopulatesEnvFlag(t *testing.T) {
+	t.Setenv("CLI_DOTENV_TEST_TOKEN", "")
+	os.Unsetenv("CLI_DOTENV_TEST_TOKEN")
+
+	path := writeDotEnv(t, t.TempDir(), ".env", "CLI_DOTENV_TEST_TOKEN=from-dotenv\n")
+
+	var got string
+	cmd := newDotEnvCommand(&got)
+	cmd.DotEnvFiles = []string{path}
+
+	os.Args = []string{"test"}
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "from-dotenv" {
+		t.Errorf("expected 'from-dotenv', got %q", got)
+	}
+}
+
+func TestDotEnvFiles_KeepsEnvironment(t *testing.T) {
+	t.Setenv("CLI_DOTENV_TEST_TOKEN", "from-shell")
+	t.Setenv("CLI_DOTENV_TEST_LOCAL", "")
+	os.Unsetenv("CLI_DOTENV_TEST_LOCAL")
+
+	dir := t.TempDir()
+	base := writeDotEnv(t, dir, ".env", "CLI_DOTENV_TEST_TOKEN=from-dotenv\nCLI_DOTENV_TEST_LOCAL=base\n")
+	local := writeDotEnv(t, dir, ".env.local", "CLI_DOTENV_TEST_LOCAL=local\n")
+
+	var got string
+	cmd := newDotEnvCommand(&got)
+	cmd.DotEnvFiles = []string{base, local}
+
+	os.Args = []string{"test"}
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "from-shell" {
+		t.Errorf("expected the variable set in the environment to win, got %q", got)
+	}
+	if v := os.Getenv("CLI_DOTENV_TEST_LOCAL"); v != "local" {
+		t.Errorf("expected the later file to override the earlier one, got %q", v)
+	}
+}
+
+func TestDotEnvFiles_SearchPath(t *testing.T) {
+	t.Setenv("CLI_DOTENV_TEST_TOKEN", "")
+	os.Unsetenv("CLI_DOTENV_TEST_TOKEN")
+
+	dir := t.TempDir()
+	writeDotEnv(t, dir, "app.env", "CLI_DOTENV_TEST_TOKEN=searched\n")
+
+	var got string
+	cmd := newDotEnvCommand(&got)
+	cmd.DotEnvFiles = []string{"app.env"}
+	cmd.DotEnvSearchPath = func() []string { return []string{t.TempDir(), dir} }
+
+	os.Args = []string{"test"}
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "searched" {
+		t.Errorf("expected 'searched', got %q", got)
+	}
+}
+
+func TestDotEnvFiles_Missing(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.env")
+
+	var got string
+	cmd := newDotEnvCommand(&got)
+	cmd.DotEnvFiles = []string{missing}
+
+	os.Args = []string{"test"}
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("missing optional env file should be ignored, got %v", err)
+	}
+
+	cmd = newDotEnvCommand(&got)
+	cmd.DotEnvFiles = []string{missing}
+	cmd.DotEnvRequired = true
+
+	if err := cmd.Execute(context.Background()); err == nil {
+		t.Fatal("expected error for missing required env file")
+	}
+}
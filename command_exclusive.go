@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkMutuallyExclusive returns an error if more than one flag from any of the MutuallyExclusive groups
+// of the commands in the sequence was given on the command line
+func checkMutuallyExclusive(commandSequence []*Command, cliFlags map[string]bool) error {
+	for _, cmd := range commandSequence {
+		for _, group := range cmd.MutuallyExclusive {
+			var given []string
+			for _, name := range group {
+				if cliFlags[name] {
+					given = append(given, "--"+name)
+				}
+			}
+
+			if len(given) > 1 {
+				return fmt.Errorf("flags %s and %s cannot be used together", strings.Join(given[:len(given)-1], ", "), given[len(given)-1])
+			}
+		}
+	}
+	return nil
+}
+
+// checkRequiredOneOf returns an error if other than exactly one flag from any of the RequiredOneOf groups
+// of the commands in the sequence is set on the matched command, values from environment variables and config files
+// count as set but bool flags that are false don't
+func checkRequiredOneOf(commandSequence []*Command, matched *Command) error {
+	for _, cmd := range commandSequence {
+		for _, group := range cmd.RequiredOneOf {
+			count := 0
+			for _, name := range group {
+				if matched.flagGiven(name) {
+					count++
+				}
+			}
+
+			if count != 1 {
+				return fmt.Errorf("exactly one of --%s is required", strings.Join(group, ", --"))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMutuallyExclusiveFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		wantErr string
+	}{
+		{"one flag", []string{"--json"}, "", ""},
+		{"two flags", []string{"--json", "--yaml"}, "", "flags --json and --yaml cannot be used together"},
+		{"three flags", []string{"--xml", "--yaml", "-j"}, "", "flags --json, --yaml and --xml cannot be used together"},
+		{"env var ignored", []string{"--json"}, "true", ""},
+		{"help skips check", []string{"--json", "--yaml", "--help"}, "", ""},
+		{"version skips check", []string{"--json", "--yaml", "--version"}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_YAML", tt.env)
+			}
+
+			cmd := &Command{
+				Name:    "test",
+				Version: "1.0.0",
+				Flags: []Flag{
+					&BoolFlag{Name: "json", Aliases: []string{"j"}},
+					&BoolFlag{Name: "yaml", EnvVars: []string{"TEST_YAML"}},
+					&BoolFlag{Name: "xml", DefaultValue: true},
+				},
+				MutuallyExclusive: [][]string{{"json", "yaml", "xml"}},
+				Run:               func(ctx context.Context, cmd *Command) error { return nil },
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMutuallyExclusiveParentGroup(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Flags: []Flag{
+			&BoolFlag{Name: "quiet", Global: true},
+			&BoolFlag{Name: "verbose", Global: true},
+		},
+		MutuallyExclusive: [][]string{{"quiet", "verbose"}},
+		Commands: []*Command{
+			{Name: "run", Run: func(ctx context.Context, cmd *Command) error { return nil }},
+		},
+	}
+
+	root.SetArgs([]string{"run", "--quiet", "--verbose"})
+	err := root.Execute(context.Background())
+	if err == nil || err.Error() != "flags --quiet and --verbose cannot be used together" {
+		t.Errorf("expected mutually exclusive error, got %v", err)
+	}
+}
+
+func TestRequiredOneOfFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		config  string
+		wantErr string
+	}{
+		{"one flag", []string{"--file", "in.txt"}, "", `{}`, ""},
+		{"no flags", []string{}, "", `{}`, "exactly one of --file, --stdin, --url is required"},
+		{"two flags", []string{"--file", "in.txt", "--stdin"}, "", `{}`, "exactly one of --file, --stdin, --url is required"},
+		{"from env", []string{}, "https://example.com", `{}`, ""},
+		{"from config", []string{}, "", `{"input":{"file":"in.txt"}}`, ""},
+		{"cli and env", []string{"--stdin"}, "https://example.com", `{}`, "exactly one of --file, --stdin, --url is required"},
+		{"false bool with another flag", []string{"--stdin=false", "--file", "in.txt"}, "", `{}`, ""},
+		{"negated bool with another flag", []string{"--no-stdin", "--file", "in.txt"}, "", `{}`, ""},
+		{"only a false bool", []string{"--no-stdin"}, "", `{}`, "exactly one of --file, --stdin, --url is required"},
+		{"help skips check", []string{"--help"}, "", `{}`, ""},
+		{"version skips check", []string{"--version"}, "", `{}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_URL", tt.env)
+			}
+			cfg, _ := newJSONConfigBase(t, tt.config)
+
+			cmd := &Command{
+				Name:       "test",
+				Version:    "1.0.0",
+				ConfigFile: cfg,
+				Flags: []Flag{
+					&StringFlag{Name: "file", ConfigPath: []string{"input.file"}},
+					&BoolFlag{Name: "stdin"},
+					&StringFlag{Name: "url", EnvVars: []string{"TEST_URL"}, DefaultValue: "https://default.example.com"},
+				},
+				RequiredOneOf: [][]string{{"file", "stdin", "url"}},
+				Run:           func(ctx context.Context, cmd *Command) error { return nil },
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ExitCoder is an error that carries the exit code the process should end with
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+type exitError struct {
+	message string
+	code    int
+}
+
+func (e *exitError) Error() string {
+	return e.message
+}
+
+func (e *exitError) ExitCode() int {
+	return e.code
+}
+
+// Exit returns an error with the given message that ends the process with code when returned from a command,
+// e.g. return cli.Exit("no changes to apply", 3)
+func Exit(message string, code int) error {
+	return &exitError{message: message, code: code}
+}
+
+// ExitCode returns the exit code for an error returned by Execute, 0 for nil, the code of the first ExitCoder found
+// in the error's tree, or 1 for any other error
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitCoder ExitCoder
+	if errors.As(err, &exitCoder) {
+		return exitCoder.ExitCode()
+	}
+	return 1
+}
+
+// ExecuteWithExitCode runs Execute and returns the exit code for the result, printing any error message to stderr,
+// so that main can end with os.Exit(cmd.ExecuteWithExitCode(ctx))
+func (c *Command) ExecuteWithExitCode(ctx context.Context) int {
+	err := c.Execute(ctx)
+	if err != nil && err.Error() != "" {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	return ExitCode(err)
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"plain error", errors.New("failed"), 1},
+		{"exit error", Exit("no changes", 3), 3},
+		{"wrapped", fmt.Errorf("apply: %w", Exit("conflict", 4)), 4},
+		{"joined", errors.Join(nil, Exit("conflict", 5), errors.New("cleanup failed")), 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("expected exit code %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExecuteWithExitCode(t *testing.T) {
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Commands: []*Command{
+				{Name: "ok", Run: func(ctx context.Context, cmd *Command) error { return nil }},
+				{Name: "fail", Run: func(ctx context.Context, cmd *Command) error { return errors.New("failed") }},
+				{Name: "conflict", Run: func(ctx context.Context, cmd *Command) error { return Exit("conflict found", 4) }},
+				{
+					Name: "pre",
+					PreRun: func(ctx context.Context, cmd *Command) (context.Context, error) {
+						return ctx, Exit("not logged in", 5)
+					},
+					Run: func(ctx context.Context, cmd *Command) error { return nil },
+				},
+				{
+					Name:  "validate",
+					Flags: []Flag{&IntFlag{Name: "port", Required: true}},
+					OnValidationError: func(c *Command, err error) error {
+						return Exit(err.Error(), 2)
+					},
+					Run: func(ctx context.Context, cmd *Command) error { return nil },
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		args   []string
+		want   int
+		stderr string
+	}{
+		{[]string{"ok"}, 0, ""},
+		{[]string{"fail"}, 1, "Error: failed\n"},
+		{[]string{"conflict"}, 4, "Error: conflict found\n"},
+		{[]string{"pre"}, 5, "Error: not logged in\n"},
+		{[]string{"validate"}, 2, "Error: required flag 'port' not set\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args[0], func(t *testing.T) {
+			root := newRoot()
+			root.SetArgs(tt.args)
+
+			var code int
+			stderr := captureStderr(t, func() {
+				code = root.ExecuteWithExitCode(context.Background())
+			})
+			if code != tt.want {
+				t.Errorf("expected exit code %d, got %d", tt.want, code)
+			}
+			if stderr != tt.stderr {
+				t.Errorf("expected stderr %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+}
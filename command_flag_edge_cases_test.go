@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -824,3 +825,101 @@ func TestFlagPositioning_EmptyArguments(t *testing.T) {
 		t.Fatal("expected command to be executed")
 	}
 }
+
+func TestFlagPrefix(t *testing.T) {
+	newCmd := func(captured *Command) *Command {
+		return &Command{
+			Name:            "test",
+			AllowFlagPrefix: true,
+			Flags: []Flag{
+				&BoolFlag{Name: "verbose"},
+				&BoolFlag{Name: "version-check"},
+				&IntFlag{Name: "port", Aliases: []string{"port-number"}},
+				&StringFlag{Name: "name"},
+				&StringFlag{Name: "namespace"},
+				&StringFlag{Name: "output", Global: true},
+			},
+			Commands: []*Command{
+				{
+					Name: "sub",
+					Run: func(ctx context.Context, cmd *Command) error {
+						*captured = *cmd
+						return nil
+					},
+				},
+			},
+			Run: func(ctx context.Context, cmd *Command) error {
+				*captured = *cmd
+				return nil
+			},
+		}
+	}
+
+	t.Run("unique prefix", func(t *testing.T) {
+		var captured Command
+		os.Args = []string{"test", "--verb", "--po", "8080", "arg"}
+		cmd := newCmd(&captured)
+		cmd.MaxArgs = UnlimitedArgs
+		if err := cmd.Execute(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !captured.GetBool("verbose") || captured.GetInt("port") != 8080 {
+			t.Fatalf("expected verbose and port 8080, got %v and %d", captured.GetBool("verbose"), captured.GetInt("port"))
+		}
+		if args := captured.GetArgs(); len(args) != 1 || args[0] != "arg" {
+			t.Fatalf("expected remaining args [arg], got %v", args)
+		}
+	})
+
+	t.Run("prefix with inline value", func(t *testing.T) {
+		var captured Command
+		os.Args = []string{"test", "--po=9090"}
+		if err := newCmd(&captured).Execute(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if captured.GetInt("port") != 9090 {
+			t.Fatalf("expected port 9090, got %d", captured.GetInt("port"))
+		}
+	})
+
+	t.Run("global flag prefix in subcommand", func(t *testing.T) {
+		var captured Command
+		os.Args = []string{"test", "sub", "--out", "json"}
+		if err := newCmd(&captured).Execute(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if captured.GetString("output") != "json" {
+			t.Fatalf("expected output json, got %q", captured.GetString("output"))
+		}
+	})
+
+	t.Run("ambiguous prefix", func(t *testing.T) {
+		var captured Command
+		os.Args = []string{"test", "--ver"}
+		err := newCmd(&captured).Execute(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "ambiguous flag: --ver could be --verbose, --version-check") {
+			t.Fatalf("expected ambiguity error, got %v", err)
+		}
+	})
+
+	t.Run("exact match wins", func(t *testing.T) {
+		var captured Command
+		os.Args = []string{"test", "--name", "x"}
+		if err := newCmd(&captured).Execute(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if captured.GetString("name") != "x" || captured.GetString("namespace") != "" {
+			t.Fatalf("expected name to be set exactly, got name=%q namespace=%q", captured.GetString("name"), captured.GetString("namespace"))
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		var captured Command
+		os.Args = []string{"test", "--verb"}
+		cmd := newCmd(&captured)
+		cmd.AllowFlagPrefix = false
+		if err := cmd.Execute(context.Background()); err == nil || !strings.Contains(err.Error(), "unknown flag: --verb") {
+			t.Fatalf("expected unknown flag error, got %v", err)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -582,3 +583,72 @@ func TestFlagPositioning_EmptyStringValue(t *testing.T) {
 		t.Fatal("expected command to be executed")
 	}
 }
+
+// TestFlagPositioning_NegativeNumbers tests negative numbers are positional arguments or flag values rather than flags
+func TestFlagPositioning_NegativeNumbers(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		digitFlag  bool
+		wantArgs   []string
+		wantOffset int
+		wantFive   bool
+		wantErr    string
+	}{
+		{"argument", []string{"compute", "-5"}, false, []string{"-5"}, 0, false, ""},
+		{"decimal argument", []string{"compute", "1", "-1.5"}, false, []string{"1", "-1.5"}, 0, false, ""},
+		{"flag value", []string{"compute", "--offset", "-3", "-5"}, false, []string{"-5"}, -3, false, ""},
+		{"short flag value", []string{"compute", "-o", "-3"}, false, []string{}, -3, false, ""},
+		{"flag after argument", []string{"compute", "-5", "--offset=2"}, false, []string{"-5"}, 2, false, ""},
+		{"global flag before subcommand", []string{"--offset", "-3", "compute", "-5"}, false, []string{"-5"}, -3, false, ""},
+		{"after terminator", []string{"compute", "--", "-5", "--offset"}, false, []string{"-5", "--offset"}, 0, false, ""},
+		{"digit flag", []string{"compute", "-5"}, true, []string{}, 0, true, ""},
+		{"unknown flag", []string{"compute", "-x"}, false, nil, 0, false, "unknown flag: -x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotArgs []string
+			var offset int
+			var five bool
+
+			flags := []Flag{&IntFlag{Name: "offset", Aliases: []string{"o"}, Global: true, AssignTo: &offset}}
+			if tt.digitFlag {
+				flags = append(flags, &BoolFlag{Name: "five", Aliases: []string{"5"}, Global: true, AssignTo: &five})
+			}
+
+			rootCmd := &Command{
+				Name:  "calc",
+				Flags: flags,
+				Commands: []*Command{
+					{
+						Name:    "compute",
+						MaxArgs: UnlimitedArgs,
+						Run: func(ctx context.Context, cmd *Command) error {
+							gotArgs = cmd.GetArgs()
+							return nil
+						},
+					},
+				},
+			}
+
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.Execute(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(gotArgs) != len(tt.wantArgs) || (len(gotArgs) > 0 && !reflect.DeepEqual(gotArgs, tt.wantArgs)) {
+				t.Errorf("expected args %q, got %q", tt.wantArgs, gotArgs)
+			}
+			if offset != tt.wantOffset || five != tt.wantFive {
+				t.Errorf("expected offset %d and five %v, got %d and %v", tt.wantOffset, tt.wantFive, offset, five)
+			}
+		})
+	}
+}
@@ -1,8 +1,80 @@
 package cli
 
-// Flag getters
+import (
+	"fmt"
+	"net"
+	"net/url"
+)
+
+// RawFlags returns a copy of the resolved flag values keyed by flag name, the values of secret flags are masked
+func (c *Command) RawFlags() map[string]any {
+	raw := make(map[string]any, len(c.parsedFlags))
+	for name, value := range c.parsedFlags {
+		raw[name] = value
+	}
+
+	flags := make([]Flag, 0, len(c.globalFlags)+len(c.Flags))
+	flags = append(flags, c.globalFlags...)
+	flags = append(flags, c.Flags...)
+	for _, flag := range flags {
+		if value, ok := raw[flag.getName()]; ok && flag.isSecret() {
+			raw[flag.getName()] = MaskSecret(fmt.Sprintf("%v", value))
+		}
+	}
+
+	return raw
+}
+
+// FlagNames returns the names of the flags declared for the command, the inherited global flags first and then in the order they're defined,
+// a flag of the command replaces an inherited global flag with the same name
+func (c *Command) FlagNames() []string {
+	flags := c.combinedFlags()
+	names := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		names = append(names, flag.getName())
+	}
+	return names
+}
+
+// VisitSetFlags calls fn with the name and value of each flag that was set on the command line, from an environment variable or
+// the config file, in the same order as FlagNames, flags holding their default value are skipped and secret values are masked
+func (c *Command) VisitSetFlags(fn func(name string, value any)) {
+	for _, flag := range c.combinedFlags() {
+		name := flag.getName()
+		if !c.givenFlags[name] {
+			continue
+		}
+
+		value := c.parsedFlags[name]
+		if flag.isSecret() {
+			value = MaskSecret(fmt.Sprintf("%v", value))
+		}
+		fn(name, value)
+	}
+}
+
+// resolveFlagKey returns the name the value of a flag is stored under, given either its name or one of its aliases
+func (c *Command) resolveFlagKey(nameOrAlias string) string {
+	if _, ok := c.parsedFlags[nameOrAlias]; ok {
+		return nameOrAlias
+	}
+
+	for _, flags := range [][]Flag{c.globalFlags, c.Flags} {
+		for _, flag := range flags {
+			for _, alias := range flag.getAliases() {
+				if alias == nameOrAlias {
+					return flag.getName()
+				}
+			}
+		}
+	}
+
+	return nameOrAlias
+}
+
+// Flag getters, the name can be the flag's name or one of its aliases
 func (c *Command) GetString(name string) string {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.(string); ok {
 			return s
 		}
@@ -11,7 +83,7 @@ func (c *Command) GetString(name string) string {
 }
 
 func (c *Command) GetInt64(name string) int64 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if i, ok := v.(int64); ok {
 			return i
 		}
@@ -20,7 +92,7 @@ func (c *Command) GetInt64(name string) int64 {
 }
 
 func (c *Command) GetInt(name string) int {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if i, ok := v.(int); ok {
 			return i
 		}
@@ -29,7 +101,7 @@ func (c *Command) GetInt(name string) int {
 }
 
 func (c *Command) GetInt8(name string) int8 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if i, ok := v.(int8); ok {
 			return i
 		}
@@ -38,7 +110,7 @@ func (c *Command) GetInt8(name string) int8 {
 }
 
 func (c *Command) GetInt16(name string) int16 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if i, ok := v.(int16); ok {
 			return i
 		}
@@ -47,7 +119,7 @@ func (c *Command) GetInt16(name string) int16 {
 }
 
 func (c *Command) GetInt32(name string) int32 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if i, ok := v.(int32); ok {
 			return i
 		}
@@ -56,7 +128,7 @@ func (c *Command) GetInt32(name string) int32 {
 }
 
 func (c *Command) GetUint64(name string) uint64 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if u, ok := v.(uint64); ok {
 			return u
 		}
@@ -65,7 +137,7 @@ func (c *Command) GetUint64(name string) uint64 {
 }
 
 func (c *Command) GetUint(name string) uint {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if u, ok := v.(uint); ok {
 			return u
 		}
@@ -74,7 +146,7 @@ func (c *Command) GetUint(name string) uint {
 }
 
 func (c *Command) GetUint8(name string) uint8 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if u, ok := v.(uint8); ok {
 			return u
 		}
@@ -83,7 +155,7 @@ func (c *Command) GetUint8(name string) uint8 {
 }
 
 func (c *Command) GetUint16(name string) uint16 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if u, ok := v.(uint16); ok {
 			return u
 		}
@@ -92,7 +164,7 @@ func (c *Command) GetUint16(name string) uint16 {
 }
 
 func (c *Command) GetUint32(name string) uint32 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if u, ok := v.(uint32); ok {
 			return u
 		}
@@ -101,7 +173,7 @@ func (c *Command) GetUint32(name string) uint32 {
 }
 
 func (c *Command) GetFloat32(name string) float32 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if f, ok := v.(float32); ok {
 			return f
 		}
@@ -110,7 +182,7 @@ func (c *Command) GetFloat32(name string) float32 {
 }
 
 func (c *Command) GetFloat64(name string) float64 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if f, ok := v.(float64); ok {
 			return f
 		}
@@ -119,7 +191,7 @@ func (c *Command) GetFloat64(name string) float64 {
 }
 
 func (c *Command) GetBool(name string) bool {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if b, ok := v.(bool); ok {
 			return b
 		}
@@ -129,7 +201,7 @@ func (c *Command) GetBool(name string) bool {
 
 // Slice getters
 func (c *Command) GetStringSlice(name string) []string {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]string); ok {
 			return s
 		}
@@ -138,7 +210,7 @@ func (c *Command) GetStringSlice(name string) []string {
 }
 
 func (c *Command) GetIntSlice(name string) []int {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]int); ok {
 			return s
 		}
@@ -147,7 +219,7 @@ func (c *Command) GetIntSlice(name string) []int {
 }
 
 func (c *Command) GetInt8Slice(name string) []int8 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]int8); ok {
 			return s
 		}
@@ -156,7 +228,7 @@ func (c *Command) GetInt8Slice(name string) []int8 {
 }
 
 func (c *Command) GetInt16Slice(name string) []int16 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]int16); ok {
 			return s
 		}
@@ -165,7 +237,7 @@ func (c *Command) GetInt16Slice(name string) []int16 {
 }
 
 func (c *Command) GetInt32Slice(name string) []int32 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]int32); ok {
 			return s
 		}
@@ -174,7 +246,7 @@ func (c *Command) GetInt32Slice(name string) []int32 {
 }
 
 func (c *Command) GetInt64Slice(name string) []int64 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]int64); ok {
 			return s
 		}
@@ -183,7 +255,7 @@ func (c *Command) GetInt64Slice(name string) []int64 {
 }
 
 func (c *Command) GetUintSlice(name string) []uint {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]uint); ok {
 			return s
 		}
@@ -192,7 +264,7 @@ func (c *Command) GetUintSlice(name string) []uint {
 }
 
 func (c *Command) GetUint8Slice(name string) []uint8 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]uint8); ok {
 			return s
 		}
@@ -201,7 +273,7 @@ func (c *Command) GetUint8Slice(name string) []uint8 {
 }
 
 func (c *Command) GetUint16Slice(name string) []uint16 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]uint16); ok {
 			return s
 		}
@@ -210,7 +282,7 @@ func (c *Command) GetUint16Slice(name string) []uint16 {
 }
 
 func (c *Command) GetUint32Slice(name string) []uint32 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]uint32); ok {
 			return s
 		}
@@ -219,7 +291,7 @@ func (c *Command) GetUint32Slice(name string) []uint32 {
 }
 
 func (c *Command) GetUint64Slice(name string) []uint64 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]uint64); ok {
 			return s
 		}
@@ -228,7 +300,7 @@ func (c *Command) GetUint64Slice(name string) []uint64 {
 }
 
 func (c *Command) GetFloat32Slice(name string) []float32 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]float32); ok {
 			return s
 		}
@@ -237,7 +309,7 @@ func (c *Command) GetFloat32Slice(name string) []float32 {
 }
 
 func (c *Command) GetFloat64Slice(name string) []float64 {
-	if v, ok := c.parsedFlags[name]; ok {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
 		if s, ok := v.([]float64); ok {
 			return s
 		}
@@ -245,6 +317,69 @@ func (c *Command) GetFloat64Slice(name string) []float64 {
 	return nil
 }
 
+func (c *Command) GetStringMap(name string) map[string]string {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
+		if m, ok := v.(map[string]string); ok {
+			return m
+		}
+	}
+	return nil
+}
+
+func (c *Command) GetIP(name string) net.IP {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
+		if ip, ok := v.(net.IP); ok {
+			return ip
+		}
+	}
+	return nil
+}
+
+func (c *Command) GetIPNet(name string) *net.IPNet {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
+		if ipNet, ok := v.(*net.IPNet); ok {
+			return ipNet
+		}
+	}
+	return nil
+}
+
+func (c *Command) GetIPSlice(name string) []net.IP {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
+		if s, ok := v.([]net.IP); ok {
+			return s
+		}
+	}
+	return nil
+}
+
+func (c *Command) GetIPNetSlice(name string) []*net.IPNet {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
+		if s, ok := v.([]*net.IPNet); ok {
+			return s
+		}
+	}
+	return nil
+}
+
+func (c *Command) GetURL(name string) *url.URL {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
+		if u, ok := v.(*url.URL); ok {
+			return u
+		}
+	}
+	return nil
+}
+
+func (c *Command) GetURLSlice(name string) []*url.URL {
+	if v, ok := c.parsedFlags[c.resolveFlagKey(name)]; ok {
+		if s, ok := v.([]*url.URL); ok {
+			return s
+		}
+	}
+	return nil
+}
+
 // Argument getters
 func (c *Command) GetStringArg(name string) string {
 	if v, ok := c.parsedArgs[name]; ok {
@@ -371,3 +506,57 @@ func (c *Command) GetFloat32Arg(name string) float32 {
 	}
 	return 0
 }
+
+func (c *Command) GetStringSliceArg(name string) []string {
+	if v, ok := c.parsedArgs[name]; ok {
+		if s, ok := v.([]string); ok {
+			return s
+		}
+	}
+	return nil
+}
+
+func (c *Command) GetIntSliceArg(name string) []int {
+	if v, ok := c.parsedArgs[name]; ok {
+		if s, ok := v.([]int); ok {
+			return s
+		}
+	}
+	return nil
+}
+
+func (c *Command) GetInt64SliceArg(name string) []int64 {
+	if v, ok := c.parsedArgs[name]; ok {
+		if s, ok := v.([]int64); ok {
+			return s
+		}
+	}
+	return nil
+}
+
+func (c *Command) GetUintSliceArg(name string) []uint {
+	if v, ok := c.parsedArgs[name]; ok {
+		if s, ok := v.([]uint); ok {
+			return s
+		}
+	}
+	return nil
+}
+
+func (c *Command) GetUint64SliceArg(name string) []uint64 {
+	if v, ok := c.parsedArgs[name]; ok {
+		if s, ok := v.([]uint64); ok {
+			return s
+		}
+	}
+	return nil
+}
+
+func (c *Command) GetFloat64SliceArg(name string) []float64 {
+	if v, ok := c.parsedArgs[name]; ok {
+		if s, ok := v.([]float64); ok {
+			return s
+		}
+	}
+	return nil
+}
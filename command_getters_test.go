@@ -2,7 +2,9 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -480,3 +482,183 @@ func TestGetRootCmd_NoChain(t *testing.T) {
 		t.Error("GetRootCmd on command with no chain should return itself")
 	}
 }
+
+func TestRawFlags(t *testing.T) {
+	var raw map[string]any
+	root := &Command{
+		Name: "root",
+		Flags: []Flag{
+			&StringFlag{Name: "token", Global: true, Secret: true},
+			&BoolFlag{Name: "debug", Global: true},
+		},
+		Commands: []*Command{
+			{
+				Name: "child",
+				Flags: []Flag{
+					&IntFlag{Name: "port", DefaultValue: 8080},
+					&StringSliceFlag{Name: "tags"},
+					&StringFlag{Name: "password", Secret: true},
+					&StringFlag{Name: "unset"},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					raw = cmd.RawFlags()
+					return nil
+				},
+			},
+		},
+	}
+
+	os.Args = []string{"root", "child", "--token", "sk-abcdef123456", "--debug", "--tags", "a", "--tags", "b", "--password", "hunter2"}
+	if err := root.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]any{
+		"token":    "sk-a***3456",
+		"debug":    true,
+		"port":     8080,
+		"tags":     []string{"a", "b"},
+		"password": "[***]",
+	}
+	for name, want := range expected {
+		if got, ok := raw[name]; !ok || !reflect.DeepEqual(got, want) {
+			t.Errorf("RawFlags()[%q] = %v, want %v", name, got, want)
+		}
+	}
+	if _, ok := raw["unset"]; ok {
+		t.Error("RawFlags() should not contain unset flags")
+	}
+
+	// The returned map is a copy
+	raw["port"] = 1
+	if root.Commands[0].GetInt("port") != 8080 {
+		t.Error("modifying RawFlags() result changed the command")
+	}
+}
+
+func TestFlagNamesAndVisitSetFlags(t *testing.T) {
+	t.Setenv("CHILD_REGION", "eu-west")
+
+	var names []string
+	var visited []string
+	values := map[string]any{}
+	root := &Command{
+		Name: "root",
+		Flags: []Flag{
+			&StringFlag{Name: "token", Global: true, Secret: true},
+			&BoolFlag{Name: "debug", Global: true},
+		},
+		Commands: []*Command{
+			{
+				Name: "child",
+				Flags: []Flag{
+					&IntFlag{Name: "port", DefaultValue: 8080},
+					&StringFlag{Name: "region", EnvVars: []string{"CHILD_REGION"}},
+					&StringSliceFlag{Name: "tags"},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					names = cmd.FlagNames()
+					cmd.VisitSetFlags(func(name string, value any) {
+						visited = append(visited, name)
+						values[name] = value
+					})
+					return nil
+				},
+			},
+		},
+	}
+
+	root.SetArgs([]string{"child", "--tags", "a", "--token", "sk-abcdef123456", "--tags", "b"})
+	if err := root.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantNames := []string{"token", "debug", "port", "region", "tags", "help"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("FlagNames() = %v, want %v", names, wantNames)
+	}
+
+	wantVisited := []string{"token", "region", "tags"}
+	if !reflect.DeepEqual(visited, wantVisited) {
+		t.Errorf("VisitSetFlags visited %v, want %v", visited, wantVisited)
+	}
+
+	wantValues := map[string]any{
+		"token":  "sk-a***3456",
+		"region": "eu-west",
+		"tags":   []string{"a", "b"},
+	}
+	if !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("VisitSetFlags values = %v, want %v", values, wantValues)
+	}
+}
+
+func TestFlagNames_CommandFlagShadowsGlobal(t *testing.T) {
+	var names []string
+	var visited []string
+	root := &Command{
+		Name:        "root",
+		DisableHelp: true,
+		Flags: []Flag{
+			&StringFlag{Name: "region", Global: true},
+			&BoolFlag{Name: "debug", Global: true},
+		},
+		Commands: []*Command{
+			{
+				Name:        "child",
+				DisableHelp: true,
+				Flags:       []Flag{&StringFlag{Name: "region", Secret: true}},
+				Run: func(ctx context.Context, cmd *Command) error {
+					names = cmd.FlagNames()
+					cmd.VisitSetFlags(func(name string, value any) {
+						visited = append(visited, name+"="+fmt.Sprint(value))
+					})
+					return nil
+				},
+			},
+		},
+	}
+
+	root.SetArgs([]string{"child", "--region", "eu-west-1234"})
+	if err := root.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"debug", "region"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("FlagNames() = %v, want %v", names, want)
+	}
+	if want := []string{"region=eu-w***1234"}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("VisitSetFlags visited %v, want %v", visited, want)
+	}
+}
+
+func TestGettersAcceptAliases(t *testing.T) {
+	var tags []string
+	var hasName, hasN bool
+	cmd := &Command{
+		Name: "test",
+		Flags: []Flag{
+			&StringSliceFlag{Name: "tags", Aliases: []string{"t", "label"}},
+			&StringFlag{Name: "name", Aliases: []string{"n"}},
+		},
+		Run: func(ctx context.Context, cmd *Command) error {
+			tags = cmd.GetStringSlice("label")
+			hasName, hasN = cmd.HasFlag("name"), cmd.HasFlag("n")
+			return nil
+		},
+	}
+
+	cmd.SetArgs([]string{"-t", "a", "--tags", "b"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("expected tags [a b] via alias, got %v", tags)
+	}
+	if hasName || hasN {
+		t.Errorf("expected name not to be set, got HasFlag(name)=%v HasFlag(n)=%v", hasName, hasN)
+	}
+	if got := cmd.GetStringSlice("t"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected tags [a b] via short alias, got %v", got)
+	}
+}
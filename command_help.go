@@ -2,28 +2,49 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 )
 
+// ShowHelp prints the help for the command to its Output, or stdout if not set
 func (c *Command) ShowHelp() {
-	// Make the command name from the chain of commands
+	c.WriteHelp(c.output())
+}
+
+// displayHelp shows the help for the command using the HelpFunc closest to it, or ShowHelp if there isn't one
+func (c *Command) displayHelp() {
+	for i := len(c.commandChain) - 1; i >= 0; i-- {
+		if c.commandChain[i].HelpFunc != nil {
+			c.commandChain[i].HelpFunc(c)
+			return
+		}
+	}
+	c.ShowHelp()
+}
+
+// HelpString returns the help for the command as a string, e.g. to display it inside a TUI or REPL
+func (c *Command) HelpString() string {
+	var b strings.Builder
+	c.WriteHelp(&b)
+	return b.String()
+}
+
+// commandPath returns the names of the commands from the root to the command, or just the command's name if it hasn't been parsed yet
+func (c *Command) commandPath() []string {
 	chain := []string{}
 	for _, cmd := range c.commandChain {
 		chain = append(chain, cmd.Name)
 	}
-	cmdName := strings.Join(chain, " ")
-
-	// Display name and version
-	fmt.Printf("Name:\n   %s", cmdName)
-	if c.Usage != "" {
-		fmt.Printf(" - %s", c.Usage)
+	if len(chain) == 0 {
+		chain = append(chain, c.Name)
 	}
-	fmt.Println()
-	fmt.Println()
+	return chain
+}
 
-	// Display usage
-	fmt.Println("Usage:")
-	usageString := fmt.Sprintf("   %s", cmdName)
+// usageLine returns the usage of the command, e.g. "app remove [flags] <id> [args...]"
+func (c *Command) usageLine(cmdName string) string {
+	usageString := cmdName
 
 	// Add flags indicator if we have flags
 	if len(c.Flags) > 0 {
@@ -31,12 +52,8 @@ func (c *Command) ShowHelp() {
 	}
 
 	// Add arguments
-	for _, arg := range c.Arguments {
-		if arg.isRequired() {
-			usageString += fmt.Sprintf(" <%s>", arg.name())
-		} else {
-			usageString += fmt.Sprintf(" [%s]", arg.name())
-		}
+	if named := c.namedArgsUsage(); named != "" {
+		usageString += " " + named
 	}
 
 	// Add command placeholder if subcommands exist
@@ -44,41 +61,86 @@ func (c *Command) ShowHelp() {
 		usageString += " [command]"
 	}
 
+	if unnamed := c.unnamedArgsUsage(); unnamed != "" {
+		usageString += " " + unnamed
+	}
+
+	return usageString
+}
+
+// argsUsage returns the usage of the positional arguments of the command, e.g. "<id> [name] [args...]"
+func (c *Command) argsUsage() string {
+	return strings.TrimSpace(c.namedArgsUsage() + " " + c.unnamedArgsUsage())
+}
+
+// namedArgsUsage returns the usage of the named arguments, required ones in angle brackets and optional ones in square brackets
+func (c *Command) namedArgsUsage() string {
+	var parts []string
+	for _, arg := range c.Arguments {
+		name := arg.name()
+		if arg.isVariadic() {
+			name += "..."
+		}
+		if arg.isRequired() {
+			parts = append(parts, fmt.Sprintf("<%s>", name))
+		} else if defaultText := arg.defaultText(); defaultText != "" {
+			parts = append(parts, fmt.Sprintf("[%s=%s]", name, defaultText))
+		} else {
+			parts = append(parts, fmt.Sprintf("[%s]", name))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// unnamedArgsUsage returns the placeholder for the unnamed arguments allowed by MinArgs and MaxArgs, if any
+func (c *Command) unnamedArgsUsage() string {
+	if c.hasVariadicArg() {
+		return ""
+	}
 	if c.MaxArgs > 0 || c.MaxArgs == UnlimitedArgs {
 		if c.MinArgs > 0 {
-			usageString += " <args...>"
-		} else {
-			usageString += " [args...]"
+			return "<args...>"
 		}
+		return "[args...]"
+	}
+	return ""
+}
+
+// WriteHelp writes the help for the command to w, e.g. to capture it in a test
+func (c *Command) WriteHelp(w io.Writer) {
+	cmdName := strings.Join(c.commandPath(), " ")
+
+	// Display name and version
+	fmt.Fprintf(w, "Name:\n   %s", cmdName)
+	if c.Usage != "" {
+		fmt.Fprintf(w, " - %s", c.Usage)
 	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w)
 
-	fmt.Println(usageString)
-	fmt.Println()
+	// Display usage
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "   "+c.usageLine(cmdName))
+	fmt.Fprintln(w)
 
 	// Display version if available
 	if c.Version != "" {
-		fmt.Printf("Version:\n   %s\n\n", c.Version)
+		fmt.Fprintf(w, "Version:\n   %s\n\n", c.Version)
 	}
 
 	// Display detailed description if available
 	if c.Description != "" {
-		fmt.Println("Description:")
+		fmt.Fprintln(w, "Description:")
 		paragraphs := strings.Split(c.Description, "\n\n")
 		for _, para := range paragraphs {
-			fmt.Printf("   ")
-			c.printWrappedText(strings.TrimSpace(para), 3, 80)
-			fmt.Print("\n\n")
+			fmt.Fprintf(w, "   ")
+			c.printWrappedText(w, strings.TrimSpace(para), 3, 80)
+			fmt.Fprint(w, "\n\n")
 		}
 	}
 
 	// Display subcommands if any
-	if len(c.Commands) > 0 {
-		fmt.Println("Available Commands:")
-		for _, cmd := range c.Commands {
-			fmt.Printf("   %-15s %s\n", cmd.Name, cmd.Usage)
-		}
-		fmt.Println()
-	}
+	c.writeCommandList(w)
 
 	// Group flags into local and global
 	localFlags := []Flag{}
@@ -96,32 +158,37 @@ func (c *Command) ShowHelp() {
 		}
 	}
 
-	// Add found global flags from parent commands
+	// Add found global flags from parent commands, unless the command has its own flag of the same name
 	for _, flag := range c.globalFlags {
-		if flag.isHidden() {
+		if flag.isHidden() || c.hasFlag(flag.getName()) {
 			continue
 		}
 
 		globalFlags = append(globalFlags, flag)
 	}
 
+	if c.sortFlags() {
+		sortHelpFlags(localFlags)
+		sortHelpFlags(globalFlags)
+	}
+
 	// Display local flags if any
 	if len(localFlags) > 0 {
-		fmt.Println("Flags:")
-		c.displayFormattedFlags(localFlags)
-		fmt.Println()
+		fmt.Fprintln(w, "Flags:")
+		c.displayFormattedFlags(w, localFlags)
+		fmt.Fprintln(w)
 	}
 
 	// Display global flags if any
 	if len(globalFlags) > 0 {
-		fmt.Println("Global Flags:")
-		c.displayFormattedFlags(globalFlags)
-		fmt.Println()
+		fmt.Fprintln(w, "Global Flags:")
+		c.displayFormattedFlags(w, globalFlags)
+		fmt.Fprintln(w)
 	}
 
 	// Display arguments if any
 	if len(c.Arguments) > 0 {
-		fmt.Println("Arguments:")
+		fmt.Fprintln(w, "Arguments:")
 
 		// Find maximum width for argument names to align descriptions
 		maxArgWidth := 0
@@ -141,9 +208,14 @@ func (c *Command) ShowHelp() {
 		}
 
 		for _, arg := range c.Arguments {
-			required := ""
+			note := ""
+			if choices := arg.getChoices(); len(choices) > 0 {
+				note = fmt.Sprintf(" (choices: %s)", strings.Join(choices, ", "))
+			}
 			if arg.isRequired() {
-				required = " (Required)"
+				note += " (Required)"
+			} else if defaultText := arg.defaultText(); defaultText != "" {
+				note += fmt.Sprintf(" (default: %s)", defaultText)
 			}
 
 			argNameWithType := arg.name() + " " + arg.typeText()
@@ -154,17 +226,104 @@ func (c *Command) ShowHelp() {
 			}
 
 			// Print argument name and type with padding
-			fmt.Printf("   %-*s", maxArgWidth, argNameWithType)
+			fmt.Fprintf(w, "   %-*s", maxArgWidth, argNameWithType)
 
 			// Print the description with proper wrapping
-			c.printWrappedText(arg.usage()+required, maxArgWidth+3, 80)
-			fmt.Println()
+			c.printWrappedText(w, arg.usage()+note, maxArgWidth+3, 80)
+			fmt.Fprintln(w)
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
+	}
+
+	// Display examples if any, these are shown as given without wrapping
+	if len(c.Examples) > 0 {
+		fmt.Fprintln(w, "Examples:")
+		for _, example := range c.Examples {
+			for _, line := range strings.Split(strings.TrimSpace(example), "\n") {
+				fmt.Fprintf(w, "   %s\n", line)
+			}
+		}
+		fmt.Fprintln(w)
 	}
 }
 
-func (c *Command) displayFormattedFlags(flags []Flag) {
+// sortFlags returns true if SortFlags is set on the command or one of its parents
+func (c *Command) sortFlags() bool {
+	if c.SortFlags {
+		return true
+	}
+	for _, cmd := range c.commandChain {
+		if cmd.SortFlags {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCommandList writes the subcommands of the command, if any command sets a Group then the commands are listed under
+// their group headings sorted by group and then name, with the ungrouped commands first
+func (c *Command) writeCommandList(w io.Writer) {
+	if len(c.Commands) == 0 {
+		return
+	}
+
+	const defaultGroup = "Available Commands"
+
+	groups := map[string][]*Command{}
+	var names []string
+	for _, cmd := range c.Commands {
+		group := cmd.Group
+		if group == "" {
+			group = defaultGroup
+		}
+		if _, ok := groups[group]; !ok && group != defaultGroup {
+			names = append(names, group)
+		}
+		groups[group] = append(groups[group], cmd)
+	}
+
+	// Without groups the commands are listed in the order they're defined
+	if len(names) > 0 {
+		sort.Strings(names)
+		for _, cmds := range groups {
+			sort.SliceStable(cmds, func(i, j int) bool { return cmds[i].Name < cmds[j].Name })
+		}
+	}
+	if _, ok := groups[defaultGroup]; ok {
+		names = append([]string{defaultGroup}, names...)
+	}
+
+	for _, group := range names {
+		fmt.Fprintln(w, group+":")
+		for _, cmd := range groups[group] {
+			fmt.Fprintf(w, "   %-15s %s\n", strings.Join(append([]string{cmd.Name}, cmd.Aliases...), ", "), cmd.Usage)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// sortHelpFlags sorts flags for the help, required flags first then by name, with --help and --version last
+func sortHelpFlags(flags []Flag) {
+	rank := func(flag Flag) int {
+		switch {
+		case flag.getName() == "help" || flag.getName() == "version":
+			return 2
+		case flag.isRequired():
+			return 0
+		default:
+			return 1
+		}
+	}
+
+	sort.SliceStable(flags, func(i, j int) bool {
+		if ri, rj := rank(flags[i]), rank(flags[j]); ri != rj {
+			return ri < rj
+		}
+		return flags[i].getName() < flags[j].getName()
+	})
+}
+
+func (c *Command) displayFormattedFlags(w io.Writer, flags []Flag) {
 	// Find maximum width for flag definitions to align descriptions
 	maxDefWidth := 0
 	for _, flag := range flags {
@@ -186,6 +345,9 @@ func (c *Command) displayFormattedFlags(flags []Flag) {
 		def := flag.flagDefinition()
 		desc := flag.getUsage()
 		defaultValue := flag.defaultValueText()
+		if value, ok := c.flagDefault(flag); ok {
+			defaultValue = flag.defaultOverrideText(value)
+		}
 
 		// Truncate definition if it's too long
 		if len(def) > maxDefWidth-2 && maxDefWidth == 40 {
@@ -193,7 +355,12 @@ func (c *Command) displayFormattedFlags(flags []Flag) {
 		}
 
 		// Print flag definition with padding
-		fmt.Printf("   %-*s", maxDefWidth, def)
+		fmt.Fprintf(w, "   %-*s", maxDefWidth, def)
+
+		// Add the accepted values if restricted
+		if choices := flag.getChoices(); len(choices) > 0 {
+			desc += fmt.Sprintf(" (choices: %s)", strings.Join(choices, ", "))
+		}
 
 		// Add default value if available
 		if defaultValue != "" {
@@ -206,11 +373,11 @@ func (c *Command) displayFormattedFlags(flags []Flag) {
 		}
 
 		// Print the description with proper wrapping
-		c.printWrappedText(desc, maxDefWidth+3, 80)
+		c.printWrappedText(w, desc, maxDefWidth+3, 80)
 
 		// Add environment variable and config path info on new lines if available
 		indent := strings.Repeat(" ", maxDefWidth+3)
-		envVars := flag.getEnvVars()
+		envVars := c.flagEnvVars(flag)
 		configPaths := flag.getConfigPaths()
 
 		// Build sources line for both env vars and config paths
@@ -224,21 +391,21 @@ func (c *Command) displayFormattedFlags(flags []Flag) {
 
 		// Print sources on the same line if any exist
 		if len(sources) > 0 {
-			fmt.Printf("\n%s(%s)\n", indent, strings.Join(sources, ", "))
+			fmt.Fprintf(w, "\n%s(%s)\n", indent, strings.Join(sources, ", "))
 		}
 
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 }
 
 // Helper function to print wrapped text with proper indentation
-func (c *Command) printWrappedText(text string, indent, width int) {
+func (c *Command) printWrappedText(w io.Writer, text string, indent, width int) {
 	// Calculate available width for text
 	availWidth := width - indent
 
 	// If text fits on one line, just print it
 	if len(text) <= availWidth {
-		fmt.Print(text)
+		fmt.Fprint(w, text)
 		return
 	}
 
@@ -263,10 +430,10 @@ func (c *Command) printWrappedText(text string, indent, width int) {
 		if len(line)+len(word)+1 > availWidth {
 			// Print current line
 			if firstLine {
-				fmt.Print(line)
+				fmt.Fprint(w, line)
 				firstLine = false
 			} else {
-				fmt.Printf("\n%s%s", strings.Repeat(" ", indent), line)
+				fmt.Fprintf(w, "\n%s%s", strings.Repeat(" ", indent), line)
 			}
 			line = word
 		} else {
@@ -281,10 +448,10 @@ func (c *Command) printWrappedText(text string, indent, width int) {
 	// Print the last line of main text
 	if line != "" {
 		if firstLine {
-			fmt.Print(line)
+			fmt.Fprint(w, line)
 			firstLine = false
 		} else {
-			fmt.Printf("\n%s%s", strings.Repeat(" ", indent), line)
+			fmt.Fprintf(w, "\n%s%s", strings.Repeat(" ", indent), line)
 		}
 	}
 
@@ -292,10 +459,10 @@ func (c *Command) printWrappedText(text string, indent, width int) {
 	if defaultPart != "" {
 		// If default part fits on current line, append it
 		if !firstLine && len(line)+len(defaultPart) <= availWidth {
-			fmt.Print(defaultPart)
+			fmt.Fprint(w, defaultPart)
 		} else {
 			// Otherwise, put default part on its own line
-			fmt.Printf("\n%s%s", strings.Repeat(" ", indent), strings.Trim(defaultPart, " "))
+			fmt.Fprintf(w, "\n%s%s", strings.Repeat(" ", indent), strings.Trim(defaultPart, " "))
 		}
 	}
 }
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type helpJSONCommand struct {
+	Name        string             `json:"name"`
+	Aliases     []string           `json:"aliases,omitempty"`
+	Usage       string             `json:"usage,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Group       string             `json:"group,omitempty"`
+	Examples    []string           `json:"examples,omitempty"`
+	Flags       []helpJSONFlag     `json:"flags,omitempty"`
+	Arguments   []helpJSONArgument `json:"arguments,omitempty"`
+	Commands    []helpJSONCommand  `json:"commands,omitempty"`
+}
+
+type helpJSONFlag struct {
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	Default  string   `json:"default,omitempty"`
+	Required bool     `json:"required"`
+	Global   bool     `json:"global"`
+	Usage    string   `json:"usage,omitempty"`
+}
+
+type helpJSONArgument struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required"`
+	Usage    string `json:"usage,omitempty"`
+}
+
+// HelpJSON writes the command and its subcommands to w as JSON, with the flags and arguments of each command,
+// e.g. to generate documentation or a wrapper for the CLI. Hidden flags are left out.
+func (c *Command) HelpJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(c.helpJSON(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// helpJSON builds the description of the command and its subcommands for HelpJSON
+func (c *Command) helpJSON() helpJSONCommand {
+	result := helpJSONCommand{
+		Name:        c.Name,
+		Aliases:     c.Aliases,
+		Usage:       c.Usage,
+		Description: c.Description,
+		Group:       c.Group,
+		Examples:    c.Examples,
+	}
+
+	for _, flag := range c.Flags {
+		if flag.isHidden() {
+			continue
+		}
+
+		defaultValue := flag.defaultValueText()
+		if value, ok := c.flagDefault(flag); ok {
+			defaultValue = flag.defaultOverrideText(value)
+		}
+
+		result.Flags = append(result.Flags, helpJSONFlag{
+			Name:     flag.getName(),
+			Aliases:  flag.getAliases(),
+			Type:     flag.typeText(),
+			Default:  defaultValue,
+			Required: flag.isRequired(),
+			Global:   flag.isGlobal(),
+			Usage:    flag.getUsage(),
+		})
+	}
+
+	for _, arg := range c.Arguments {
+		result.Arguments = append(result.Arguments, helpJSONArgument{
+			Name:     arg.name(),
+			Type:     arg.typeText(),
+			Required: arg.isRequired(),
+			Usage:    arg.usage(),
+		})
+	}
+
+	for _, sub := range c.Commands {
+		result.Commands = append(result.Commands, sub.helpJSON())
+	}
+
+	return result
+}
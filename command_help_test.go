@@ -0,0 +1,375 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestHelpString(t *testing.T) {
+	cmd := &Command{
+		Name:  "app",
+		Usage: "Manage the app",
+		Flags: []Flag{
+			&StringFlag{Name: "listen", Usage: "Address to listen on", DefaultValue: ":8080"},
+			&BoolFlag{Name: "debug", Usage: "Enable debug output"},
+		},
+		Commands: []*Command{
+			{Name: "start", Usage: "Start the server"},
+		},
+	}
+
+	// The help is available before the command has been parsed
+	if help := cmd.HelpString(); !strings.Contains(help, "app - Manage the app") {
+		t.Errorf("expected name and usage in unparsed help, got:\n%s", help)
+	}
+
+	os.Args = []string{"app"}
+	if err := cmd.ReloadFlags(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	help := cmd.HelpString()
+	for _, want := range []string{
+		"app - Manage the app",
+		"--listen",
+		"Address to listen on",
+		"(default: :8080)",
+		"--debug",
+		"Enable debug output",
+		"start",
+		"Start the server",
+	} {
+		if !strings.Contains(help, want) {
+			t.Errorf("expected help to contain %q, got:\n%s", want, help)
+		}
+	}
+}
+
+func TestHelpShowsFlagChoices(t *testing.T) {
+	cmd := &Command{
+		Name: "app",
+		Flags: []Flag{
+			&StringFlag{Name: "log-level", Usage: "Log level", Choices: []string{"debug", "info"}, DefaultValue: "info"},
+		},
+	}
+
+	if help := cmd.HelpString(); !strings.Contains(help, "Log level (choices: debug, info) (default: info)") {
+		t.Errorf("expected choices in help, got:\n%s", help)
+	}
+}
+
+func TestHelpShowsCommandAliases(t *testing.T) {
+	cmd := &Command{
+		Name: "app",
+		Commands: []*Command{
+			{Name: "remove", Aliases: []string{"rm", "delete"}, Usage: "Remove an item"},
+			{Name: "list", Usage: "List items"},
+		},
+	}
+
+	help := cmd.HelpString()
+	if !strings.Contains(help, "remove, rm, delete Remove an item") {
+		t.Errorf("expected aliases in help, got:\n%s", help)
+	}
+	if !strings.Contains(help, "list            List items") {
+		t.Errorf("expected commands without aliases to be unchanged, got:\n%s", help)
+	}
+}
+
+func TestHelpShowsExamples(t *testing.T) {
+	cmd := &Command{
+		Name: "app",
+		Examples: []string{
+			"app serve --port 8080",
+			"# Serve with TLS\napp serve --tls",
+		},
+	}
+
+	want := "Examples:\n   app serve --port 8080\n   # Serve with TLS\n   app serve --tls\n\n"
+	if help := cmd.HelpString(); !strings.HasSuffix(help, want) {
+		t.Errorf("expected examples at the end of help, got:\n%s", help)
+	}
+}
+
+func TestHelpShowsMapFlagType(t *testing.T) {
+	cmd := &Command{
+		Name:  "app",
+		Flags: []Flag{&StringMapFlag{Name: "label", Usage: "Labels to apply"}},
+	}
+
+	if help := cmd.HelpString(); !strings.Contains(help, "--label key=value") {
+		t.Errorf("expected key=value type in help, got:\n%s", help)
+	}
+}
+
+func TestHelpShowsBoolDefaults(t *testing.T) {
+	cmd := &Command{
+		Name: "app",
+		Flags: []Flag{
+			&BoolFlag{Name: "color", Usage: "Colour output", DefaultValue: true},
+			&BoolFlag{Name: "debug", Usage: "Debug output"},
+			&BoolFlag{Name: "quiet", Usage: "Quiet output", DefaultValue: true, HideDefault: true},
+		},
+	}
+
+	help := cmd.HelpString()
+	for _, want := range []string{"Colour output (default: true)", "Debug output (default: false)"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("expected help to contain %q, got:\n%s", want, help)
+		}
+	}
+	if strings.Contains(help, "Quiet output (default") {
+		t.Errorf("expected hidden default not to be shown, got:\n%s", help)
+	}
+}
+
+func TestHelpSortsFlags(t *testing.T) {
+	newCmd := func(sorted bool) *Command {
+		return &Command{
+			Name:      "app",
+			Version:   "1.0.0",
+			SortFlags: sorted,
+			Flags: []Flag{
+				&StringFlag{Name: "zone", Usage: "Zone"},
+				&StringFlag{Name: "token", Usage: "Token", Required: true},
+				&BoolFlag{Name: "debug", Usage: "Debug"},
+				&BoolFlag{Name: "secret", Usage: "Secret", Hidden: true},
+				&StringFlag{Name: "account", Usage: "Account", Required: true},
+				&BoolFlag{Name: "all", Usage: "All", Global: true},
+			},
+			Run: func(ctx context.Context, cmd *Command) error { return nil },
+		}
+	}
+
+	order := func(help string, names ...string) []int {
+		positions := make([]int, len(names))
+		for i, name := range names {
+			positions[i] = strings.Index(help, "--"+name+" ")
+		}
+		return positions
+	}
+
+	cmd := newCmd(true)
+	cmd.SetArgs([]string{"--help"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	help := cmd.HelpString()
+	if strings.Contains(help, "--secret") {
+		t.Errorf("expected hidden flag to be excluded, got:\n%s", help)
+	}
+	positions := order(help, "account", "token", "debug", "zone", "version", "all", "help")
+	if !sort.IntsAreSorted(positions) || positions[0] < 0 {
+		t.Errorf("expected sorted flags, got positions %v in:\n%s", positions, help)
+	}
+
+	cmd = newCmd(false)
+	cmd.SetArgs([]string{"--help"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	positions = order(cmd.HelpString(), "zone", "token", "debug", "account", "version")
+	if !sort.IntsAreSorted(positions) || positions[0] < 0 {
+		t.Errorf("expected flags in declaration order, got positions %v", positions)
+	}
+}
+
+func TestHelpFunc(t *testing.T) {
+	var shown []string
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			HelpFunc: func(c *Command) {
+				shown = append(shown, "root:"+c.Name)
+			},
+			Commands: []*Command{
+				{
+					Name: "serve",
+					Run:  func(ctx context.Context, cmd *Command) error { return nil },
+				},
+				{
+					Name: "admin",
+					Commands: []*Command{
+						{Name: "users", Run: func(ctx context.Context, cmd *Command) error { return nil }},
+					},
+				},
+				{
+					Name: "db",
+					HelpFunc: func(c *Command) {
+						shown = append(shown, "db:"+c.Name)
+					},
+					Commands: []*Command{
+						{Name: "migrate", Run: func(ctx context.Context, cmd *Command) error { return nil }},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"root help flag", []string{"--help"}, []string{"root:app"}},
+		{"subcommand help flag", []string{"serve", "--help"}, []string{"root:serve"}},
+		{"command without run", []string{"admin"}, []string{"root:admin"}},
+		{"closest help func", []string{"db", "migrate", "-h"}, []string{"db:migrate"}},
+		{"no help requested", []string{"serve"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shown = nil
+			root := newRoot()
+			root.SetArgs(tt.args)
+
+			out := captureStdout(t, func() {
+				if err := root.Execute(context.Background()); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			})
+			if strings.Join(shown, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected help funcs %v, got %v", tt.want, shown)
+			}
+			if out != "" {
+				t.Errorf("expected the built-in help not to be shown, got:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestHelpFuncNilShowsBuiltInHelp(t *testing.T) {
+	root := &Command{
+		Name:  "app",
+		Usage: "An application",
+		Run:   func(ctx context.Context, cmd *Command) error { return nil },
+	}
+	root.SetArgs([]string{"--help"})
+
+	out := captureStdout(t, func() {
+		if err := root.Execute(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if out != root.HelpString() {
+		t.Errorf("expected the built-in help, got:\n%s", out)
+	}
+}
+
+func TestHelpGroupsCommands(t *testing.T) {
+	cmd := &Command{
+		Name: "app",
+		Commands: []*Command{
+			{Name: "volume", Usage: "Manage volumes", Group: "Management Commands"},
+			{Name: "version", Usage: "Show the version"},
+			{Name: "ps", Usage: "List containers", Group: "Query Commands"},
+			{Name: "image", Usage: "Manage images", Group: "Management Commands"},
+			{Name: "completion", Usage: "Generate completions"},
+			{Name: "logs", Usage: "Show logs", Group: "Query Commands"},
+		},
+	}
+
+	help := cmd.HelpString()
+	want := `Available Commands:
+   completion      Generate completions
+   version         Show the version
+
+Management Commands:
+   image           Manage images
+   volume          Manage volumes
+
+Query Commands:
+   logs            Show logs
+   ps              List containers
+
+`
+	if !strings.Contains(help, want) {
+		t.Errorf("expected grouped commands:\n%s\ngot:\n%s", want, help)
+	}
+
+	// The command definitions keep their order
+	if cmd.Commands[0].Name != "volume" {
+		t.Errorf("expected the commands not to be reordered, got %s first", cmd.Commands[0].Name)
+	}
+}
+
+func TestHelpWithoutGroupsKeepsOrder(t *testing.T) {
+	cmd := &Command{
+		Name: "app",
+		Commands: []*Command{
+			{Name: "stop", Usage: "Stop"},
+			{Name: "start", Usage: "Start"},
+		},
+	}
+
+	want := "Available Commands:\n   stop            Stop\n   start           Start\n\n"
+	if help := cmd.HelpString(); !strings.Contains(help, want) {
+		t.Errorf("expected commands in definition order, got:\n%s", help)
+	}
+}
+
+func TestHelpJSON(t *testing.T) {
+	root := &Command{
+		Name:  "app",
+		Usage: "An application",
+		Flags: []Flag{
+			&StringFlag{Name: "config", Aliases: []string{"c"}, Usage: "Config file", Global: true, DefaultValue: "app.toml"},
+			&BoolFlag{Name: "internal", Hidden: true},
+		},
+		Commands: []*Command{
+			{
+				Name:        "remove",
+				Aliases:     []string{"rm"},
+				Usage:       "Remove an item",
+				Description: "Removes the item with the given ID",
+				Flags: []Flag{
+					&IntFlag{Name: "retries", Required: true, Usage: "Number of retries"},
+				},
+				Arguments: []Argument{
+					&StringArg{Name: "id", Usage: "Item ID", Required: true},
+				},
+			},
+		},
+	}
+
+	var b strings.Builder
+	if err := root.HelpJSON(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, b.String())
+	}
+
+	want := map[string]any{
+		"name":  "app",
+		"usage": "An application",
+		"flags": []any{
+			map[string]any{"name": "config", "aliases": []any{"c"}, "type": "string", "default": "app.toml", "required": false, "global": true, "usage": "Config file"},
+		},
+		"commands": []any{
+			map[string]any{
+				"name":        "remove",
+				"aliases":     []any{"rm"},
+				"usage":       "Remove an item",
+				"description": "Removes the item with the given ID",
+				"flags": []any{
+					map[string]any{"name": "retries", "type": "int", "required": true, "global": false, "usage": "Number of retries"},
+				},
+				"arguments": []any{
+					map[string]any{"name": "id", "type": "string", "required": true, "usage": "Item ID"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected JSON:\n%s", b.String())
+	}
+}
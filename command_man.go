@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GenerateManPage writes a man page for the command to w in roff format, with the name, synopsis, description, options,
+// arguments and examples of the command and references to its parent and subcommands.
+func (c *Command) GenerateManPage(w io.Writer) error {
+	return c.writeManPage(w, c.commandPath(), c.GetRootCmd().Version)
+}
+
+// GenerateManPages writes a man page for the command and each of its subcommands to dir, named after the command path,
+// e.g. app.1 and app-server-start.1
+func (c *Command) GenerateManPages(dir string) error {
+	return c.generateManPages(dir, c.commandPath(), c.GetRootCmd().Version)
+}
+
+func (c *Command) generateManPages(dir string, path []string, version string) error {
+	f, err := os.Create(filepath.Join(dir, manPageName(path)+".1"))
+	if err != nil {
+		return err
+	}
+
+	err = c.writeManPage(f, path, version)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range c.Commands {
+		if err := sub.generateManPages(dir, append(path[:len(path):len(path)], sub.Name), version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeManPage renders the man page for the command, path holds the names of the commands from the root to the command
+// and version is the version of the root command
+func (c *Command) writeManPage(w io.Writer, path []string, version string) error {
+	var b strings.Builder
+
+	title := strings.ToUpper(manPageName(path))
+	source := path[0]
+	if version != "" {
+		source += " " + version
+	}
+	fmt.Fprintf(&b, ".TH \"%s\" \"1\" \"\" \"%s\" \"%s Manual\"\n", manEscape(title), manEscape(source), manEscape(path[0]))
+
+	// Name
+	b.WriteString(".SH NAME\n")
+	b.WriteString(manEscape(manPageName(path)))
+	if c.Usage != "" {
+		b.WriteString(" \\- " + manEscape(c.Usage))
+	}
+	b.WriteString("\n")
+
+	// Synopsis, required flags and arguments are shown as is and optional ones in brackets
+	flags := c.manFlags()
+	b.WriteString(".SH SYNOPSIS\n")
+	b.WriteString(".B " + manEscape(strings.Join(path, " ")) + "\n")
+	var synopsis []string
+	for _, flag := range flags {
+		option := "\\fB\\-\\-" + manEscape(flag.getName()) + "\\fR"
+		if flag.takesValue() && flag.typeText() != "" {
+			option += " \\fI" + manEscape(flag.typeText()) + "\\fR"
+		}
+		if !flag.isRequired() {
+			option = "[" + option + "]"
+		}
+		synopsis = append(synopsis, option)
+	}
+	for _, arg := range c.Arguments {
+		if arg.isRequired() {
+			synopsis = append(synopsis, "\\fI"+manEscape(arg.name())+"\\fR")
+		} else {
+			synopsis = append(synopsis, "[\\fI"+manEscape(arg.name())+"\\fR]")
+		}
+	}
+	if len(c.Commands) > 0 {
+		synopsis = append(synopsis, "\\fIcommand\\fR")
+	}
+	if len(synopsis) > 0 {
+		b.WriteString(strings.Join(synopsis, " ") + "\n")
+	}
+
+	// Description
+	if c.Description != "" {
+		b.WriteString(".SH DESCRIPTION\n")
+		for i, para := range strings.Split(c.Description, "\n\n") {
+			if i > 0 {
+				b.WriteString(".PP\n")
+			}
+			b.WriteString(manEscape(strings.TrimSpace(para)) + "\n")
+		}
+	}
+
+	// Options
+	if len(flags) > 0 {
+		b.WriteString(".SH OPTIONS\n")
+		for _, flag := range flags {
+			b.WriteString(".TP\n")
+			b.WriteString("\\fB" + manEscape(strings.TrimSpace(flag.flagDefinition())) + "\\fR\n")
+
+			desc := flag.getUsage()
+			if choices := flag.getChoices(); len(choices) > 0 {
+				desc += fmt.Sprintf(" (choices: %s)", strings.Join(choices, ", "))
+			}
+			defaultValue := flag.defaultValueText()
+			if value, ok := c.flagDefault(flag); ok {
+				defaultValue = flag.defaultOverrideText(value)
+			}
+			if defaultValue != "" {
+				desc += fmt.Sprintf(" (default: %s)", defaultValue)
+			}
+			if flag.isRequired() {
+				desc += " (required)"
+			}
+			if envVars := c.flagEnvVars(flag); len(envVars) > 0 {
+				desc += fmt.Sprintf(" (env: %s)", strings.Join(envVars, ", "))
+			}
+			b.WriteString(manEscape(strings.TrimSpace(desc)) + "\n")
+		}
+	}
+
+	// Arguments
+	if len(c.Arguments) > 0 {
+		b.WriteString(".SH ARGUMENTS\n")
+		for _, arg := range c.Arguments {
+			b.WriteString(".TP\n")
+			b.WriteString("\\fI" + manEscape(arg.name()) + "\\fR\n")
+			desc := arg.usage()
+			if choices := arg.getChoices(); len(choices) > 0 {
+				desc += fmt.Sprintf(" (choices: %s)", strings.Join(choices, ", "))
+			}
+			if arg.isRequired() {
+				desc += " (required)"
+			}
+			b.WriteString(manEscape(strings.TrimSpace(desc)) + "\n")
+		}
+	}
+
+	// Examples, shown as given without filling
+	if len(c.Examples) > 0 {
+		b.WriteString(".SH EXAMPLES\n")
+		for i, example := range c.Examples {
+			if i > 0 {
+				b.WriteString(".PP\n")
+			}
+			b.WriteString(".nf\n")
+			for _, line := range strings.Split(example, "\n") {
+				b.WriteString(manEscape(line) + "\n")
+			}
+			b.WriteString(".fi\n")
+		}
+	}
+
+	// References to the parent command and the subcommands
+	var seeAlso []string
+	if len(path) > 1 {
+		seeAlso = append(seeAlso, "\\fB"+manEscape(manPageName(path[:len(path)-1]))+"\\fR(1)")
+	}
+	for _, sub := range c.Commands {
+		seeAlso = append(seeAlso, "\\fB"+manEscape(manPageName(append(path[:len(path):len(path)], sub.Name)))+"\\fR(1)")
+	}
+	if len(seeAlso) > 0 {
+		b.WriteString(".SH SEE ALSO\n")
+		b.WriteString(strings.Join(seeAlso, ", ") + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// manFlags returns the visible flags of the command followed by the inherited global flags
+func (c *Command) manFlags() []Flag {
+	var flags []Flag
+	for _, list := range [][]Flag{c.Flags, c.globalFlags} {
+		for _, flag := range list {
+			if !flag.isHidden() {
+				flags = append(flags, flag)
+			}
+		}
+	}
+	return flags
+}
+
+// manPageName returns the name of the man page for a command path, e.g. app-server-start
+func manPageName(path []string) string {
+	return strings.Join(path, "-")
+}
+
+// manEscape escapes text for roff, backslashes and hyphens are escaped and lines can't start with a control character
+func manEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\e")
+	s = strings.ReplaceAll(s, "-", "\\-")
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = "\\&" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func newManTestCommand() *Command {
+	return &Command{
+		Name:    "app",
+		Version: "1.2.0",
+		Usage:   "Manage items",
+		Flags: []Flag{
+			&StringFlag{Name: "config", Aliases: []string{"c"}, Usage: "Config file", Global: true, DefaultValue: "app.toml"},
+		},
+		Commands: []*Command{
+			{
+				Name:        "remove",
+				Usage:       "Remove an item",
+				Description: "Removes the item.\n\n.Dotted lines are escaped.",
+				Examples:    []string{"# Remove two items\napp remove --force a b"},
+				Flags: []Flag{
+					&IntFlag{Name: "retries", Usage: "Number of retries", Required: true},
+					&BoolFlag{Name: "force", Aliases: []string{"f"}, Usage: "Don't ask"},
+					&BoolFlag{Name: "internal", Hidden: true},
+				},
+				Arguments: []Argument{
+					&StringArg{Name: "id", Usage: "Item ID", Required: true},
+					&StringArg{Name: "other", Usage: "Another item"},
+				},
+			},
+			{Name: "list", Usage: "List items"},
+		},
+	}
+}
+
+func TestGenerateManPage(t *testing.T) {
+	root := newManTestCommand()
+
+	var b strings.Builder
+	if err := root.GenerateManPage(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page := b.String()
+
+	for _, want := range []string{
+		".TH \"APP\" \"1\" \"\" \"app 1.2.0\" \"app Manual\"\n",
+		".SH NAME\napp \\- Manage items\n",
+		".SH SYNOPSIS\n.B app\n[\\fB\\-\\-config\\fR \\fIstring\\fR] \\fIcommand\\fR\n",
+		".TP\n\\fB\\-c, \\-\\-config string\\fR\nConfig file (default: app.toml)\n",
+		".SH SEE ALSO\n\\fBapp\\-remove\\fR(1), \\fBapp\\-list\\fR(1)\n",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected man page to contain %q, got:\n%s", want, page)
+		}
+	}
+}
+
+func TestGenerateManPages(t *testing.T) {
+	dir := t.TempDir()
+	if err := newManTestCommand().GenerateManPages(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	if strings.Join(names, " ") != "app-list.1 app-remove.1 app.1" {
+		t.Fatalf("unexpected man pages: %v", names)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "app-remove.1"))
+	if err != nil {
+		t.Fatalf("failed to read man page: %v", err)
+	}
+	page := string(data)
+
+	for _, want := range []string{
+		".TH \"APP\\-REMOVE\" \"1\" \"\" \"app 1.2.0\" \"app Manual\"\n",
+		".SH NAME\napp\\-remove \\- Remove an item\n",
+		".B app remove\n\\fB\\-\\-retries\\fR \\fIint\\fR [\\fB\\-\\-force\\fR] \\fIid\\fR [\\fIother\\fR]\n",
+		".SH DESCRIPTION\nRemoves the item.\n.PP\n\\&.Dotted lines are escaped.\n",
+		".TP\n\\fB\\-\\-retries int\\fR\nNumber of retries (required)\n",
+		".TP\n\\fIid\\fR\nItem ID (required)\n",
+		".SH EXAMPLES\n.nf\n# Remove two items\napp remove \\-\\-force a b\n.fi\n",
+		".SH SEE ALSO\n\\fBapp\\fR(1)\n",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected man page to contain %q, got:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "internal") {
+		t.Errorf("expected hidden flags to be left out, got:\n%s", page)
+	}
+}
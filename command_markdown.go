@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GenerateMarkdown writes reference documentation for the command and its subcommands to w as Markdown, each command
+// has a section with its usage, description, examples and tables of its flags and arguments. Hidden flags are left out.
+func (c *Command) GenerateMarkdown(w io.Writer) error {
+	var b strings.Builder
+	c.writeMarkdown(&b, c.commandPath(), 1)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdown writes the section for the command and then its subcommands one heading level deeper
+func (c *Command) writeMarkdown(b *strings.Builder, path []string, level int) {
+	cmdName := strings.Join(path, " ")
+
+	fmt.Fprintf(b, "%s %s\n\n", strings.Repeat("#", min(level, 6)), cmdName)
+	if c.Usage != "" {
+		fmt.Fprintf(b, "%s\n\n", c.Usage)
+	}
+	if len(c.Aliases) > 0 {
+		fmt.Fprintf(b, "Aliases: `%s`\n\n", strings.Join(c.Aliases, "`, `"))
+	}
+
+	fmt.Fprintf(b, "```\n%s\n```\n\n", c.usageLine(cmdName))
+
+	if c.Description != "" {
+		for _, para := range strings.Split(c.Description, "\n\n") {
+			fmt.Fprintf(b, "%s\n\n", strings.TrimSpace(para))
+		}
+	}
+
+	// Flags
+	var flags []Flag
+	for _, flag := range c.Flags {
+		if !flag.isHidden() {
+			flags = append(flags, flag)
+		}
+	}
+	if len(flags) > 0 {
+		b.WriteString("| Flag | Type | Default | Description |\n")
+		b.WriteString("|------|------|---------|-------------|\n")
+		for _, flag := range flags {
+			names := []string{"`--" + flag.getName() + "`"}
+			for _, alias := range flag.getAliases() {
+				if len(alias) == 1 {
+					names = append(names, "`-"+alias+"`")
+				} else {
+					names = append(names, "`--"+alias+"`")
+				}
+			}
+
+			defaultValue := flag.defaultValueText()
+			if value, ok := c.flagDefault(flag); ok {
+				defaultValue = flag.defaultOverrideText(value)
+			}
+			if defaultValue != "" {
+				defaultValue = "`" + defaultValue + "`"
+			}
+
+			desc := flag.getUsage()
+			if choices := flag.getChoices(); len(choices) > 0 {
+				desc += fmt.Sprintf(" (choices: %s)", strings.Join(choices, ", "))
+			}
+			if flag.isRequired() {
+				desc += " (required)"
+			}
+
+			fmt.Fprintf(b, "| %s | %s | %s | %s |\n", strings.Join(names, ", "), markdownCell(flag.typeText()),
+				markdownCell(defaultValue), markdownCell(strings.TrimSpace(desc)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Arguments
+	if len(c.Arguments) > 0 {
+		b.WriteString("| Argument | Type | Required | Description |\n")
+		b.WriteString("|----------|------|----------|-------------|\n")
+		for _, arg := range c.Arguments {
+			required := "no"
+			if arg.isRequired() {
+				required = "yes"
+			}
+			desc := arg.usage()
+			if choices := arg.getChoices(); len(choices) > 0 {
+				desc += fmt.Sprintf(" (choices: %s)", strings.Join(choices, ", "))
+			}
+			fmt.Fprintf(b, "| `%s` | %s | %s | %s |\n", arg.name(), markdownCell(arg.typeText()), required, markdownCell(strings.TrimSpace(desc)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Examples
+	if len(c.Examples) > 0 {
+		b.WriteString("Examples:\n\n")
+		for _, example := range c.Examples {
+			fmt.Fprintf(b, "```\n%s\n```\n\n", example)
+		}
+	}
+
+	for _, sub := range c.Commands {
+		sub.writeMarkdown(b, append(path[:len(path):len(path)], sub.Name), level+1)
+	}
+}
+
+// markdownCell escapes text for a Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
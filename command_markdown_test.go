@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateMarkdown(t *testing.T) {
+	root := &Command{
+		Name:  "app",
+		Usage: "Manage items",
+		Flags: []Flag{
+			&StringFlag{Name: "config", Aliases: []string{"c"}, Usage: "Config file", Global: true, DefaultValue: "app.toml"},
+			&BoolFlag{Name: "internal", Usage: "Internal use", Hidden: true},
+		},
+		Commands: []*Command{
+			{
+				Name:        "remove",
+				Aliases:     []string{"rm"},
+				Usage:       "Remove an item",
+				Description: "Removes the item.\n\nThe item can't be recovered.",
+				Examples:    []string{"app remove a"},
+				Flags: []Flag{
+					&IntFlag{Name: "retries", Usage: "Number of retries", Required: true},
+					&StringFlag{Name: "format", Usage: "Output format, text|json", Choices: []string{"text", "json"}},
+				},
+				Arguments: []Argument{
+					&StringArg{Name: "id", Usage: "Item ID", Required: true},
+				},
+				Commands: []*Command{{Name: "all", Usage: "Remove everything"}},
+			},
+		},
+	}
+
+	var b strings.Builder
+	if err := root.GenerateMarkdown(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc := b.String()
+
+	for _, want := range []string{
+		"# app\n\nManage items\n\n```\napp [flags] [command]\n```\n\n",
+		"| `--config`, `-c` | string | `app.toml` | Config file |\n",
+		"## app remove\n\nRemove an item\n\nAliases: `rm`\n\n```\napp remove [flags] <id> [command]\n```\n\nRemoves the item.\n\nThe item can't be recovered.\n\n",
+		"| `--retries` | int |  | Number of retries (required) |\n",
+		"| `--format` | string |  | Output format, text\\|json (choices: text, json) |\n",
+		"| `id` | string | yes | Item ID |\n",
+		"Examples:\n\n```\napp remove a\n```\n\n",
+		"### app remove all\n\nRemove everything\n\n",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", want, doc)
+		}
+	}
+	if strings.Contains(doc, "internal") {
+		t.Errorf("expected hidden flags to be left out, got:\n%s", doc)
+	}
+}
@@ -2,25 +2,100 @@ package cli
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 	"strconv"
+
+	"github.com/paularlott/cli/fuzzy"
 )
 
+// argsForbiddenBy returns the name of the first given flag that forbids positional arguments, or "" if there isn't one,
+// a bool flag only forbids them when true
+func (c *Command) argsForbiddenBy() string {
+	for _, flags := range [][]Flag{c.globalFlags, c.Flags} {
+		for _, flag := range flags {
+			if flag.forbidsArgs() && c.flagGiven(flag.getName()) {
+				return flag.getName()
+			}
+		}
+	}
+	return ""
+}
+
+// checkValidArgs checks the first positional argument is one of the command's ValidArgs, if any are set
+func (c *Command) checkValidArgs(args []string) error {
+	if len(c.ValidArgs) == 0 || len(args) == 0 || slices.Contains(c.ValidArgs, args[0]) {
+		return nil
+	}
+	return fmt.Errorf("invalid argument '%s', must be %s", args[0], fuzzy.FormatSuggestions(c.ValidArgs))
+}
+
+// checkVariadicCount checks the number of values for a variadic argument is within MinArgs and MaxArgs, as for unnamed
+// arguments MaxArgs of NoArgs allows no values
+func (c *Command) checkVariadicCount(arg Argument, n int) error {
+	if n < c.MinArgs {
+		return fmt.Errorf("argument %s needs at least %d values, got %d", arg.name(), c.MinArgs, n)
+	}
+	if c.MaxArgs != UnlimitedArgs && n > c.MaxArgs {
+		return fmt.Errorf("argument %s accepts at most %d values, got %d", arg.name(), c.MaxArgs, n)
+	}
+	return nil
+}
+
 func (c *Command) parseArgs(args []string) ([]string, error) {
 	c.parsedArgs = make(map[string]interface{})
+	c.givenArgs = make(map[string]bool)
 
 	// Parse the arguments
-	for _, arg := range c.Arguments {
+	for i, arg := range c.Arguments {
 		if len(args) == 0 {
 			if arg.isRequired() {
 				return args, fmt.Errorf("missing required argument: %s", arg.name())
 			}
-			break // No more args to parse
+
+			// No more args to parse, the rest of the arguments take their defaults, a variadic argument still needs MinArgs
+			// values which its default can supply
+			for _, rest := range c.Arguments[i:] {
+				rest.setDefault(c)
+				if rest.isVariadic() {
+					n := 0
+					if value, ok := c.parsedArgs[rest.name()]; ok {
+						n = reflect.ValueOf(value).Len()
+					}
+					if err := c.checkVariadicCount(rest, n); err != nil {
+						return args, err
+					}
+				}
+			}
+			break
+		}
+		c.givenArgs[arg.name()] = true
+
+		// A variadic argument takes all the remaining arguments, limited by MinArgs and MaxArgs
+		if arg.isVariadic() {
+			if err := c.checkVariadicCount(arg, len(args)); err != nil {
+				return args, err
+			}
+			for _, value := range args {
+				if err := arg.checkChoice(value); err != nil {
+					return args, err
+				}
+			}
+			if err := c.parseVariadicArg(arg, args); err != nil {
+				return args, err
+			}
+			args = nil
+			break
 		}
 
 		// Get the next argument
 		value := args[0]
 		args = args[1:]
 
+		if err := arg.checkChoice(value); err != nil {
+			return args, err
+		}
+
 		switch arg := arg.(type) {
 		case *StringArg:
 			c.parsedArgs[arg.name()] = value
@@ -28,16 +103,16 @@ func (c *Command) parseArgs(args []string) ([]string, error) {
 				*arg.AssignTo = value
 			}
 		case *IntArg:
-			intVal, err := strconv.Atoi(value)
+			intVal, err := strconv.ParseInt(value, 0, 0)
 			if err != nil {
 				return args, fmt.Errorf("invalid integer value for argument %s: %s", arg.name(), value)
 			}
-			c.parsedArgs[arg.name()] = intVal
+			c.parsedArgs[arg.name()] = int(intVal)
 			if arg.AssignTo != nil {
-				*arg.AssignTo = intVal
+				*arg.AssignTo = int(intVal)
 			}
 		case *Int8Arg:
-			int8Val, err := strconv.ParseInt(value, 10, 8)
+			int8Val, err := strconv.ParseInt(value, 0, 8)
 			if err != nil {
 				return args, fmt.Errorf("invalid int8 value for argument %s: %s", arg.name(), value)
 			}
@@ -46,7 +121,7 @@ func (c *Command) parseArgs(args []string) ([]string, error) {
 				*arg.AssignTo = int8(int8Val)
 			}
 		case *Int16Arg:
-			int16Val, err := strconv.ParseInt(value, 10, 16)
+			int16Val, err := strconv.ParseInt(value, 0, 16)
 			if err != nil {
 				return args, fmt.Errorf("invalid int16 value for argument %s: %s", arg.name(), value)
 			}
@@ -55,7 +130,7 @@ func (c *Command) parseArgs(args []string) ([]string, error) {
 				*arg.AssignTo = int16(int16Val)
 			}
 		case *Int32Arg:
-			int32Val, err := strconv.ParseInt(value, 10, 32)
+			int32Val, err := strconv.ParseInt(value, 0, 32)
 			if err != nil {
 				return args, fmt.Errorf("invalid int32 value for argument %s: %s", arg.name(), value)
 			}
@@ -64,7 +139,7 @@ func (c *Command) parseArgs(args []string) ([]string, error) {
 				*arg.AssignTo = int32(int32Val)
 			}
 		case *Int64Arg:
-			int64Val, err := strconv.ParseInt(value, 10, 64)
+			int64Val, err := strconv.ParseInt(value, 0, 64)
 			if err != nil {
 				return args, fmt.Errorf("invalid int64 value for argument %s: %s", arg.name(), value)
 			}
@@ -73,45 +148,45 @@ func (c *Command) parseArgs(args []string) ([]string, error) {
 				*arg.AssignTo = int64Val
 			}
 		case *UintArg:
-			uintVal, err := strconv.ParseUint(value, 10, 64)
+			uintVal, err := strconv.ParseUint(value, 0, 64)
 			if err != nil {
-				return args, fmt.Errorf("invalid uint value for argument %s: %s", arg.name(), value)
+				return args, uintParseError("argument "+arg.name(), value, 64, err)
 			}
 			c.parsedArgs[arg.name()] = uint(uintVal)
 			if arg.AssignTo != nil {
 				*arg.AssignTo = uint(uintVal)
 			}
 		case *Uint8Arg:
-			uint8Val, err := strconv.ParseUint(value, 10, 8)
+			uint8Val, err := strconv.ParseUint(value, 0, 8)
 			if err != nil {
-				return args, fmt.Errorf("invalid uint8 value for argument %s: %s", arg.name(), value)
+				return args, uintParseError("argument "+arg.name(), value, 8, err)
 			}
 			c.parsedArgs[arg.name()] = uint8(uint8Val)
 			if arg.AssignTo != nil {
 				*arg.AssignTo = uint8(uint8Val)
 			}
 		case *Uint16Arg:
-			uint16Val, err := strconv.ParseUint(value, 10, 16)
+			uint16Val, err := strconv.ParseUint(value, 0, 16)
 			if err != nil {
-				return args, fmt.Errorf("invalid uint16 value for argument %s: %s", arg.name(), value)
+				return args, uintParseError("argument "+arg.name(), value, 16, err)
 			}
 			c.parsedArgs[arg.name()] = uint16(uint16Val)
 			if arg.AssignTo != nil {
 				*arg.AssignTo = uint16(uint16Val)
 			}
 		case *Uint32Arg:
-			uint32Val, err := strconv.ParseUint(value, 10, 32)
+			uint32Val, err := strconv.ParseUint(value, 0, 32)
 			if err != nil {
-				return args, fmt.Errorf("invalid uint32 value for argument %s: %s", arg.name(), value)
+				return args, uintParseError("argument "+arg.name(), value, 32, err)
 			}
 			c.parsedArgs[arg.name()] = uint32(uint32Val)
 			if arg.AssignTo != nil {
 				*arg.AssignTo = uint32(uint32Val)
 			}
 		case *Uint64Arg:
-			uint64Val, err := strconv.ParseUint(value, 10, 64)
+			uint64Val, err := strconv.ParseUint(value, 0, 64)
 			if err != nil {
-				return args, fmt.Errorf("invalid uint64 value for argument %s: %s", arg.name(), value)
+				return args, uintParseError("argument "+arg.name(), value, 64, err)
 			}
 			c.parsedArgs[arg.name()] = uint64Val
 			if arg.AssignTo != nil {
@@ -158,3 +233,47 @@ func (c *Command) parseArgs(args []string) ([]string, error) {
 
 	return args, nil
 }
+
+// parseVariadicArg converts the values for a variadic argument to its slice type
+func (c *Command) parseVariadicArg(arg Argument, values []string) error {
+	switch arg := arg.(type) {
+	case *StringSliceArg:
+		return setVariadicArg(c, arg, values, func(v string) (string, error) { return v, nil })
+	case *IntSliceArg:
+		return setVariadicArg(c, arg, values, func(v string) (int, error) {
+			i, err := strconv.ParseInt(v, 0, 0)
+			return int(i), err
+		})
+	case *Int64SliceArg:
+		return setVariadicArg(c, arg, values, func(v string) (int64, error) { return strconv.ParseInt(v, 0, 64) })
+	case *UintSliceArg:
+		return setVariadicArg(c, arg, values, func(v string) (uint, error) {
+			i, err := strconv.ParseUint(v, 0, 0)
+			return uint(i), err
+		})
+	case *Uint64SliceArg:
+		return setVariadicArg(c, arg, values, func(v string) (uint64, error) { return strconv.ParseUint(v, 0, 64) })
+	case *Float64SliceArg:
+		return setVariadicArg(c, arg, values, func(v string) (float64, error) { return strconv.ParseFloat(v, 64) })
+	}
+	return fmt.Errorf("unsupported type for variadic argument %s", arg.name())
+}
+
+// setVariadicArg parses each value and stores the slice as the value of the argument
+func setVariadicArg[T any](c *Command, arg *ArgumentTyped[[]T], values []string, parse func(string) (T, error)) error {
+	result := make([]T, 0, len(values))
+	for _, value := range values {
+		v, err := parse(value)
+		if err != nil {
+			var zero T
+			return fmt.Errorf("invalid %s value for argument %s: %s", GetTypeText(zero), arg.name(), value)
+		}
+		result = append(result, v)
+	}
+
+	c.parsedArgs[arg.name()] = result
+	if arg.AssignTo != nil {
+		*arg.AssignTo = result
+	}
+	return nil
+}
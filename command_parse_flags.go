@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -46,14 +47,33 @@ func (c *Command) parseFlags(args []string) ([]string, error) {
 			}
 
 			flag, exists := longFlags[flagName]
+
+			// Check for --no-flag to set a bool flag to false, before trying abbreviations
+			if !exists {
+				if negated := negatedBoolFlag(flagName, longFlags, shortFlags); negated != nil {
+					if hasValue {
+						return remainingArgs, fmt.Errorf("flag --%s does not take a value", flagName)
+					}
+					flag, exists = negated, true
+					value, hasValue = "false", true
+				}
+			}
+
+			if !exists && c.GetRootCmd().AllowFlagPrefix {
+				var err error
+				if flag, err = matchFlagPrefix(flagName, longFlags); err != nil {
+					return remainingArgs, err
+				}
+				exists = flag != nil
+			}
 			if !exists {
 				return remainingArgs, fmt.Errorf("unknown flag: --%s", flagName)
 			}
 
-			if err := c.parseFlag(flag, value, hasValue, args, &i, parsed); err != nil {
+			if err := c.parseFlag(flag, value, hasValue, args, &i, parsed, shortFlags); err != nil {
 				return remainingArgs, err
 			}
-		} else if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+		} else if len(arg) > 1 && isFlagArg(arg, shortFlags) {
 			// Short flag(s) (-f or -abc for bundled flags)
 			flagChars := arg[1:]
 
@@ -70,22 +90,17 @@ func (c *Command) parseFlags(args []string) ([]string, error) {
 				var value string
 				hasValue := false
 
-				// If this is the last flag in a bundle and it's not a bool, check for value
-				if isLast {
-					switch flag.(type) {
-					case *BoolFlag:
-						// Bool flags don't need values
-					default:
-						// Check if next arg is a value
-						if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-							value = args[i+1]
-							hasValue = true
-							i++ // consume the value
-						}
+				// If this is the last flag in a bundle and it takes a value, e.g. not a bool, check for value
+				if isLast && flag.takesValue() {
+					// Check if next arg is a value
+					if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+						value = args[i+1]
+						hasValue = true
+						i++ // consume the value
 					}
 				}
 
-				if err := c.parseFlag(flag, value, hasValue, args, &i, parsed); err != nil {
+				if err := c.parseFlag(flag, value, hasValue, args, &i, parsed, shortFlags); err != nil {
 					return remainingArgs, err
 				}
 			}
@@ -102,10 +117,10 @@ func (c *Command) parseFlags(args []string) ([]string, error) {
 	return remainingArgs, nil
 }
 
-func (c *Command) parseFlag(flag Flag, value string, hasValue bool, args []string, i *int, parsed map[string]interface{}) error {
-	if _, ok := flag.(*BoolFlag); !ok {
+func (c *Command) parseFlag(flag Flag, value string, hasValue bool, args []string, i *int, parsed map[string]interface{}, shortFlags map[string]Flag) error {
+	if flag.takesValue() {
 		if !hasValue {
-			if *i+1 >= len(args) || strings.HasPrefix(args[*i+1], "-") {
+			if *i+1 >= len(args) || isFlagArg(args[*i+1], shortFlags) {
 				return fmt.Errorf("flag --%s requires a value", flag.getName())
 			}
 			value = args[*i+1]
@@ -115,3 +130,59 @@ func (c *Command) parseFlag(flag Flag, value string, hasValue bool, args []strin
 
 	return flag.parseString(value, hasValue, parsed)
 }
+
+// isNegativeNumber returns true if arg starts with a - followed by a digit, e.g. -5 or -1.5
+func isNegativeNumber(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-' && arg[1] >= '0' && arg[1] <= '9'
+}
+
+// isFlagArg returns true if arg is a flag rather than a value or positional argument,
+// a negative number is only a flag if there's a short flag named after its first digit
+func isFlagArg(arg string, shortFlags map[string]Flag) bool {
+	if isNegativeNumber(arg) {
+		_, exists := shortFlags[arg[1:2]]
+		return exists
+	}
+	return strings.HasPrefix(arg, "-")
+}
+
+// negatedBoolFlag returns the bool flag named by a --no-flag form of the flag name or alias, or nil if there isn't one
+func negatedBoolFlag(flagName string, longFlags, shortFlags map[string]Flag) Flag {
+	name, ok := strings.CutPrefix(flagName, "no-")
+	if !ok {
+		return nil
+	}
+
+	flag, exists := longFlags[name]
+	if !exists {
+		flag = shortFlags[name]
+	}
+	if _, isBool := flag.(*BoolFlag); isBool {
+		return flag
+	}
+	return nil
+}
+
+// matchFlagPrefix returns the flag whose long name or alias starts with prefix, erroring if more than one flag matches
+func matchFlagPrefix(prefix string, longFlags map[string]Flag) (Flag, error) {
+	var matched Flag
+	var names []string
+	ambiguous := false
+
+	for name, flag := range longFlags {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, "--"+name)
+			if matched != nil && matched != flag {
+				ambiguous = true
+			}
+			matched = flag
+		}
+	}
+
+	if ambiguous {
+		sort.Strings(names)
+		return nil, fmt.Errorf("ambiguous flag: --%s could be %s", prefix, strings.Join(names, ", "))
+	}
+
+	return matched, nil
+}
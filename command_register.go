@@ -0,0 +1,36 @@
+package cli
+
+import "fmt"
+
+// RegisterGlobalFlag adds a global flag to the command, allowing plugins to contribute flags to the root command
+// before Execute is called. The flag is made global and an error is returned if its name or an alias is already used.
+func (c *Command) RegisterGlobalFlag(flag Flag) error {
+	if err := c.checkFlagConflict(flag); err != nil {
+		return err
+	}
+
+	flag.setGlobal()
+	c.Flags = append(c.Flags, flag)
+
+	return nil
+}
+
+// checkFlagConflict returns an error if the name or an alias of the flag is already used by a flag of the command
+func (c *Command) checkFlagConflict(flag Flag) error {
+	return c.checkFlagConflictWith(c.Flags, flag)
+}
+
+// checkFlagConflictWith returns an error if the name or an alias of the flag is already used by one of the flags
+func (c *Command) checkFlagConflictWith(flags []Flag, flag Flag) error {
+	names := append([]string{flag.getName()}, flag.getAliases()...)
+	for _, existing := range flags {
+		for _, used := range append([]string{existing.getName()}, existing.getAliases()...) {
+			for _, name := range names {
+				if name == used {
+					return fmt.Errorf("command '%s': flag '%s' conflicts with flag '%s' on '%s'", c.Name, flag.getName(), existing.getName(), name)
+				}
+			}
+		}
+	}
+	return nil
+}
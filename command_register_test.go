@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRegisterGlobalFlag(t *testing.T) {
+	var got string
+	root := &Command{
+		Name: "app",
+		Flags: []Flag{
+			&BoolFlag{Name: "verbose", Aliases: []string{"V"}},
+		},
+		Commands: []*Command{
+			{
+				Name: "db",
+				Commands: []*Command{
+					{
+						Name: "migrate",
+						Run: func(ctx context.Context, cmd *Command) error {
+							got = cmd.GetString("plugin-endpoint")
+							return nil
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// A plugin registers its flag without Global set
+	if err := root.RegisterGlobalFlag(&StringFlag{Name: "plugin-endpoint"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	os.Args = []string{"app", "db", "migrate", "--plugin-endpoint", "http://localhost"}
+	if err := root.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "http://localhost" {
+		t.Errorf("expected 'http://localhost', got %q", got)
+	}
+}
+
+func TestRegisterGlobalFlag_Duplicate(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Flags: []Flag{
+			&BoolFlag{Name: "verbose", Aliases: []string{"V"}},
+		},
+	}
+
+	tests := []Flag{
+		&StringFlag{Name: "verbose"},
+		&StringFlag{Name: "level", Aliases: []string{"V"}},
+		&StringFlag{Name: "V"},
+	}
+	for _, flag := range tests {
+		err := root.RegisterGlobalFlag(flag)
+		if err == nil || !strings.Contains(err.Error(), "conflicts with flag 'verbose'") {
+			t.Errorf("flag %q: expected conflict error, got %v", flag.getName(), err)
+		}
+	}
+	if len(root.Flags) != 1 {
+		t.Errorf("conflicting flags should not be added, got %d flags", len(root.Flags))
+	}
+}
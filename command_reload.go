@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"reflect"
+	"sort"
+)
+
+// snapshotParsedFlags records the parsed flags of the command and its subcommands, parsing replaces the map so holding a reference is enough
+func (c *Command) snapshotParsedFlags(snapshot map[*Command]map[string]interface{}) {
+	if c.parsedFlags != nil {
+		snapshot[c] = c.parsedFlags
+	}
+
+	for _, sub := range c.Commands {
+		sub.snapshotParsedFlags(snapshot)
+	}
+}
+
+// notifyFlagChanges calls the OnFlagChanged hook closest to the command for each flag whose value differs from oldFlags
+func (c *Command) notifyFlagChanges(commandSequence []*Command, oldFlags map[string]interface{}) {
+	var onChanged func(name string, oldValue, newValue any)
+	for i := len(commandSequence) - 1; i >= 0; i-- {
+		if commandSequence[i].OnFlagChanged != nil {
+			onChanged = commandSequence[i].OnFlagChanged
+			break
+		}
+	}
+	if onChanged == nil {
+		return
+	}
+
+	names := make(map[string]struct{}, len(c.parsedFlags))
+	for name := range oldFlags {
+		names[name] = struct{}{}
+	}
+	for name := range c.parsedFlags {
+		names[name] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		oldValue, newValue := oldFlags[name], c.parsedFlags[name]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			onChanged(name, oldValue, newValue)
+		}
+	}
+}
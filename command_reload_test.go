@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReloadFlags_OnFlagChanged(t *testing.T) {
+	type change struct {
+		name             string
+		oldValue, newVal any
+	}
+	var changes []change
+
+	cfg, _ := newJSONConfigBase(t, `{"server":{"port":8080,"host":"localhost"}}`)
+	cmd := &Command{
+		Name:       "test",
+		ConfigFile: cfg,
+		Flags: []Flag{
+			&IntFlag{Name: "port", ConfigPath: []string{"server.port"}},
+			&StringFlag{Name: "host", ConfigPath: []string{"server.host"}},
+		},
+		OnFlagChanged: func(name string, oldValue, newValue any) {
+			changes = append(changes, change{name, oldValue, newValue})
+		},
+		Run: func(ctx context.Context, cmd *Command) error { return nil },
+	}
+
+	os.Args = []string{"test"}
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("expected no changes from Execute, got %v", changes)
+	}
+
+	// Simulate the config file being rewritten
+	if err := cfg.SetValue("server.port", 9090); err != nil {
+		t.Fatalf("SetValue error: %v", err)
+	}
+	if err := cmd.ReloadFlags(); err != nil {
+		t.Fatalf("ReloadFlags error: %v", err)
+	}
+
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %v", changes)
+	}
+	if changes[0].name != "port" || changes[0].oldValue != 8080 || changes[0].newVal != 9090 {
+		t.Errorf("unexpected change %+v", changes[0])
+	}
+
+	// Reloading again without changes doesn't fire the callback
+	changes = nil
+	if err := cmd.ReloadFlags(); err != nil {
+		t.Fatalf("ReloadFlags error: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
+
+func TestReloadFlags_InjectsHelpOnce(t *testing.T) {
+	sub := &Command{Name: "serve"}
+	cmd := &Command{Name: "test", Version: "1.0.0", Commands: []*Command{sub}}
+
+	countFlags := func(flags []Flag, name string) int {
+		count := 0
+		for _, flag := range flags {
+			if flag.getName() == name {
+				count++
+			}
+		}
+		return count
+	}
+
+	for i := 0; i < 10; i++ {
+		for _, args := range [][]string{{"test"}, {"test", "serve"}} {
+			os.Args = args
+			if err := cmd.ReloadFlags(); err != nil {
+				t.Fatalf("ReloadFlags error: %v", err)
+			}
+		}
+	}
+
+	if n := countFlags(cmd.Flags, "help"); n != 1 {
+		t.Errorf("root has %d help flags, want 1", n)
+	}
+	if n := countFlags(cmd.Flags, "version"); n != 1 {
+		t.Errorf("root has %d version flags, want 1", n)
+	}
+
+	// The subcommand uses the global help flag of the root rather than adding its own
+	if n := countFlags(append(sub.Flags, sub.globalFlags...), "help"); n != 1 {
+		t.Errorf("subcommand has %d help flags, want 1", n)
+	}
+	if n := strings.Count(sub.HelpString(), "--help"); n != 1 {
+		t.Errorf("subcommand help lists --help %d times, want 1:\n%s", n, sub.HelpString())
+	}
+
+	// A subcommand parsed before its parent keeps its own help flag, it's listed once
+	sub = &Command{Name: "serve"}
+	cmd = &Command{Name: "test", Commands: []*Command{sub}}
+	for _, args := range [][]string{{"test", "serve"}, {"test"}, {"test", "serve"}} {
+		os.Args = args
+		if err := cmd.ReloadFlags(); err != nil {
+			t.Fatalf("ReloadFlags error: %v", err)
+		}
+	}
+	if n := strings.Count(sub.HelpString(), "--help"); n != 1 {
+		t.Errorf("subcommand help lists --help %d times, want 1:\n%s", n, sub.HelpString())
+	}
+}
+
+func TestReloadFlags_SubcommandRunsAfterRoot(t *testing.T) {
+	runs := 0
+	sub := &Command{
+		Name: "sub",
+		Run: func(ctx context.Context, cmd *Command) error {
+			runs++
+			return nil
+		},
+	}
+	cmd := &Command{Name: "test", Commands: []*Command{sub}, Output: &strings.Builder{}}
+
+	// The help flag the subcommand kept from its first run mustn't look given once the root adds its own
+	for _, args := range [][]string{{"sub"}, {}, {"sub"}} {
+		cmd.SetArgs(args)
+		if err := cmd.Execute(context.Background()); err != nil {
+			t.Fatalf("Execute(%v) error: %v", args, err)
+		}
+	}
+	if runs != 2 {
+		t.Errorf("subcommand ran %d times, want 2", runs)
+	}
+	if sub.WantsHelp() {
+		t.Error("subcommand wants help, want false")
+	}
+}
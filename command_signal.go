@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ExecuteWithSignals runs Execute with a context that's cancelled when one of the signals is received, defaulting to
+// SIGINT and SIGTERM, so a long running command can stop and clean up rather than the process being killed.
+// The default handling of the signals is restored when it returns.
+func (c *Command) ExecuteWithSignals(ctx context.Context, sigs ...os.Signal) error {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, sigs...)
+	defer stop()
+
+	return c.Execute(ctx)
+}
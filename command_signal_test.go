@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestExecuteWithSignals(t *testing.T) {
+	var cause error
+	cmd := &Command{
+		Name: "app",
+		Run: func(ctx context.Context, cmd *Command) error {
+			process, err := os.FindProcess(os.Getpid())
+			if err != nil {
+				return err
+			}
+			if err := process.Signal(syscall.SIGTERM); err != nil {
+				t.Skipf("can't send signals on this platform: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				cause = ctx.Err()
+				return nil
+			case <-time.After(5 * time.Second):
+				return errors.New("context was not cancelled")
+			}
+		},
+	}
+
+	cmd.SetArgs([]string{})
+	if err := cmd.ExecuteWithSignals(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(cause, context.Canceled) {
+		t.Errorf("expected the context to be cancelled, got %v", cause)
+	}
+}
+
+func TestExecuteWithSignalsCompletes(t *testing.T) {
+	var ctxErr error
+	cmd := &Command{
+		Name: "app",
+		Run: func(ctx context.Context, cmd *Command) error {
+			ctxErr = ctx.Err()
+			return nil
+		},
+	}
+
+	cmd.SetArgs([]string{})
+	if err := cmd.ExecuteWithSignals(context.Background(), os.Interrupt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ctxErr != nil {
+		t.Errorf("expected a live context while running, got %v", ctxErr)
+	}
+}
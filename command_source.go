@@ -0,0 +1,32 @@
+package cli
+
+// FlagSource identifies where the value of a flag came from
+type FlagSource int
+
+const (
+	SourceNone    FlagSource = iota // The flag isn't known to the command or flags haven't been processed
+	SourceCLI                       // The value was given on the command line
+	SourceEnv                       // The value was read from an environment variable
+	SourceConfig                    // The value was read from the configuration file
+	SourceDefault                   // The flag wasn't set so holds its default value
+)
+
+func (s FlagSource) String() string {
+	switch s {
+	case SourceCLI:
+		return "cli"
+	case SourceEnv:
+		return "env"
+	case SourceConfig:
+		return "config"
+	case SourceDefault:
+		return "default"
+	default:
+		return "none"
+	}
+}
+
+// FlagSource returns where the value of the flag with the given name, or alias, came from
+func (c *Command) FlagSource(name string) FlagSource {
+	return c.flagSources[c.resolveFlagKey(name)]
+}
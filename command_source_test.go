@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFlagSource(t *testing.T) {
+	cfg, _ := newJSONConfigBase(t, `{"server": {"host": "config.local", "port": 9000}}`)
+	t.Setenv("APP_PORT", "7000")
+
+	var cmd *Command
+	cmd = &Command{
+		Name:       "app",
+		ConfigFile: cfg,
+		Flags: []Flag{
+			&StringFlag{Name: "name", Aliases: []string{"n"}},
+			&IntFlag{Name: "port", EnvVars: []string{"APP_PORT"}, ConfigPath: []string{"server.port"}},
+			&StringFlag{Name: "host", ConfigPath: []string{"server.host"}},
+			&IntFlag{Name: "timeout", DefaultValue: 30},
+		},
+		Run: func(ctx context.Context, c *Command) error {
+			return nil
+		},
+	}
+
+	cmd.SetArgs([]string{"--name", "test"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want FlagSource
+	}{
+		{"name", SourceCLI},
+		{"n", SourceCLI},
+		{"port", SourceEnv},
+		{"host", SourceConfig},
+		{"timeout", SourceDefault},
+		{"unknown", SourceNone},
+	}
+
+	for _, tt := range tests {
+		if got := cmd.FlagSource(tt.name); got != tt.want {
+			t.Errorf("FlagSource(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFlagSourceString(t *testing.T) {
+	tests := map[FlagSource]string{
+		SourceNone:    "none",
+		SourceCLI:     "cli",
+		SourceEnv:     "env",
+		SourceConfig:  "config",
+		SourceDefault: "default",
+	}
+
+	for source, want := range tests {
+		if got := source.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
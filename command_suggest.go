@@ -55,15 +55,16 @@ func (c *Command) findSimilarCommands(cmdName string, commands []*Command, maxDi
 }
 
 func (c *Command) displaySuggestions(suggestions []string, remainingArgs []string) {
-	fmt.Printf("Unknown command: %s\n\nDid you mean this?\n", remainingArgs[0])
+	w := c.output()
+	fmt.Fprintf(w, "Unknown command: %s\n\nDid you mean this?\n", remainingArgs[0])
 	if len(suggestions) == 1 {
-		fmt.Printf("   - %s\n", suggestions[0])
+		fmt.Fprintf(w, "   - %s\n", suggestions[0])
 	} else {
 		for _, suggestion := range suggestions {
-			fmt.Printf("   - %s\n", suggestion)
+			fmt.Fprintf(w, "   - %s\n", suggestion)
 		}
 	}
-	fmt.Println()
-	fmt.Printf("Run '%s --help' for usage.\n", c.Name)
-	fmt.Println()
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Run '%s --help' for usage.\n", c.Name)
+	fmt.Fprintln(w)
 }
@@ -2,7 +2,11 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -179,6 +183,63 @@ func TestCommand_Execute_Subcommands(t *testing.T) {
 	}
 }
 
+func TestCommand_Aliases(t *testing.T) {
+	for _, name := range []string{"remove", "rm", "delete"} {
+		t.Run(name, func(t *testing.T) {
+			var ran string
+			var args []string
+			root := &Command{
+				Name: "app",
+				Commands: []*Command{
+					{
+						Name:    "remove",
+						Aliases: []string{"rm", "delete"},
+						MaxArgs: UnlimitedArgs,
+						Run: func(ctx context.Context, cmd *Command) error {
+							ran = cmd.Name
+							args = cmd.GetArgs()
+							if cmd.GetRootCmd() != cmd.commandChain[0] || cmd.GetRootCmd().Name != "app" {
+								t.Errorf("unexpected root command %q", cmd.GetRootCmd().Name)
+							}
+							return nil
+						},
+					},
+				},
+			}
+
+			root.SetArgs([]string{name, "rm"})
+			if err := root.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ran != "remove" {
+				t.Errorf("expected the remove command to run with its canonical name, got %q", ran)
+			}
+			if !reflect.DeepEqual(args, []string{"rm"}) {
+				t.Errorf("expected an alias after the command to be an argument, got %v", args)
+			}
+		})
+	}
+}
+
+func TestCommand_AliasesNotSuggested(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Commands: []*Command{
+			{Name: "remove", Aliases: []string{"delete"}},
+			{Name: "list"},
+		},
+	}
+
+	for _, suggestion := range root.findSimilarCommands("delet", root.Commands, 2) {
+		if suggestion == "delete" {
+			t.Errorf("expected aliases to be excluded from suggestions, got %v", suggestion)
+		}
+	}
+	if got := root.findSimilarCommands("lst", root.Commands, 2); !reflect.DeepEqual(got, []string{"list"}) {
+		t.Errorf("expected suggestion [list], got %v", got)
+	}
+}
+
 func TestCommand_Execute_SubcommandWithHelp(t *testing.T) {
 	childExecuted := false
 
@@ -274,6 +335,128 @@ func TestCommand_Execute_PostRun(t *testing.T) {
 	}
 }
 
+func TestCommand_Execute_PostRunE(t *testing.T) {
+	runErr := errors.New("connection refused")
+	var received error
+
+	root := &Command{
+		Name: "test",
+		PostRunE: func(ctx context.Context, cmd *Command, err error) error {
+			received = err
+			if err != nil {
+				return fmt.Errorf("%s failed: %w", cmd.Name, err)
+			}
+			return nil
+		},
+		PostRun: func(ctx context.Context, cmd *Command) error {
+			t.Error("expected PostRun not to be called when PostRunE is set")
+			return nil
+		},
+		Commands: []*Command{
+			{
+				Name: "fail",
+				Run:  func(ctx context.Context, cmd *Command) error { return runErr },
+			},
+			{
+				Name: "ok",
+				Run:  func(ctx context.Context, cmd *Command) error { return nil },
+			},
+		},
+	}
+
+	root.SetArgs([]string{"fail"})
+	err := root.Execute(context.Background())
+	if received != runErr {
+		t.Errorf("expected PostRunE to receive the Run error, got %v", received)
+	}
+	if !errors.Is(err, runErr) || err.Error() != "fail failed: connection refused" {
+		t.Errorf("expected wrapped Run error, got %v", err)
+	}
+
+	root.SetArgs([]string{"ok"})
+	if err := root.Execute(context.Background()); err != nil || received != nil {
+		t.Errorf("expected no error, got %v and PostRunE received %v", err, received)
+	}
+}
+
+func TestCommand_Execute_PersistentRun(t *testing.T) {
+	type ctxKey string
+	var calls []string
+	record := func(name string) func(ctx context.Context, cmd *Command) (context.Context, error) {
+		return func(ctx context.Context, cmd *Command) (context.Context, error) {
+			calls = append(calls, name)
+			return context.WithValue(ctx, ctxKey(name), true), nil
+		}
+	}
+	recordPost := func(name string) func(ctx context.Context, cmd *Command) error {
+		return func(ctx context.Context, cmd *Command) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	root := &Command{
+		Name:              "app",
+		PersistentPreRun:  record("root persistent pre"),
+		PersistentPostRun: recordPost("root persistent post"),
+		PreRun:            record("root pre"),
+		Commands: []*Command{
+			{
+				Name:              "server",
+				PersistentPreRun:  record("server persistent pre"),
+				PersistentPostRun: recordPost("server persistent post"),
+				Commands: []*Command{
+					{
+						Name:    "start",
+						PreRun:  record("start pre"),
+						PostRun: recordPost("start post"),
+						Run: func(ctx context.Context, cmd *Command) error {
+							if ctx.Value(ctxKey("root persistent pre")) == nil || ctx.Value(ctxKey("server persistent pre")) == nil {
+								t.Error("expected Run to receive the context from each PersistentPreRun")
+							}
+							calls = append(calls, "run")
+							return nil
+						},
+					},
+				},
+			},
+		},
+	}
+
+	root.SetArgs([]string{"server", "start"})
+	if err := root.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"root persistent pre",
+		"server persistent pre",
+		"start pre",
+		"run",
+		"start post",
+		"server persistent post",
+		"root persistent post",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+
+	// An error from a PersistentPreRun stops the rest of the chain and Run, the post run functions still run
+	preErr := errors.New("no logger")
+	root.PersistentPreRun = func(ctx context.Context, cmd *Command) (context.Context, error) {
+		calls = append(calls, "root persistent pre")
+		return ctx, preErr
+	}
+	calls = nil
+	if err := root.Execute(context.Background()); !errors.Is(err, preErr) {
+		t.Fatalf("expected PersistentPreRun error, got %v", err)
+	}
+	expected = []string{"root persistent pre", "start post", "server persistent post", "root persistent post"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+}
+
 func TestCommand_Execute_MinMaxArgs(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -474,7 +657,7 @@ func TestCommand_Execute_UnknownSubcommand(t *testing.T) {
 			},
 			args:       []string{"parent", "extra"},
 			wantErr:    true,
-			errContains: "too many arguments",
+			errContains: "parent: expected no arguments, got 1",
 		},
 	}
 
@@ -511,7 +694,7 @@ func TestCommand_Execute_MinMaxArgs_EdgeCases(t *testing.T) {
 			},
 			args:       []string{"test"},
 			wantErr:    true,
-			errContains: "too few arguments",
+			errContains: "test: expected at least 1 argument, got 0",
 		},
 		{
 			name: "MinArgs=2 MaxArgs=3 with exactly 2 args",
@@ -592,7 +775,7 @@ func TestCommand_Execute_MinMaxArgs_EdgeCases(t *testing.T) {
 			},
 			args:       []string{"test", "arg1", "arg2"},
 			wantErr:    true,
-			errContains: "too many arguments",
+			errContains: "test: expected at most 1 argument, got 2",
 		},
 		{
 			name: "command with subcommands and valid subcommand call",
@@ -624,7 +807,7 @@ func TestCommand_Execute_MinMaxArgs_EdgeCases(t *testing.T) {
 			},
 			args:       []string{"parent", "child"},
 			wantErr:    true,
-			errContains: "too few arguments",
+			errContains: "child: expected at least 1 argument, got 0",
 		},
 	}
 
@@ -643,3 +826,266 @@ func TestCommand_Execute_MinMaxArgs_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestCommand_Execute_ArgCountError(t *testing.T) {
+	tests := []struct {
+		name     string
+		minArgs  int
+		maxArgs  int
+		optional bool
+		args     []string
+		want     string
+	}{
+		{"between", 1, 2, false, []string{"a", "b", "c", "d"}, "server start: expected between 2 and 3 arguments, got 4 (usage: app server start <config> <args...>)"},
+		{"exact", 1, 1, false, []string{"a"}, "server start: expected 2 arguments, got 1 (usage: app server start <config> <args...>)"},
+		{"at least", 2, UnlimitedArgs, false, []string{"a", "b"}, "server start: expected at least 3 arguments, got 2 (usage: app server start <config> <args...>)"},
+		{"optional args", 0, 1, false, []string{"a", "b", "c"}, "server start: expected between 1 and 2 arguments, got 3 (usage: app server start <config> [args...])"},
+		{"optional argument", 0, 0, true, []string{"a", "b"}, "server start: expected at most 1 argument, got 2 (usage: app server start [config])"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &Command{
+				Name: "app",
+				Commands: []*Command{
+					{
+						Name: "server",
+						Commands: []*Command{
+							{
+								Name:      "start",
+								MinArgs:   tt.minArgs,
+								MaxArgs:   tt.maxArgs,
+								Arguments: []Argument{&StringArg{Name: "config", Required: !tt.optional}},
+								Run:       func(ctx context.Context, cmd *Command) error { return nil },
+							},
+						},
+					},
+				},
+			}
+
+			root.SetArgs(append([]string{"server", "start"}, tt.args...))
+			err := root.Execute(context.Background())
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("expected error %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCommand_WantsHelpAndVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		disableHelp    bool
+		disableVersion bool
+		args           []string
+		wantHelp       bool
+		wantVersion    bool
+	}{
+		{name: "nothing requested", args: []string{"test"}},
+		{name: "long help", args: []string{"test", "--help"}, wantHelp: true},
+		{name: "short help", args: []string{"test", "-h"}, wantHelp: true},
+		{name: "long version", args: []string{"test", "--version"}, wantVersion: true},
+		{name: "short version", args: []string{"test", "-v"}, wantVersion: true},
+		{name: "help disabled", disableHelp: true, args: []string{"test", "--help"}},
+		{name: "version disabled", disableVersion: true, args: []string{"test", "--version"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Command{
+				Name:           "test",
+				Version:        "1.0.0",
+				DisableHelp:    tt.disableHelp,
+				DisableVersion: tt.disableVersion,
+			}
+
+			// Provide user defined flags when the built-in ones are disabled
+			if tt.disableHelp {
+				cmd.Flags = append(cmd.Flags, &BoolFlag{Name: "help"})
+			}
+			if tt.disableVersion {
+				cmd.Flags = append(cmd.Flags, &BoolFlag{Name: "version"})
+			}
+
+			os.Args = tt.args
+			if err := cmd.ReloadFlags(); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if cmd.WantsHelp() != tt.wantHelp {
+				t.Errorf("WantsHelp() = %v, want %v", cmd.WantsHelp(), tt.wantHelp)
+			}
+			if cmd.WantsVersion() != tt.wantVersion {
+				t.Errorf("WantsVersion() = %v, want %v", cmd.WantsVersion(), tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestInvokeSubcommand(t *testing.T) {
+	var ran []string
+	root := &Command{
+		Name: "app",
+		Commands: []*Command{
+			{
+				Name:  "greet",
+				Flags: []Flag{&StringFlag{Name: "name", DefaultValue: "world"}},
+				Run: func(ctx context.Context, cmd *Command) error {
+					ran = append(ran, cmd.GetString("name"))
+					return nil
+				},
+			},
+		},
+	}
+
+	os.Args = []string{"app", "--help"}
+	for _, args := range [][]string{{"greet"}, {"greet", "--name", "bob"}, {"greet"}} {
+		if err := root.InvokeSubcommand(context.Background(), args); err != nil {
+			t.Fatalf("InvokeSubcommand(%v) error: %v", args, err)
+		}
+	}
+
+	if want := []string{"world", "bob", "world"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("expected runs %v, got %v", want, ran)
+	}
+	if root.args != nil {
+		t.Error("args override should be cleared after InvokeSubcommand")
+	}
+}
+
+func TestSetArgs(t *testing.T) {
+	for _, name := range []string{"alice", "bob"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			cmd := &Command{
+				Name:  "app",
+				Flags: []Flag{&StringFlag{Name: "name"}, &IntFlag{Name: "count"}},
+				Run: func(ctx context.Context, cmd *Command) error {
+					for i := 0; i < cmd.GetInt("count"); i++ {
+						got = append(got, cmd.GetString("name"))
+					}
+					return nil
+				},
+			}
+
+			cmd.SetArgs([]string{"--name", name, "--count", "50"})
+			for i := 0; i < 20; i++ {
+				got = nil
+				if err := cmd.Execute(context.Background()); err != nil {
+					t.Fatalf("Execute error: %v", err)
+				}
+				if len(got) != 50 || got[0] != name || got[49] != name {
+					t.Fatalf("expected 50 runs with name %q, got %v", name, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCommand_Output(t *testing.T) {
+	newRoot := func(out io.Writer) *Command {
+		return &Command{
+			Name:        "app",
+			Version:     "1.2.3",
+			Suggestions: true,
+			Output:      out,
+			Commands: []*Command{
+				{Name: "start", Usage: "Start the server", Run: func(ctx context.Context, cmd *Command) error { return nil }},
+				{Name: "admin", Commands: []*Command{{Name: "users"}}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{"version", []string{"--version"}, "app version 1.2.3\n", false},
+		{"help", []string{"start", "--help"}, "Start the server", false},
+		{"command without run", []string{"admin"}, "Available Commands:", false},
+		{"suggestions", []string{"stat"}, "Did you mean this?\n   - start\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			root := newRoot(&b)
+			root.SetArgs(tt.args)
+
+			stdout := captureStdout(t, func() {
+				if err := root.Execute(context.Background()); (err != nil) != tt.wantErr {
+					t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+				}
+			})
+			if !strings.Contains(b.String(), tt.want) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.want, b.String())
+			}
+			if stdout != "" {
+				t.Errorf("expected nothing written to stdout, got:\n%s", stdout)
+			}
+		})
+	}
+}
+
+func TestCommand_WriteHelp(t *testing.T) {
+	cmd := &Command{Name: "app", Usage: "An application"}
+
+	var b strings.Builder
+	cmd.WriteHelp(&b)
+	if b.String() != cmd.HelpString() || !strings.Contains(b.String(), "app - An application") {
+		t.Errorf("unexpected help:\n%s", b.String())
+	}
+}
+
+func TestCommand_ShowUsageOnError(t *testing.T) {
+	newRoot := func(show bool) *Command {
+		return &Command{
+			Name:             "myapp",
+			ShowUsageOnError: show,
+			Commands: []*Command{
+				{
+					Name:    "server",
+					MaxArgs: NoArgs,
+					Flags:   []Flag{&IntFlag{Name: "port", Required: true}},
+					Run:     func(ctx context.Context, cmd *Command) error { return nil },
+				},
+				{
+					Name: "fail",
+					Run:  func(ctx context.Context, cmd *Command) error { return errors.New("failed") },
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		show bool
+		args []string
+		want string
+	}{
+		{"missing required flag", true, []string{"server"}, "Run 'myapp server --help' for usage.\n"},
+		{"unknown flag", true, []string{"server", "--port", "1", "--bad"}, "Run 'myapp server --help' for usage.\n"},
+		{"too many arguments", true, []string{"server", "--port", "1", "extra"}, "Run 'myapp server --help' for usage.\n"},
+		{"run error", true, []string{"fail"}, ""},
+		{"disabled", false, []string{"server"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newRoot(tt.show)
+			root.SetArgs(tt.args)
+
+			stderr := captureStderr(t, func() {
+				if err := root.Execute(context.Background()); err == nil {
+					t.Error("expected an error")
+				}
+			})
+			if stderr != tt.want {
+				t.Errorf("expected stderr %q, got %q", tt.want, stderr)
+			}
+		})
+	}
+}
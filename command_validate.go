@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validate checks the command tree for definitions that would make parsing ambiguous, such as
+// flags sharing a name or alias and subcommands sharing a name.
+func (c *Command) Validate() error {
+	return c.validateTree(nil, nil)
+}
+
+func (c *Command) validateTree(path []string, inherited []Flag) error {
+	path = append(path, c.Name)
+	cmdPath := strings.Join(path, " ")
+
+	// Flag names and aliases must be unique across the command and the global flags it inherits, the help, version and
+	// yes flags added by the library when a command is run aren't checked as they're only added when free
+	var own []Flag
+	for _, flag := range c.Flags {
+		if !flag.isBuiltin() {
+			own = append(own, flag)
+		}
+	}
+
+	seen := make(map[string]Flag)
+	globals := append([]Flag{}, inherited...)
+	flags := append(append([]Flag{}, inherited...), own...)
+	for _, flag := range flags {
+		for _, name := range append([]string{flag.getName()}, flag.getAliases()...) {
+			if owner, ok := seen[name]; ok {
+				if owner == flag {
+					return fmt.Errorf("command '%s': flag '%s' declares '%s' more than once", cmdPath, flag.getName(), name)
+				}
+				return fmt.Errorf("command '%s': flag '%s' conflicts with flag '%s' on '%s'", cmdPath, flag.getName(), owner.getName(), name)
+			}
+			seen[name] = flag
+		}
+	}
+
+	for _, flag := range own {
+		if flag.isGlobal() {
+			globals = append(globals, flag)
+		}
+	}
+
+	// A required argument is always given so a default would never be used
+	for _, arg := range c.Arguments {
+		if arg.isRequired() && arg.defaultText() != "" {
+			return fmt.Errorf("command '%s': argument '%s' is required and can't have a default value", cmdPath, arg.name())
+		}
+	}
+
+	// Only one variadic argument is allowed and it must be the last, as it takes all the remaining arguments
+	var variadic []Argument
+	for _, arg := range c.Arguments {
+		if arg.isVariadic() {
+			variadic = append(variadic, arg)
+		}
+	}
+	if len(variadic) > 1 {
+		return fmt.Errorf("command '%s': only one variadic argument is allowed, found '%s' and '%s'", cmdPath, variadic[0].name(), variadic[1].name())
+	}
+	if len(variadic) == 1 && c.Arguments[len(c.Arguments)-1] != variadic[0] {
+		return fmt.Errorf("command '%s': variadic argument '%s' must be the last argument", cmdPath, variadic[0].name())
+	}
+
+	// Subcommand names and aliases must be unique
+	commands := make(map[string]bool)
+	for _, sub := range c.Commands {
+		if commands[sub.Name] {
+			return fmt.Errorf("command '%s': duplicate subcommand '%s'", cmdPath, sub.Name)
+		}
+		commands[sub.Name] = true
+	}
+	for _, sub := range c.Commands {
+		for _, alias := range sub.Aliases {
+			if commands[alias] {
+				return fmt.Errorf("command '%s': alias '%s' of subcommand '%s' is already in use", cmdPath, alias, sub.Name)
+			}
+			commands[alias] = true
+		}
+	}
+
+	for _, sub := range c.Commands {
+		if err := sub.validateTree(path, globals); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCommand_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *Command
+		errContains string
+	}{
+		{
+			name: "valid tree",
+			cmd: &Command{
+				Name:  "app",
+				Flags: []Flag{&BoolFlag{Name: "debug", Aliases: []string{"d"}, Global: true}},
+				Commands: []*Command{
+					{Name: "start", Flags: []Flag{&IntFlag{Name: "port", Aliases: []string{"p"}}}},
+					{Name: "stop"},
+				},
+			},
+		},
+		{
+			name: "duplicate flag name",
+			cmd: &Command{
+				Name:  "app",
+				Flags: []Flag{&StringFlag{Name: "name"}, &StringFlag{Name: "name"}},
+			},
+			errContains: "flag 'name' conflicts with flag 'name' on 'name'",
+		},
+		{
+			name: "alias collides with flag name",
+			cmd: &Command{
+				Name:  "app",
+				Flags: []Flag{&StringFlag{Name: "verbose"}, &BoolFlag{Name: "loud", Aliases: []string{"verbose"}}},
+			},
+			errContains: "flag 'loud' conflicts with flag 'verbose' on 'verbose'",
+		},
+		{
+			name: "duplicate short alias",
+			cmd: &Command{
+				Name:  "app",
+				Flags: []Flag{&StringFlag{Name: "name", Aliases: []string{"n"}}, &IntFlag{Name: "number", Aliases: []string{"n"}}},
+			},
+			errContains: "flag 'number' conflicts with flag 'name' on 'n'",
+		},
+		{
+			name: "alias repeats own name",
+			cmd: &Command{
+				Name:  "app",
+				Flags: []Flag{&StringFlag{Name: "name", Aliases: []string{"name"}}},
+			},
+			errContains: "flag 'name' declares 'name' more than once",
+		},
+		{
+			name: "subcommand flag collides with inherited global",
+			cmd: &Command{
+				Name:  "app",
+				Flags: []Flag{&BoolFlag{Name: "debug", Aliases: []string{"d"}, Global: true}},
+				Commands: []*Command{
+					{Name: "start", Flags: []Flag{&StringFlag{Name: "dir", Aliases: []string{"d"}}}},
+				},
+			},
+			errContains: "command 'app start': flag 'dir' conflicts with flag 'debug' on 'd'",
+		},
+		{
+			name: "duplicate subcommand",
+			cmd: &Command{
+				Name:     "app",
+				Commands: []*Command{{Name: "start"}, {Name: "start"}},
+			},
+			errContains: "command 'app': duplicate subcommand 'start'",
+		},
+		{
+			name: "alias collides with subcommand",
+			cmd: &Command{
+				Name:     "app",
+				Commands: []*Command{{Name: "remove", Aliases: []string{"rm", "list"}}, {Name: "list"}},
+			},
+			errContains: "command 'app': alias 'list' of subcommand 'remove' is already in use",
+		},
+		{
+			name: "duplicate alias",
+			cmd: &Command{
+				Name:     "app",
+				Commands: []*Command{{Name: "remove", Aliases: []string{"rm"}}, {Name: "rmdir", Aliases: []string{"rm"}}},
+			},
+			errContains: "command 'app': alias 'rm' of subcommand 'rmdir' is already in use",
+		},
+		{
+			name: "required argument with default",
+			cmd: &Command{
+				Name:      "app",
+				Arguments: []Argument{&IntArg{Name: "port", Required: true, DefaultValue: 80}},
+			},
+			errContains: "command 'app': argument 'port' is required and can't have a default value",
+		},
+		{
+			name: "variadic argument not last",
+			cmd: &Command{
+				Name:      "app",
+				Arguments: []Argument{&StringSliceArg{Name: "files"}, &StringArg{Name: "dest"}},
+			},
+			errContains: "command 'app': variadic argument 'files' must be the last argument",
+		},
+		{
+			name: "two variadic arguments",
+			cmd: &Command{
+				Name:      "app",
+				Arguments: []Argument{&StringSliceArg{Name: "files"}, &IntSliceArg{Name: "sizes"}},
+			},
+			errContains: "command 'app': only one variadic argument is allowed, found 'files' and 'sizes'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate()
+			if tt.errContains == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestCommand_StrictInit(t *testing.T) {
+	executed := false
+	cmd := &Command{
+		Name:  "app",
+		Flags: []Flag{&StringFlag{Name: "name"}, &StringFlag{Name: "name"}},
+		Run: func(ctx context.Context, cmd *Command) error {
+			executed = true
+			return nil
+		},
+	}
+
+	os.Args = []string{"app"}
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error without StrictInit, got %v", err)
+	}
+
+	executed = false
+	cmd.StrictInit = true
+	cmd.Flags = []Flag{&StringFlag{Name: "name"}, &StringFlag{Name: "name"}}
+	if err := cmd.Execute(context.Background()); err == nil {
+		t.Fatal("expected validation error with StrictInit")
+	}
+	if executed {
+		t.Fatal("expected command not to be executed")
+	}
+}
+
+func TestCommand_StrictInitRepeatedExecute(t *testing.T) {
+	runs := 0
+	cmd := &Command{
+		Name:       "app",
+		Version:    "1.0.0",
+		StrictInit: true,
+		Output:     &strings.Builder{},
+		Commands: []*Command{
+			{
+				Name:    "serve",
+				Confirm: "Start the server?",
+				Run: func(ctx context.Context, cmd *Command) error {
+					runs++
+					return nil
+				},
+			},
+		},
+	}
+
+	// The flags added by the library on earlier runs mustn't be reported as conflicts
+	for _, args := range [][]string{{"serve", "--yes"}, {}, {"serve", "--yes"}} {
+		cmd.SetArgs(args)
+		if err := cmd.Execute(context.Background()); err != nil {
+			t.Fatalf("Execute(%v) error: %v", args, err)
+		}
+	}
+	if runs != 2 {
+		t.Errorf("expected serve to run twice, got %d", runs)
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+// FlagInfo describes a flag as seen by a command, for building custom help or checking flag definitions
+type FlagInfo struct {
+	Name        string   // Name of the flag
+	Aliases     []string // Alternative names for the flag
+	Usage       string   // Description of the flag
+	Type        string   // Type shown in the help, e.g. "int" or "strings"
+	DefaultText string   // Default value as shown in the help, including any FlagDefaults override of the command
+	EnvVars     []string // Environment variables read by the flag, including any derived from EnvPrefix
+	ConfigPaths []string // Paths in the configuration file read by the flag
+	Choices     []string // Values the flag accepts, if restricted
+	TakesValue  bool     // False for flags given without a value, e.g. bool and count flags
+	Required    bool     // The flag must be set
+	Hidden      bool     // The flag is hidden from the help
+	Secret      bool     // The value of the flag is masked
+	Global      bool     // The flag is available to subcommands
+	Inherited   bool     // The flag is a global flag defined by a parent command
+	Flag        Flag     // The flag definition
+}
+
+// VisitFlags calls fn for each flag available to the command, its own flags in the order they're defined followed by the
+// global flags inherited from its parents, a flag is only visited once with the command's own flag taking precedence.
+// Hidden flags are included, along with the help and version flags once the command has been executed.
+func (c *Command) VisitFlags(fn func(FlagInfo)) {
+	seen := make(map[string]bool, len(c.Flags)+len(c.globalFlags))
+	visit := func(flag Flag, inherited bool) {
+		if seen[flag.getName()] {
+			return
+		}
+		seen[flag.getName()] = true
+
+		defaultText := flag.defaultValueText()
+		if value, ok := c.flagDefault(flag); ok {
+			defaultText = flag.defaultOverrideText(value)
+		}
+
+		fn(FlagInfo{
+			Name:        flag.getName(),
+			Aliases:     flag.getAliases(),
+			Usage:       flag.getUsage(),
+			Type:        flag.typeText(),
+			DefaultText: defaultText,
+			EnvVars:     c.flagEnvVars(flag),
+			ConfigPaths: flag.getConfigPaths(),
+			Choices:     flag.getChoices(),
+			TakesValue:  flag.takesValue(),
+			Required:    flag.isRequired(),
+			Hidden:      flag.isHidden(),
+			Secret:      flag.isSecret(),
+			Global:      flag.isGlobal(),
+			Inherited:   inherited,
+			Flag:        flag,
+		})
+	}
+
+	for _, flag := range c.Flags {
+		visit(flag, false)
+	}
+	for _, flag := range c.globalFlags {
+		visit(flag, true)
+	}
+}
+
+// ArgumentInfo describes a positional argument of a command, for building custom help or other front ends to the commands
+type ArgumentInfo struct {
+	Name        string   // Name of the argument
+	Usage       string   // Description of the argument
+	Type        string   // Type shown in the help, e.g. "int"
+	DefaultText string   // Default value as shown in the help
+	Choices     []string // Values the argument accepts, if restricted
+	Required    bool     // The argument must be given
+	Variadic    bool     // The argument takes all the remaining values
+	Argument    Argument // The argument definition
+}
+
+// VisitArguments calls fn for each of the command's arguments in the order they're defined
+func (c *Command) VisitArguments(fn func(ArgumentInfo)) {
+	for _, arg := range c.Arguments {
+		fn(ArgumentInfo{
+			Name:        arg.name(),
+			Usage:       arg.usage(),
+			Type:        arg.typeText(),
+			DefaultText: arg.defaultText(),
+			Choices:     arg.getChoices(),
+			Required:    arg.isRequired(),
+			Variadic:    arg.isVariadic(),
+			Argument:    arg,
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestVisitFlags(t *testing.T) {
+	var visited []FlagInfo
+	root := &Command{
+		Name:      "app",
+		Version:   "1.0.0",
+		EnvPrefix: "APP",
+		Flags: []Flag{
+			&StringFlag{Name: "config", Aliases: []string{"c"}, Global: true, ConfigPath: []string{"config"}},
+			&BoolFlag{Name: "debug", Global: true, Hidden: true},
+			&IntFlag{Name: "workers"},
+		},
+		Commands: []*Command{
+			{
+				Name:         "serve",
+				FlagDefaults: map[string]any{"port": 9000},
+				Flags: []Flag{
+					&IntFlag{Name: "port", DefaultValue: 8080, Required: true},
+					&StringFlag{Name: "format", Choices: []string{"json", "text"}},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					cmd.VisitFlags(func(info FlagInfo) {
+						visited = append(visited, info)
+					})
+					return nil
+				},
+			},
+		},
+	}
+
+	root.SetArgs([]string{"serve", "--port", "1"})
+	if err := root.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	byName := map[string]FlagInfo{}
+	for _, info := range visited {
+		names = append(names, info.Name)
+		byName[info.Name] = info
+	}
+
+	// Own flags first, including the injected help flag, then the inherited globals, the non-global workers flag isn't available
+	want := []string{"port", "format", "help", "config", "debug"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected flags %v, got %v", want, names)
+	}
+
+	if info := byName["config"]; !info.Inherited || !info.Global || info.Type != "string" ||
+		!reflect.DeepEqual(info.Aliases, []string{"c"}) || !reflect.DeepEqual(info.ConfigPaths, []string{"config"}) ||
+		!reflect.DeepEqual(info.EnvVars, []string{"APP_CONFIG"}) {
+		t.Errorf("unexpected info for config: %+v", info)
+	}
+	if info := byName["debug"]; !info.Hidden || info.TakesValue {
+		t.Errorf("expected debug to be a hidden flag without a value: %+v", info)
+	}
+	if info := byName["port"]; info.Inherited || !info.Required || info.DefaultText != "9000" || !info.TakesValue {
+		t.Errorf("unexpected info for port: %+v", info)
+	}
+	if info := byName["format"]; !reflect.DeepEqual(info.Choices, []string{"json", "text"}) {
+		t.Errorf("expected choices for format, got %v", info.Choices)
+	}
+	if info := byName["help"]; info.Inherited || info.Flag == nil {
+		t.Errorf("unexpected info for help: %+v", info)
+	}
+}
+
+func TestVisitFlagsRootIncludesVersion(t *testing.T) {
+	root := &Command{
+		Name:    "app",
+		Version: "1.0.0",
+		Flags:   []Flag{&BoolFlag{Name: "verbose"}},
+		Run:     func(ctx context.Context, cmd *Command) error { return nil },
+	}
+
+	root.SetArgs([]string{})
+	if err := root.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	root.VisitFlags(func(info FlagInfo) {
+		names = append(names, info.Name)
+	})
+
+	if want := []string{"verbose", "help", "version"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected flags %v, got %v", want, names)
+	}
+}
+
+func TestVisitArguments(t *testing.T) {
+	cmd := &Command{
+		Name: "copy",
+		Arguments: []Argument{
+			&StringArg{Name: "mode", Usage: "Copy mode", Required: true, Choices: []string{"fast", "safe"}},
+			&IntArg{Name: "retries", DefaultValue: 3},
+			&StringSliceArg{Name: "files"},
+		},
+	}
+
+	var visited []ArgumentInfo
+	cmd.VisitArguments(func(info ArgumentInfo) {
+		visited = append(visited, info)
+	})
+
+	if len(visited) != 3 {
+		t.Fatalf("expected 3 arguments, got %d", len(visited))
+	}
+	if info := visited[0]; info.Name != "mode" || info.Usage != "Copy mode" || !info.Required || info.Variadic ||
+		!reflect.DeepEqual(info.Choices, []string{"fast", "safe"}) || info.Argument != cmd.Arguments[0] {
+		t.Errorf("unexpected info for mode: %+v", info)
+	}
+	if info := visited[1]; info.Name != "retries" || info.Type != "int" || info.DefaultText != "3" || info.Required {
+		t.Errorf("unexpected info for retries: %+v", info)
+	}
+	if info := visited[2]; info.Name != "files" || !info.Variadic {
+		t.Errorf("unexpected info for files: %+v", info)
+	}
+}
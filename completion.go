@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -14,11 +15,11 @@ func GenerateCompletionCommand() *Command {
 	return &Command{
 		Name:        "completion",
 		Usage:       "Generate shell completion scripts",
-		Description: "Output shell completion scripts for bash, zsh, fish or powershell",
+		Description: "Output shell completion scripts for bash, zsh, fish, powershell or nushell",
 		Arguments: []Argument{
 			&StringArg{
 				Name:     "shell",
-				Usage:    "Shell type (bash, zsh, fish, powershell)",
+				Usage:    "Shell type (bash, zsh, fish, powershell, nushell)",
 				Required: true,
 			},
 		},
@@ -33,25 +34,56 @@ func GenerateCompletionCommand() *Command {
 				Usage:  "Return flag completions for the given command path",
 				Hidden: true,
 			},
+			&StringFlag{
+				Name:   "value",
+				Usage:  "Return value completions for the named flag, used with --flag",
+				Hidden: true,
+			},
+			&StringFlag{
+				Name:   "word",
+				Usage:  "Word being completed, passed to the completion functions of arguments",
+				Hidden: true,
+			},
+			&BoolFlag{
+				Name:  "static",
+				Usage: "Embed the command tree in the script rather than calling back to the program, bash and zsh only",
+			},
+			&BoolFlag{
+				Name:   "debug",
+				Usage:  "Print the resolved command and completion candidates to stderr",
+				Hidden: true,
+			},
 		},
 		Run: func(ctx context.Context, cmd *Command) error {
 			shell := cmd.GetStringArg("shell")
 
 			// Handle the dynamic completion mode when called with the completion flags
+			if cmd.GetBool("debug") && (cmd.HasFlag("command") || cmd.HasFlag("flag")) {
+				printCompletionDebug(os.Stderr, cmd)
+			}
+
 			if cmd.HasFlag("command") {
-				handleCommandCompletion(cmd, shell)
+				handleCommandCompletion(os.Stdout, cmd, shell, cmd.GetString("command"))
 				return nil
 			} else if cmd.HasFlag("flag") {
-				handleFlagCompletion(cmd, shell)
+				handleFlagCompletion(os.Stdout, cmd, shell)
 				return nil
 			}
 
 			// Generate completion script for the requested shell
 			rootCmd := cmd.GetRootCmd()
+			static := cmd.GetBool("static")
+			if static && !slices.Contains([]string{"bash", "zsh"}, strings.ToLower(shell)) {
+				return fmt.Errorf("static completion is not supported for %s (supported: bash, zsh)", shell)
+			}
 
 			switch strings.ToLower(shell) {
 			case "bash":
-				err := generateDynamicBashCompletion(os.Stdout, rootCmd)
+				generate := generateDynamicBashCompletion
+				if static {
+					generate = generateStaticBashCompletion
+				}
+				err := generate(os.Stdout, rootCmd)
 				if err == nil {
 					fmt.Fprintln(os.Stderr, "\nBash completion has been generated. To use it, run:")
 					fmt.Fprintln(os.Stderr, "    source <("+rootCmd.Name+" completion bash)")
@@ -60,7 +92,11 @@ func GenerateCompletionCommand() *Command {
 				}
 				return err
 			case "zsh":
-				err := generateDynamicZshCompletion(os.Stdout, rootCmd)
+				generate := generateDynamicZshCompletion
+				if static {
+					generate = generateStaticZshCompletion
+				}
+				err := generate(os.Stdout, rootCmd)
 				if err == nil {
 					fmt.Fprintln(os.Stderr, "\nZsh completion has been generated. To use it, run:")
 					fmt.Fprintln(os.Stderr, "    source <("+rootCmd.Name+" completion zsh)")
@@ -86,94 +122,182 @@ func GenerateCompletionCommand() *Command {
 					fmt.Fprintln(os.Stderr, "    "+rootCmd.Name+" completion powershell | Out-String | Invoke-Expression")
 				}
 				return err
+			case "nushell":
+				err := generateDynamicNushellCompletion(os.Stdout, rootCmd)
+				if err == nil {
+					fmt.Fprintln(os.Stderr, "\nNushell completion has been generated. To use it, run:")
+					fmt.Fprintln(os.Stderr, "    "+rootCmd.Name+" completion nushell | save --force "+rootCmd.Name+"-completions.nu")
+					fmt.Fprintln(os.Stderr, "    source "+rootCmd.Name+"-completions.nu")
+					fmt.Fprintln(os.Stderr, "\nTo load completions for each session, save the script and add to your config.nu:")
+					fmt.Fprintln(os.Stderr, "    source ~/.config/nushell/"+rootCmd.Name+"-completions.nu")
+				}
+				return err
 			default:
-				return fmt.Errorf("unsupported shell: %s (supported: bash, zsh, fish, powershell)", shell)
+				return fmt.Errorf("unsupported shell: %s (supported: bash, zsh, fish, powershell, nushell)", shell)
 			}
 		},
 	}
 }
 
-// handleCommandCompletion prints available commands for the given path
-func handleCommandCompletion(cmd *Command, shell string) {
-	cmdPath := filepath.Base(cmd.GetString("command"))
-	rootCmd := cmd.GetRootCmd()
-
-	// Parse the command path to find the target command
-	pathParts := strings.Split(cmdPath, " ")
-	current := rootCmd
-
-	// Navigate to the specified command
-	for _, part := range pathParts {
-		if part == "" || part == rootCmd.Name {
-			continue
-		}
+// handleCommandCompletion writes available commands for the given path, or the values for the argument being completed
+func handleCommandCompletion(w io.Writer, cmd *Command, shell string, cmdPath string) {
+	current, _, args, found := findCompletionCommand(cmd.GetRootCmd(), cmdPath)
+	if !found {
+		return
+	}
 
-		found := false
+	// Output available subcommands, along with their aliases, unless arguments have already been given
+	if len(args) == 0 {
 		for _, subCmd := range current.Commands {
-			if subCmd.Name == part {
-				current = subCmd
-				found = true
-				break
+			for _, name := range append([]string{subCmd.Name}, subCmd.Aliases...) {
+				switch shell {
+				case "fish", "powershell", "nushell":
+					// Fish, Powershell and Nushell use tab-separated description format, a tab can't appear in a name
+					if subCmd.Usage != "" {
+						fmt.Fprintf(w, "%s\t%s\n", name, subCmd.Usage)
+					} else {
+						fmt.Fprintln(w, name)
+					}
+
+				default:
+					// Just need command names
+					fmt.Fprintln(w, name)
+				}
 			}
 		}
+	}
 
-		if !found {
+	// Output the values accepted by the argument being completed
+	for _, arg := range current.argCompletions(len(args), cmd.GetString("word")) {
+		fmt.Fprintln(w, arg)
+	}
+	if arg := current.argumentAt(len(args)); arg != nil {
+		if files, extensions := arg.completeFiles(); files {
+			fmt.Fprintln(w, filesDirective(extensions))
+		}
+	}
+}
+
+// argCompletions returns the values offered for the positional argument at index pos, the ValidArgs of the command for
+// the first argument and the completions of the named argument in that position
+func (c *Command) argCompletions(pos int, toComplete string) []string {
+	var values []string
+	if pos == 0 {
+		values = append(values, c.ValidArgs...)
+	}
+	if arg := c.argumentAt(pos); arg != nil {
+		values = append(values, arg.complete(c, toComplete)...)
+	}
+	return values
+}
+
+// argumentAt returns the named argument that takes the positional argument at index pos, a variadic last argument
+// takes all those after it, nil if there isn't one
+func (c *Command) argumentAt(pos int) Argument {
+	if pos < len(c.Arguments) {
+		return c.Arguments[pos]
+	}
+	if n := len(c.Arguments); n > 0 && c.Arguments[n-1].isVariadic() {
+		return c.Arguments[n-1]
+	}
+	return nil
+}
+
+// handleFlagCompletion writes available flags for the given command path
+func handleFlagCompletion(w io.Writer, cmd *Command, shell string) {
+	rootCmd := cmd.GetRootCmd()
+	current, globalFlags, _, found := findCompletionCommand(rootCmd, cmd.GetString("flag"))
+	if !found {
+		return
+	}
+
+	// Completing the value of a flag, given as the word after it, if the flag doesn't take a value then the word is
+	// an argument or a command
+	if cmd.HasFlag("value") {
+		flag := findCompletionFlag(current, globalFlags, cmd.GetString("value"))
+		if flag == nil || !flag.takesValue() {
+			handleCommandCompletion(w, cmd, shell, cmd.GetString("flag"))
 			return
 		}
+		for _, value := range flag.complete(current, cmd.GetString("word")) {
+			fmt.Fprintln(w, value)
+		}
+		if files, extensions := flag.completeFiles(); files {
+			fmt.Fprintln(w, filesDirective(extensions))
+		}
+		return
 	}
 
-	// Output available subcommands
-	for _, subCmd := range current.Commands {
-		switch shell {
-		case "fish":
-			// Fish uses tab-separated description format
-			if subCmd.Usage != "" {
-				fmt.Printf("%s\t%s\n", subCmd.Name, subCmd.Usage)
-			} else {
-				fmt.Println(subCmd.Name)
+	// Completing the value of a flag given as --flag=value
+	if name, value, ok := strings.Cut(cmd.GetString("word"), "="); ok {
+		if flag := findCompletionFlag(current, globalFlags, name); flag != nil && flag.takesValue() {
+			for _, v := range flag.complete(current, value) {
+				fmt.Fprintf(w, "%s=%s\n", name, v)
 			}
+			if files, extensions := flag.completeFiles(); files {
+				fmt.Fprintln(w, filesDirective(extensions))
+			}
+		}
+		return
+	}
 
-		case "powershell":
-			// Powershell uses value:description format
-			if subCmd.Usage != "" {
-				fmt.Printf("%s:%s\n", subCmd.Name, subCmd.Usage)
-			} else {
-				fmt.Println(subCmd.Name)
+	// Output available flags & global flags along with their aliases, flags that take a value end with = so the shell
+	// doesn't add a space
+	for _, flags := range [][]Flag{current.Flags, globalFlags} {
+		for _, flag := range flags {
+			if flag.isHidden() {
+				continue
 			}
 
-		default:
-			// Just need command names
-			fmt.Println(subCmd.Name)
+			for _, name := range flagCompletionNames(flag) {
+				switch shell {
+				case "fish", "powershell", "nushell":
+					// Fish, Powershell and Nushell use tab-separated description format
+					if flag.getUsage() == "" {
+						fmt.Fprintln(w, name)
+					} else {
+						fmt.Fprintf(w, "%s\t%s\n", name, flag.getUsage())
+					}
+
+				default:
+					fmt.Fprintln(w, name)
+				}
+			}
+
+			printFlagChoices(w, flag)
+			if rootCmd.CompleteNegated {
+				printNegatedFlag(w, flag)
+			}
 		}
 	}
 }
 
-// handleFlagCompletion prints available flags for the given command path
-func handleFlagCompletion(cmd *Command, shell string) {
-	cmdPath := filepath.Base(cmd.GetString("flag"))
-	rootCmd := cmd.GetRootCmd()
-
-	// Parse the command path to find the target command
-	pathParts := strings.Split(cmdPath, " ")
-	current := rootCmd
+// findCompletionCommand walks the command path from the root, returning the target command, the global flags
+// inherited from its parents and the positional arguments given after it, found is false if a command in the path
+// doesn't exist
+func findCompletionCommand(rootCmd *Command, cmdPath string) (current *Command, globalFlags []Flag, args []string, found bool) {
+	pathParts := strings.Split(filepath.Base(cmdPath), " ")
+	current = rootCmd
 
-	var globalFlags []Flag
-
-	// Navigate to the specified command
 	for _, part := range pathParts {
 		if part == "" || part == rootCmd.Name {
 			continue
 		}
 
-		for _, flag := range current.Flags {
-			if flag.isGlobal() && !flag.isHidden() {
-				globalFlags = append(globalFlags, flag)
-			}
+		// Once an argument has been given the rest of the path are arguments too
+		if len(args) > 0 {
+			args = append(args, part)
+			continue
 		}
 
-		found := false
+		found = false
 		for _, subCmd := range current.Commands {
-			if subCmd.Name == part {
+			if subCmd.matchesName(part) {
+				for _, flag := range current.Flags {
+					if flag.isGlobal() && !flag.isHidden() {
+						globalFlags = append(globalFlags, flag)
+					}
+				}
 				current = subCmd
 				found = true
 				break
@@ -181,50 +305,114 @@ func handleFlagCompletion(cmd *Command, shell string) {
 		}
 
 		if !found {
-			return
+			if !current.acceptsArgs() {
+				return nil, nil, nil, false
+			}
+			args = append(args, part)
 		}
 	}
 
-	// Output available flags & global flags
-	for _, flag := range current.Flags {
-		if flag.isHidden() {
-			continue
+	return current, globalFlags, args, true
+}
+
+// acceptsArgs reports whether the command takes positional arguments
+func (c *Command) acceptsArgs() bool {
+	return len(c.Arguments) > 0 || len(c.ValidArgs) > 0 || c.MaxArgs != NoArgs
+}
+
+// filesDirective returns the line asking the shell to complete file paths, limited to the given extensions if there
+// are any, e.g. ":files yaml yml"
+func filesDirective(extensions []string) string {
+	directive := ":files"
+	for _, ext := range extensions {
+		directive += " " + strings.TrimPrefix(ext, ".")
+	}
+	return directive
+}
+
+// findCompletionFlag returns the flag of the command, or one of the global flags, with the given name or alias, which
+// may include the leading dashes, nil if there isn't one
+func findCompletionFlag(current *Command, globalFlags []Flag, name string) Flag {
+	name = strings.TrimLeft(name, "-")
+	for _, flag := range append(append([]Flag{}, current.Flags...), globalFlags...) {
+		if flag.getName() == name || slices.Contains(flag.getAliases(), name) {
+			return flag
 		}
+	}
+	return nil
+}
 
-		switch shell {
-		case "fish":
-			if flag.getUsage() == "" {
-				fmt.Printf("--%s\n", flag.getName())
-			} else {
-				fmt.Printf("--%s\t%s\n", flag.getName(), flag.getUsage())
-			}
+// printCompletionDebug writes the command resolved from the --command or --flag path and the candidates that would be offered
+func printCompletionDebug(w io.Writer, cmd *Command) {
+	mode, cmdPath := "command", cmd.GetString("command")
+	if !cmd.HasFlag("command") {
+		mode, cmdPath = "flag", cmd.GetString("flag")
+	}
 
-		case "powershell":
-			// Powershell uses value:description format
-			if flag.getUsage() != "" {
-				fmt.Printf("--%s:%s\n", flag.getName(), flag.getUsage())
-			} else {
-				fmt.Printf("--%s\n", flag.getName())
-			}
+	rootCmd := cmd.GetRootCmd()
+	_, _, args, found := findCompletionCommand(rootCmd, cmdPath)
+	if !found {
+		fmt.Fprintf(w, "completion debug: %s path %q does not resolve to a command\n", mode, cmdPath)
+		return
+	}
 
-		default:
-			fmt.Printf("--%s\n", flag.getName())
+	names := []string{rootCmd.Name}
+	for _, part := range strings.Fields(filepath.Base(cmdPath)) {
+		if part != rootCmd.Name {
+			names = append(names, part)
 		}
 	}
+	names = names[:len(names)-len(args)]
+	fmt.Fprintf(w, "completion debug: %s path %q resolved to %q\n", mode, cmdPath, strings.Join(names, " "))
+
+	// Capture the candidates from the code that writes them for the shell, in the fish format as it includes the
+	// descriptions, so the debug output always matches what the shell is given
+	var out strings.Builder
+	if mode == "command" {
+		handleCommandCompletion(&out, cmd, "fish", cmdPath)
+	} else {
+		handleFlagCompletion(&out, cmd, "fish")
+	}
 
-	for _, flag := range globalFlags {
-		switch shell {
-		case "fish":
-			if flag.getUsage() == "" {
-				fmt.Printf("--%s\n", flag.getName())
-			} else {
-				fmt.Printf("--%s\t%s\n", flag.getName(), flag.getUsage())
-			}
+	fmt.Fprintln(w, "candidates:")
+	for _, line := range strings.Split(out.String(), "\n") {
+		if line != "" {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
+
+// flagCompletionNames returns the flag and its aliases as completed by the shell, --name for bool and count flags and
+// --name= for flags taking a value, single letter aliases are completed as -x
+func flagCompletionNames(flag Flag) []string {
+	suffix := ""
+	if flag.takesValue() {
+		suffix = "="
+	}
 
-		default:
-			fmt.Printf("--%s\n", flag.getName())
+	names := []string{"--" + flag.getName() + suffix}
+	for _, alias := range flag.getAliases() {
+		if len(alias) == 1 {
+			names = append(names, "-"+alias)
+		} else {
+			names = append(names, "--"+alias+suffix)
 		}
 	}
+	return names
+}
+
+// printNegatedFlag writes the --no-flag form of a bool flag
+func printNegatedFlag(w io.Writer, flag Flag) {
+	if _, isBool := flag.(*BoolFlag); isBool {
+		fmt.Fprintf(w, "--no-%s\n", flag.getName())
+	}
+}
+
+// printFlagChoices writes a completion for each of the values accepted by the flag, e.g. --log-level=debug
+func printFlagChoices(w io.Writer, flag Flag) {
+	for _, choice := range flag.getChoices() {
+		fmt.Fprintf(w, "--%s=%s\n", flag.getName(), choice)
+	}
 }
 
 // Generate a dynamic bash completion script
@@ -252,13 +440,21 @@ _%[1]s() {
     # Capture the current command line words
     local cmdpath="%[1]s"
     local current_word="${COMP_WORDS[COMP_CWORD]}"
+    local previous_word="${COMP_WORDS[COMP_CWORD-1]}"
     local completions
 
+    # Bash splits --flag=value into separate words, so complete the value of the flag
+    if [[ "$current_word" == "=" ]]; then
+        current_word=""
+    elif [[ "$previous_word" == "=" && $COMP_CWORD -gt 1 ]]; then
+        previous_word="${COMP_WORDS[COMP_CWORD-2]}"
+    fi
+
     # Build the command path from all non-flag arguments
     if [[ ${#COMP_WORDS[@]} -gt 1 ]]; then
         for ((i=1; i<COMP_CWORD; i++)); do
-            # Only add non-flag tokens to the command path
-            if [[ "${COMP_WORDS[i]}" != -* ]]; then
+            # Only add non-flag tokens to the command path, skipping the values given with --flag=value
+            if [[ "${COMP_WORDS[i]}" != -* && "${COMP_WORDS[i]}" != "=" && "${COMP_WORDS[i-1]}" != "=" ]]; then
                 cmdpath+=" ${COMP_WORDS[i]}"
             fi
         done
@@ -267,21 +463,55 @@ _%[1]s() {
     # Request completions from the binary
     if [[ "$current_word" == -* ]]; then
         # Flag completion
-        completions=$($exec_path completion bash --flag="$cmdpath")
+        completions=$($exec_path completion bash --flag="$cmdpath" --word="$current_word")
+    elif [[ "$previous_word" == -* && "$previous_word" != *=* ]]; then
+        # Flag value completion, falls back to command completion if the flag doesn't take a value
+        completions=$($exec_path completion bash --flag="$cmdpath" --value="$previous_word" --word="$current_word")
     else
         # Command/subcommand/argument completion
-        completions=$($exec_path completion bash --command="$cmdpath")
+        completions=$($exec_path completion bash --command="$cmdpath" --word="$current_word")
     fi
 
     # Split the output into an array of suggestions
     IFS=$'\n' read -r -d '' -a suggestions <<< "$completions"
 
+    # A :files line asks for file path completion, optionally limited to the listed extensions
+    local files_directive=""
+    local -a values=()
+    local suggestion
+    for suggestion in "${suggestions[@]}"; do
+        if [[ "$suggestion" == :files* ]]; then
+            files_directive="$suggestion"
+        else
+            values+=("$suggestion")
+        fi
+    done
+
+    if [[ -n "$files_directive" ]]; then
+        local -a extensions=(${files_directive#:files})
+        local ext
+        compopt -o filenames
+        if [[ ${#extensions[@]} -eq 0 ]]; then
+            mapfile -t -O ${#values[@]} values < <(compgen -f -- "$current_word")
+        else
+            mapfile -t -O ${#values[@]} values < <(compgen -d -- "$current_word")
+            for ext in "${extensions[@]}"; do
+                mapfile -t -O ${#values[@]} values < <(compgen -f -X "!*.$ext" -- "$current_word")
+            done
+        fi
+    fi
+
     # Set completion replies
-    COMPREPLY=("${suggestions[@]}")
+    COMPREPLY=("${values[@]}")
+
+    # Flags ending with = take a value, so don't add a space after them
+    if [[ ${#COMPREPLY[@]} -eq 1 && "${COMPREPLY[0]}" == *= ]]; then
+        compopt -o nospace
+    fi
 }
 
 # Register the completion function
-complete -o bashdefault -o default -o nospace -F _%[1]s %[1]s`, cmdName)
+complete -o bashdefault -o default -F _%[1]s %[1]s`, cmdName)
 
 	return nil
 }
@@ -313,6 +543,7 @@ _%[1]s() {
     # Capture the current command line words
     local cmdpath="%[1]s"
     local current_word="${words[$CURRENT]}"
+    local previous_word="${words[$CURRENT-1]}"
     local completions
 
 		# Skip command name and build from arguments
@@ -331,17 +562,47 @@ _%[1]s() {
     # Determine whether we are completing a flag or a command/argument
     if [[ "$current_word" == -* ]]; then
         # Request flag completions
-        completions=$($exec_path completion zsh --flag="$cmdpath")
+        completions=$($exec_path completion zsh --flag="$cmdpath" --word="$current_word")
+    elif [[ "$previous_word" == -* && "$previous_word" != *=* ]]; then
+        # Request flag value completions, falls back to command completions if the flag doesn't take a value
+        completions=$($exec_path completion zsh --flag="$cmdpath" --value="$previous_word" --word="$current_word")
     else
         # Request command or argument completions
-        completions=$($exec_path completion zsh --command="$cmdpath")
+        completions=$($exec_path completion zsh --command="$cmdpath" --word="$current_word")
     fi
 
     # Split the output from the command into an array of suggestions
     suggestions=("${(@f)completions}")
 
+    # Flags ending with = take a value, so don't add a space after them, a :files line asks for file path
+    # completion, optionally limited to the listed extensions
+    local -a spaced unspaced
+    local suggestion files_directive
+    for suggestion in "${suggestions[@]}"; do
+        if [[ "$suggestion" == :files* ]]; then
+            files_directive="$suggestion"
+        elif [[ "$suggestion" == *= ]]; then
+            unspaced+=("$suggestion")
+        else
+            spaced+=("$suggestion")
+        fi
+    done
+
     # Add the suggestions to the completion list
-    compadd -- "${suggestions[@]}"
+    compadd -- "${spaced[@]}"
+    compadd -S '' -- "${unspaced[@]}"
+
+    if [[ -n "$files_directive" ]]; then
+        local -a extensions=(${=files_directive#:files})
+        if [[ "$current_word" == -*=* ]]; then
+            compset -P '*='
+        fi
+        if [[ ${#extensions[@]} -eq 0 ]]; then
+            _files
+        else
+            _files -g "*.(${(j:|:)extensions})"
+        fi
+    fi
 }
 
 # Register the completion function
@@ -360,6 +621,7 @@ function __%[1]s_completion
     set -l exec_path
     set -l cmd_line (commandline -opc)
     set -l current_token (commandline -ct)
+    set -l previous_token $cmd_line[-1]
     set -l cmd_path "%[1]s"
 
     # Check if exec is in the PATH, otherwise assume a local executable
@@ -395,12 +657,38 @@ function __%[1]s_completion
     end
 
     # Request completions from the binary
+    set -l completions
     if string match -q -- '-*' $current_token
         # Flag completion
-        eval $exec_path completion fish --flag=\"$cmd_path\"
+        set completions (eval $exec_path completion fish --flag=\"$cmd_path\" --word=\"$current_token\")
+    else if string match -q -- '-*' $previous_token; and not string match -q -- '*=*' $previous_token
+        # Flag value completion, falls back to command completion if the flag doesn't take a value
+        set completions (eval $exec_path completion fish --flag=\"$cmd_path\" --value=\"$previous_token\" --word=\"$current_token\")
     else
         # Command/subcommand/argument completion
-        eval $exec_path completion fish --command=\"$cmd_path\"
+        set completions (eval $exec_path completion fish --command=\"$cmd_path\" --word=\"$current_token\")
+    end
+
+    # A :files line asks for file path completion, optionally limited to the listed extensions
+    set -l files_directive
+    for line in $completions
+        if string match -q -- ':files*' $line
+            set files_directive $line
+        else
+            echo $line
+        end
+    end
+
+    if test -n "$files_directive"
+        set -l extensions (string split -n ' ' -- (string replace ':files' '' -- $files_directive))
+        set -l prefix (string match -r -- '^-[^=]*=' $current_token)
+        set -l path (string replace -r -- '^-[^=]*=' '' $current_token)
+        for file in (__fish_complete_path $path)
+            set file (string split -f1 \t -- $file)
+            if test (count $extensions) -eq 0; or test -d $file; or string match -q -r -- '\.('(string join '|' $extensions)')$' $file
+                echo "$prefix$file"
+            end
+        end
     end
 end
 
@@ -455,26 +743,43 @@ Register-ArgumentCompleter -Native -CommandName %[1]s -ScriptBlock {
         }
     }
 
-    # Determine if we're completing a flag or a command/argument
+    # Find the word before the one being completed
+    $previousWord = ""
+    $lastIndex = $tokens.Count - 1
+    if ($currentWord -and $lastIndex -ge 1 -and $tokens[$lastIndex].ToString() -eq $currentWord) {
+        $lastIndex--
+    }
+    if ($lastIndex -ge 1) {
+        $previousWord = $tokens[$lastIndex].ToString()
+    }
+
+    # Determine if we're completing a flag, a flag value or a command/argument
     $completions = $null
     if ($currentWord -match "^-") {
         # Flag completion
-        $completions = & $execPath completion powershell --flag="$cmdPath" 2>$null
+        $completions = & $execPath completion powershell --flag="$cmdPath" --word="$currentWord" 2>$null
+    } elseif ($previousWord -match "^-" -and $previousWord -notmatch "=") {
+        # Flag value completion, falls back to command completion if the flag doesn't take a value
+        $completions = & $execPath completion powershell --flag="$cmdPath" --value="$previousWord" --word="$currentWord" 2>$null
     } else {
         # Command/subcommand/argument completion
-        $completions = & $execPath completion powershell --command="$cmdPath" 2>$null
+        $completions = & $execPath completion powershell --command="$cmdPath" --word="$currentWord" 2>$null
     }
 
 	# Process completions and return them as CompletionResults
+	$filesDirective = $null
 	if ($completions) {
 `, cmdName)
 	fmt.Fprintln(w, "$completions -split \"`n\" | ForEach-Object {")
 	fmt.Fprintf(w, `			$line = $_.Trim()
-			if ($line) {
-				# Parse completion lines - format should be "value:description"
-				if ($line -match "^(.*?)(?::(.*))?$") {
+			if ($line -like ":files*") {
+				# A :files line asks for file path completion, optionally limited to the listed extensions
+				$filesDirective = $line
+			} elseif ($line) {
+				# Parse completion lines - format should be "value<tab>description", the description may contain colons
+				if ($line -match '^([^\t]*)(?:\t(.*))?$') {
 					$value = $matches[1]
-					$description = if ($matches.Count -gt 2) { $matches[2] } else { $value }
+					$description = if ($matches[2]) { $matches[2] } else { $value }
 
 					# Create a CompletionResult
 					[System.Management.Automation.CompletionResult]::new(
@@ -487,6 +792,17 @@ Register-ArgumentCompleter -Native -CommandName %[1]s -ScriptBlock {
 			}
 		}
 	}
+
+	if ($filesDirective) {
+		$extensions = @($filesDirective.Substring(6).Trim() -split ' ' | Where-Object { $_ })
+		$dir = Split-Path -Path $currentWord -Parent
+		Get-ChildItem -Path "$currentWord*" -ErrorAction SilentlyContinue | Where-Object {
+			$_.PSIsContainer -or $extensions.Count -eq 0 -or $extensions -contains $_.Extension.TrimStart('.')
+		} | ForEach-Object {
+			$path = if ($dir) { Join-Path $dir $_.Name } else { $_.Name }
+			[System.Management.Automation.CompletionResult]::new($path, $_.Name, 'ProviderItem', $_.FullName)
+		}
+	}
 }
 
 # Note: This script should be dot-sourced or added to your PowerShell profile
@@ -494,3 +810,58 @@ Register-ArgumentCompleter -Native -CommandName %[1]s -ScriptBlock {
 
 	return nil
 }
+
+// generateDynamicNushellCompletion writes a nushell completion script that calls back to the program
+func generateDynamicNushellCompletion(w io.Writer, root *Command) error {
+	cmdName := root.Name
+
+	fmt.Fprintf(w, `# nushell completion script for the command %[1]s
+
+def "nu-complete %[1]s" [context: string] {
+    # Check if exec is in the PATH, otherwise assume a local executable
+    let exec_path = if (which %[1]s | length) > 0 { "%[1]s" } else { "./%[1]s" }
+
+    # Split the command line into words, the last word is the one being completed
+    let words = ($context | split row -r '\s+')
+    let current_word = ($words | last)
+    let previous_word = if ($words | length) > 1 { $words | get (($words | length) - 2) } else { "" }
+
+    # Build the command path from all non-flag words before the current one
+    let cmd_path = ($words | skip 1 | drop 1 | where {|word| not ($word | str starts-with "-") } | prepend "%[1]s" | str join " ")
+
+    # Request completions from the binary
+    let completions = if ($current_word | str starts-with "-") {
+        # Flag completion
+        ^$exec_path completion nushell $"--flag=($cmd_path)" $"--word=($current_word)"
+    } else if ($previous_word | str starts-with "-") and not ($previous_word | str contains "=") {
+        # Flag value completion, falls back to command completion if the flag doesn't take a value
+        ^$exec_path completion nushell $"--flag=($cmd_path)" $"--value=($previous_word)" $"--word=($current_word)"
+    } else {
+        # Command/subcommand/argument completion
+        ^$exec_path completion nushell $"--command=($cmd_path)" $"--word=($current_word)"
+    }
+
+    let lines = ($completions | lines | where {|line| $line != "" })
+
+    # A :files line asks for file path completion, returning null falls back to nushell's own file completion
+    if ($lines | any {|line| $line | str starts-with ":files" }) {
+        return null
+    }
+
+    # Each line is a value optionally followed by a tab and its description
+    $lines | each {|line|
+        let parts = ($line | split row "\t")
+        if ($parts | length) > 1 {
+            { value: ($parts | first), description: ($parts | skip 1 | str join "\t") }
+        } else {
+            { value: $line }
+        }
+    }
+}
+
+# Register the completion function
+extern "%[1]s" [...args: string@"nu-complete %[1]s"]
+`, cmdName)
+
+	return nil
+}
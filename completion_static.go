@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// staticCompletion holds the completions for one command in a static completion script
+type staticCompletion struct {
+	paths    []string // Command paths the completions apply to, one for each combination of command names and aliases
+	commands []string // Names and aliases of the subcommands
+	args     []string // Values accepted as the first positional argument, only offered before any other argument
+	flags    []string // Flags and their aliases, choices and negated forms
+}
+
+// staticCompletions walks the command tree from the root returning the completions of each command
+func staticCompletions(root *Command) []staticCompletion {
+	var completions []staticCompletion
+	var walk func(c *Command, paths []string, globalFlags []Flag)
+	walk = func(c *Command, paths []string, globalFlags []Flag) {
+		// Only the fixed values are embedded, values from a CompleteFunc can change after the script is generated
+		entry := staticCompletion{paths: paths, args: append([]string{}, c.ValidArgs...)}
+		if len(c.Arguments) > 0 {
+			entry.args = append(entry.args, c.Arguments[0].getChoices()...)
+		}
+
+		for _, sub := range c.Commands {
+			entry.commands = append(entry.commands, sub.Name)
+			entry.commands = append(entry.commands, sub.Aliases...)
+		}
+
+		// The help flag is added when a command runs, so add it here for every command rather than only those that have run
+		ownHelp := false
+		for _, flags := range [][]Flag{c.Flags, globalFlags} {
+			for _, flag := range flags {
+				if flag.isBuiltin() || flag.isHidden() {
+					continue
+				}
+				if flag.getName() == "help" {
+					ownHelp = true
+				}
+				entry.flags = append(entry.flags, flagCompletionNames(flag)...)
+				for _, choice := range flag.getChoices() {
+					entry.flags = append(entry.flags, "--"+flag.getName()+"="+choice)
+				}
+				if _, isBool := flag.(*BoolFlag); isBool && root.CompleteNegated {
+					entry.flags = append(entry.flags, "--no-"+flag.getName())
+				}
+			}
+		}
+		if !c.DisableHelp && !ownHelp {
+			entry.flags = append(entry.flags, "--help", "-h")
+		}
+		completions = append(completions, entry)
+
+		// Global flags of this command are inherited by its subcommands
+		inherited := append([]Flag{}, globalFlags...)
+		for _, flag := range c.Flags {
+			if flag.isGlobal() && !flag.isBuiltin() && !flag.isHidden() {
+				inherited = append(inherited, flag)
+			}
+		}
+
+		for _, sub := range c.Commands {
+			var subPaths []string
+			for _, path := range paths {
+				for _, name := range append([]string{sub.Name}, sub.Aliases...) {
+					subPaths = append(subPaths, path+" "+name)
+				}
+			}
+			walk(sub, subPaths, inherited)
+		}
+	}
+	walk(root, []string{root.Name}, nil)
+
+	return completions
+}
+
+// writeStaticCompletionFunc writes a shell function that prints the values selected from each completion for the
+// command path given as its first argument, one per line
+func writeStaticCompletionFunc(w io.Writer, name string, completions []staticCompletion, values func(staticCompletion) []string) {
+	fmt.Fprintf(w, "%s() {\n    case \"$1\" in\n", name)
+	for _, completion := range completions {
+		if len(values(completion)) == 0 {
+			continue
+		}
+
+		patterns := make([]string, len(completion.paths))
+		for i, path := range completion.paths {
+			patterns[i] = shellQuote(path)
+		}
+		fmt.Fprintf(w, "        %s)\n            printf '%%s\\n' %s\n            ;;\n", strings.Join(patterns, "|"), shellQuoteAll(values(completion)))
+	}
+	fmt.Fprintf(w, "    esac\n}\n\n")
+}
+
+// shellQuote quotes a value for bash and zsh using single quotes
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// shellQuoteAll quotes each value and joins them with spaces
+func shellQuoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = shellQuote(value)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// writeStaticCompletionFuncs writes the functions returning the subcommands, argument values and flags of a command path
+func writeStaticCompletionFuncs(w io.Writer, cmdName string, completions []staticCompletion) {
+	writeStaticCompletionFunc(w, "__"+cmdName+"_commands", completions, func(c staticCompletion) []string { return c.commands })
+	writeStaticCompletionFunc(w, "__"+cmdName+"_args", completions, func(c staticCompletion) []string { return c.args })
+	writeStaticCompletionFunc(w, "__"+cmdName+"_flags", completions, func(c staticCompletion) []string { return c.flags })
+}
+
+// generateStaticBashCompletion writes a bash completion script with the command tree embedded in it
+func generateStaticBashCompletion(w io.Writer, root *Command) error {
+	cmdName := root.Name
+
+	fmt.Fprintf(w, "# bash completion script for the command %[1]s, generated from the command tree\n\n", cmdName)
+	writeStaticCompletionFuncs(w, cmdName, staticCompletions(root))
+
+	fmt.Fprintf(w, `_%[1]s() {
+    local cmdpath="%[1]s"
+    local current_word="${COMP_WORDS[COMP_CWORD]}"
+    local word prev i
+    local -i args=0
+    local IFS=$'\n'
+
+    # Build the command path from the words that are subcommands of the path so far, counting the arguments after it,
+    # bash splits --flag=value into three words and a flag taking a value can be followed by it as the next word
+    for ((i=1; i<COMP_CWORD; i++)); do
+        word="${COMP_WORDS[i]}"
+        prev="${COMP_WORDS[i-1]}"
+        if [[ "$word" == -* || "$word" == "=" || "$prev" == "=" ]]; then
+            continue
+        elif __%[1]s_commands "$cmdpath" | grep -qxF -- "$word"; then
+            cmdpath+=" $word"
+            args=0
+        elif [[ "$prev" != -* ]] || ! __%[1]s_flags "$cmdpath" | grep -qxF -- "$prev="; then
+            args+=1
+        fi
+    done
+
+    # Complete flags, or the subcommands and, for the first argument only, the argument values
+    if [[ "$current_word" == -* ]]; then
+        COMPREPLY=($(compgen -W "$(__%[1]s_flags "$cmdpath")" -- "$current_word"))
+    elif ((args == 0)); then
+        COMPREPLY=($(compgen -W "$(__%[1]s_commands "$cmdpath"; __%[1]s_args "$cmdpath")" -- "$current_word"))
+    else
+        COMPREPLY=($(compgen -W "$(__%[1]s_commands "$cmdpath")" -- "$current_word"))
+    fi
+
+    # Flags ending with = take a value, so don't add a space after them
+    if [[ ${#COMPREPLY[@]} -eq 1 && "${COMPREPLY[0]}" == *= ]]; then
+        compopt -o nospace
+    fi
+}
+
+# Register the completion function
+complete -o bashdefault -o default -F _%[1]s %[1]s`, cmdName)
+
+	return nil
+}
+
+// generateStaticZshCompletion writes a zsh completion script with the command tree embedded in it
+func generateStaticZshCompletion(w io.Writer, root *Command) error {
+	cmdName := root.Name
+
+	fmt.Fprintf(w, "#compdef %[1]s\n# zsh completion script for the command %[1]s, generated from the command tree\n\n", cmdName)
+	writeStaticCompletionFuncs(w, cmdName, staticCompletions(root))
+
+	fmt.Fprintf(w, `_%[1]s() {
+    local cmdpath="%[1]s"
+    local current_word="${words[$CURRENT]}"
+    local -a commands flags suggestions spaced unspaced
+    local word prev suggestion i
+    local -i args=0
+
+    # Build the command path from the words that are subcommands of the path so far, counting the arguments after it,
+    # a flag taking a value can be followed by it as the next word
+    for ((i=2; i<CURRENT; i++)); do
+        word="${words[i]}"
+        prev="${words[i-1]}"
+        commands=("${(@f)$(__%[1]s_commands "$cmdpath")}")
+        flags=("${(@f)$(__%[1]s_flags "$cmdpath")}")
+        if [[ "$word" == -* ]]; then
+            continue
+        elif [[ ${commands[(Ie)$word]} -gt 0 ]]; then
+            cmdpath+=" $word"
+            args=0
+        elif [[ "$prev" != -* || ${flags[(Ie)$prev=]} -eq 0 ]]; then
+            args+=1
+        fi
+    done
+
+    # Complete flags, or the subcommands and, for the first argument only, the argument values
+    if [[ "$current_word" == -* ]]; then
+        suggestions=("${(@f)$(__%[1]s_flags "$cmdpath")}")
+    elif ((args == 0)); then
+        suggestions=("${(@f)$(__%[1]s_commands "$cmdpath"; __%[1]s_args "$cmdpath")}")
+    else
+        suggestions=("${(@f)$(__%[1]s_commands "$cmdpath")}")
+    fi
+
+    # Flags ending with = take a value, so don't add a space after them
+    for suggestion in "${suggestions[@]}"; do
+        if [[ "$suggestion" == *= ]]; then
+            unspaced+=("$suggestion")
+        elif [[ -n "$suggestion" ]]; then
+            spaced+=("$suggestion")
+        fi
+    done
+
+    # Add the suggestions to the completion list
+    compadd -- "${spaced[@]}"
+    compadd -S '' -- "${unspaced[@]}"
+}
+
+# Register the completion function
+compdef _%[1]s %[1]s`, cmdName)
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStaticCompletion(t *testing.T) {
+	newRoot := func() *Command {
+		return &Command{
+			Name: "app",
+			Flags: []Flag{
+				&BoolFlag{Name: "verbose", Aliases: []string{"v"}, Global: true},
+				&StringFlag{Name: "token", Hidden: true, Global: true},
+			},
+			Commands: []*Command{
+				GenerateCompletionCommand(),
+				{
+					Name:    "remove",
+					Aliases: []string{"rm"},
+					Flags:   []Flag{&StringFlag{Name: "format", Choices: []string{"json", "text"}}},
+					Commands: []*Command{
+						{Name: "all", ValidArgs: []string{"it's"}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{
+			"# bash completion script for the command app, generated from the command tree",
+			"        'app')\n            printf '%s\\n' 'completion' 'remove' 'rm'\n",
+			"        'app remove'|'app rm')\n            printf '%s\\n' 'all'\n",
+			"        'app remove all'|'app rm all')\n            printf '%s\\n' 'it'\\''s'\n",
+			"printf '%s\\n' '--format=' '--format=json' '--format=text' '--verbose' '-v' '--help' '-h'\n",
+			"    elif ((args == 0)); then\n        COMPREPLY=($(compgen -W \"$(__app_commands \"$cmdpath\"; __app_args \"$cmdpath\")\" -- \"$current_word\"))\n",
+			`COMPREPLY=($(compgen -W "$(__app_flags "$cmdpath")" -- "$current_word"))`,
+			"complete -o bashdefault -o default -F _app app",
+		}},
+		{"zsh", []string{
+			"#compdef app\n",
+			"        'app remove'|'app rm')\n            printf '%s\\n' 'all'\n",
+			`suggestions=("${(@f)$(__app_flags "$cmdpath")}")`,
+			"    elif ((args == 0)); then\n        suggestions=(\"${(@f)$(__app_commands \"$cmdpath\"; __app_args \"$cmdpath\")}\")\n",
+			"compdef _app app",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			root := newRoot()
+			root.SetArgs([]string{"completion", tt.shell, "--static"})
+			var out string
+			captureStderr(t, func() {
+				out = captureStdout(t, func() {
+					if err := root.Execute(context.Background()); err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+				})
+			})
+
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected the script to contain %q, got:\n%s", want, out)
+				}
+			}
+			if strings.Contains(out, "--token") || strings.Contains(out, "completion "+tt.shell+" --") {
+				t.Errorf("expected no hidden flags or call back to the program, got:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestStaticCompletionUnsupportedShell(t *testing.T) {
+	root := &Command{Name: "app", Commands: []*Command{GenerateCompletionCommand()}}
+	root.SetArgs([]string{"completion", "fish", "--static"})
+
+	err := root.Execute(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "static completion is not supported for fish") {
+		t.Errorf("expected an unsupported shell error, got %v", err)
+	}
+}
+
+func TestStaticCompletionHelpFlag(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{Name: "quiet", DisableHelp: true},
+			{Name: "own", Flags: []Flag{&BoolFlag{Name: "help", Usage: "Show the manual"}}},
+			{Name: "plain"},
+		},
+	}
+
+	// Run a command first so the injected help flags are present in the tree
+	root.SetArgs([]string{"plain"})
+	captureStdout(t, func() {
+		if err := root.Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	flags := map[string][]string{}
+	for _, completion := range staticCompletions(root) {
+		flags[completion.paths[0]] = completion.flags
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"app", []string{"--help", "-h"}},
+		{"app completion", []string{"--static", "--help", "-h"}},
+		{"app quiet", nil},
+		{"app own", []string{"--help"}},
+		{"app plain", []string{"--help", "-h"}},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(flags[tt.path], " "); got != strings.Join(tt.want, " ") {
+			t.Errorf("%s: expected flags %q, got %q", tt.path, tt.want, flags[tt.path])
+		}
+	}
+}
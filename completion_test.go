@@ -0,0 +1,604 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdout returns everything written to stdout while fn runs
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stdout, fn)
+}
+
+// captureStderr returns everything written to stderr while fn runs
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stderr, fn)
+}
+
+func captureFile(t *testing.T, file **os.File, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+
+	original := *file
+	*file = w
+	defer func() { *file = original }()
+
+	fn()
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	return string(out)
+}
+
+func TestFlagCompletionMarksValueFlags(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Flags: []Flag{
+			&BoolFlag{Name: "verbose", Usage: "Verbose output", Global: true},
+			&StringFlag{Name: "config", Usage: "Config file", Global: true},
+		},
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name: "serve",
+				Flags: []Flag{
+					&BoolFlag{Name: "watch"},
+					&IntFlag{Name: "port"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{"--watch", "--port=", "--verbose", "--config="}},
+		{"fish", []string{"--watch", "--port=", "--verbose\tVerbose output", "--config=\tConfig file"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			root.SetArgs([]string{"completion", tt.shell, "--flag=app serve"})
+			out := captureStdout(t, func() {
+				if err := root.Execute(context.Background()); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			})
+
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			if strings.Join(lines, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("expected completions %q, got %q", tt.want, lines)
+			}
+		})
+	}
+}
+
+func TestCommandCompletionValidArgs(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name:      "get",
+				ValidArgs: []string{"pods", "services"},
+				Commands:  []*Command{{Name: "all"}},
+			},
+		},
+	}
+
+	root.SetArgs([]string{"completion", "bash", "--command=app get"})
+	out := captureStdout(t, func() {
+		if err := root.Execute(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	if got := strings.Fields(out); strings.Join(got, " ") != "all pods services" {
+		t.Errorf("expected completions [all pods services], got %v", got)
+	}
+}
+
+func TestCommandCompletionArgumentChoices(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name:      "status",
+				Arguments: []Argument{&StringArg{Name: "state", Choices: []string{"active", "paused", "archived"}}},
+			},
+		},
+	}
+
+	root.SetArgs([]string{"completion", "bash", "--command=app status"})
+	out := captureStdout(t, func() {
+		if err := root.Execute(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	if got := strings.Fields(out); strings.Join(got, " ") != "active paused archived" {
+		t.Errorf("expected completions [active paused archived], got %v", got)
+	}
+}
+
+func TestCompletionDebug(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Flags: []Flag{
+			&StringFlag{Name: "config", Usage: "Config file", Global: true},
+		},
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name: "admin",
+				Flags: []Flag{
+					&StringFlag{Name: "format", Usage: "Output format", Choices: []string{"json", "text"}},
+				},
+				Commands: []*Command{
+					{Name: "users", Usage: "Manage users"},
+					{Name: "audit"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "commands",
+			args: []string{"completion", "bash", "--command=app admin", "--debug"},
+			want: []string{
+				`command path "app admin" resolved to "app admin"`,
+				"  users\tManage users\n",
+				"  audit\n",
+			},
+		},
+		{
+			name: "flags",
+			args: []string{"completion", "bash", "--flag=app admin", "--debug"},
+			want: []string{
+				`flag path "app admin" resolved to "app admin"`,
+				"  --format=\tOutput format\n",
+				"  --format=json\n",
+				"  --config=\tConfig file\n",
+			},
+		},
+		{
+			name: "unresolved",
+			args: []string{"completion", "bash", "--command=app missing", "--debug"},
+			want: []string{`command path "app missing" does not resolve to a command`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root.SetArgs(tt.args)
+			var stdout string
+			stderr := captureStderr(t, func() {
+				stdout = captureStdout(t, func() {
+					if err := root.Execute(context.Background()); err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+				})
+			})
+
+			for _, want := range tt.want {
+				if !strings.Contains(stderr, want) {
+					t.Errorf("expected debug output to contain %q, got:\n%s", want, stderr)
+				}
+			}
+			if strings.Contains(stdout, "candidates:") {
+				t.Errorf("debug output should not be written to stdout, got:\n%s", stdout)
+			}
+		})
+	}
+}
+
+func TestCompletionDebugMatchesOutput(t *testing.T) {
+	root := &Command{
+		Name:            "app",
+		CompleteNegated: true,
+		Flags:           []Flag{&BoolFlag{Name: "verbose", Usage: "Verbose output", Global: true}},
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name:      "get",
+				Usage:     "Get a resource",
+				ValidArgs: []string{"pods", "services"},
+				Flags:     []Flag{&StringFlag{Name: "format", Aliases: []string{"f"}, Choices: []string{"json", "text"}}},
+			},
+		},
+	}
+
+	for _, path := range []string{"--command=app get", "--flag=app get", "--command=app"} {
+		t.Run(path, func(t *testing.T) {
+			root.SetArgs([]string{"completion", "fish", path})
+			stdout := captureStdout(t, func() {
+				if err := root.Execute(context.Background()); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			})
+
+			root.SetArgs([]string{"completion", "fish", path, "--debug"})
+			stderr := captureStderr(t, func() {
+				captureStdout(t, func() {
+					if err := root.Execute(context.Background()); err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+				})
+			})
+
+			_, candidates, ok := strings.Cut(stderr, "candidates:\n")
+			if !ok {
+				t.Fatalf("expected candidates in the debug output, got:\n%s", stderr)
+			}
+			var want strings.Builder
+			for _, line := range strings.Split(strings.TrimSuffix(stdout, "\n"), "\n") {
+				want.WriteString("  " + line + "\n")
+			}
+			if candidates != want.String() {
+				t.Errorf("expected the debug candidates to match the completions\n%s\ngot:\n%s", want.String(), candidates)
+			}
+		})
+	}
+}
+
+func TestCommandCompletionAliases(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name:    "remove",
+				Aliases: []string{"rm"},
+				Usage:   "Remove an item",
+				Flags:   []Flag{&BoolFlag{Name: "force"}},
+			},
+		},
+	}
+
+	root.SetArgs([]string{"completion", "fish", "--command=app"})
+	out := captureStdout(t, func() {
+		if err := root.Execute(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(out, "remove\tRemove an item\n") || !strings.Contains(out, "rm\tRemove an item\n") {
+		t.Errorf("expected the command and its alias, got %q", out)
+	}
+
+	// Flags are completed when the command path uses the alias
+	root.SetArgs([]string{"completion", "bash", "--flag=app rm"})
+	out = captureStdout(t, func() {
+		if err := root.Execute(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if strings.TrimSpace(out) != "--force" {
+		t.Errorf("expected --force, got %q", out)
+	}
+}
+
+func TestCommandCompletionArgumentCompleteFunc(t *testing.T) {
+	var gotWord string
+	root := &Command{
+		Name: "app",
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name: "deploy",
+				Arguments: []Argument{
+					&StringArg{
+						Name: "project",
+						CompleteFunc: func(c *Command, toComplete string) []string {
+							gotWord = toComplete
+							return []string{"api", "web"}
+						},
+					},
+					&StringArg{Name: "env", Choices: []string{"staging", "production"}},
+				},
+				Flags:    []Flag{&BoolFlag{Name: "force"}},
+				Commands: []*Command{{Name: "status"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"first argument", "--command=app deploy", "status api web"},
+		{"second argument", "--command=app deploy api", "staging production"},
+		{"no more arguments", "--command=app deploy api staging", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root.SetArgs([]string{"completion", "bash", tt.path, "--word=a"})
+			out := captureStdout(t, func() {
+				if err := root.Execute(context.Background()); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			})
+
+			if got := strings.Join(strings.Fields(out), " "); got != tt.want {
+				t.Errorf("expected completions %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if gotWord != "a" {
+		t.Errorf("expected CompleteFunc to receive the word being completed, got %q", gotWord)
+	}
+
+	// Flags are completed after arguments
+	root.SetArgs([]string{"completion", "bash", "--flag=app deploy api"})
+	out := captureStdout(t, func() {
+		if err := root.Execute(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if strings.TrimSpace(out) != "--force" {
+		t.Errorf("expected --force, got %q", out)
+	}
+}
+
+func TestFlagValueCompletion(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Flags: []Flag{
+			&StringFlag{Name: "format", Global: true, Choices: []string{"json", "text"}},
+		},
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name: "deploy",
+				Flags: []Flag{
+					&StringFlag{
+						Name:    "project",
+						Aliases: []string{"p"},
+						CompleteFunc: func(c *Command, toComplete string) []string {
+							return []string{"api", "web"}
+						},
+					},
+					&BoolFlag{Name: "force"},
+				},
+				Commands: []*Command{{Name: "status"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"flag value", []string{"--flag=app deploy", "--value=--project"}, "api web"},
+		{"alias value", []string{"--flag=app deploy", "--value=-p"}, "api web"},
+		{"global flag value", []string{"--flag=app deploy", "--value=--format"}, "json text"},
+		{"bool flag falls back to commands", []string{"--flag=app deploy", "--value=--force"}, "status"},
+		{"flag=value form", []string{"--flag=app deploy", "--word=--project=a"}, "--project=api --project=web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root.SetArgs(append([]string{"completion", "bash"}, tt.args...))
+			out := captureStdout(t, func() {
+				if err := root.Execute(context.Background()); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			})
+
+			if got := strings.Join(strings.Fields(out), " "); got != tt.want {
+				t.Errorf("expected completions %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompletionFilesDirective(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name: "apply",
+				Flags: []Flag{
+					&StringFlag{Name: "config", CompleteFiles: true, FileExtensions: []string{".yaml", "yml"}},
+					&StringFlag{Name: "output", CompleteFiles: true},
+				},
+				Arguments: []Argument{
+					&StringSliceArg{Name: "files", CompleteFiles: true},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"flag with extensions", []string{"--flag=app apply", "--value=--config"}, ":files yaml yml"},
+		{"flag without extensions", []string{"--flag=app apply", "--value=--output"}, ":files"},
+		{"flag=value form", []string{"--flag=app apply", "--word=--output="}, ":files"},
+		{"argument", []string{"--command=app apply"}, ":files"},
+		{"variadic argument", []string{"--command=app apply one.txt two.txt"}, ":files"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root.SetArgs(append([]string{"completion", "bash"}, tt.args...))
+			out := captureStdout(t, func() {
+				if err := root.Execute(context.Background()); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			})
+
+			if got := strings.TrimSpace(out); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPowershellCompletionDescriptionWithColons(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Flags: []Flag{
+			&StringFlag{Name: "addr", Usage: "Listen address, e.g. host:port", Global: true},
+		},
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name:  "serve",
+				Usage: "Serve on: http://localhost:8080",
+				Flags: []Flag{&IntFlag{Name: "port", Usage: "Port: 1-65535"}},
+			},
+		},
+	}
+
+	root.SetArgs([]string{"completion", "powershell", "--command=app"})
+	out := captureStdout(t, func() {
+		if err := root.Execute(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(out, "serve\tServe on: http://localhost:8080\n") {
+		t.Errorf("expected the full command description after a tab, got %q", out)
+	}
+
+	root.SetArgs([]string{"completion", "powershell", "--flag=app serve"})
+	out = captureStdout(t, func() {
+		if err := root.Execute(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	for _, want := range []string{"--port=\tPort: 1-65535\n", "--addr=\tListen address, e.g. host:port\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in flag completions, got %q", want, out)
+		}
+	}
+
+	// The script splits the value from the description at the first tab
+	var script strings.Builder
+	if err := generateDynamicPowershellCompletion(&script, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(script.String(), `$line -match '^([^\t]*)(?:\t(.*))?$'`) {
+		t.Errorf("expected the script to split completions at a tab")
+	}
+}
+
+func TestFlagCompletionAliases(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Flags: []Flag{
+			&BoolFlag{Name: "verbose", Aliases: []string{"v"}, Usage: "Verbose output", Global: true},
+		},
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name: "serve",
+				Flags: []Flag{
+					&IntFlag{Name: "port", Aliases: []string{"p", "listen-port"}, Usage: "Port to listen on"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{"--port=", "-p", "--listen-port=", "--verbose", "-v"}},
+		{"fish", []string{
+			"--port=\tPort to listen on", "-p\tPort to listen on", "--listen-port=\tPort to listen on",
+			"--verbose\tVerbose output", "-v\tVerbose output",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			root.SetArgs([]string{"completion", tt.shell, "--flag=app serve"})
+			out := captureStdout(t, func() {
+				if err := root.Execute(context.Background()); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			})
+
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			if strings.Join(lines, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("expected completions %q, got %q", tt.want, lines)
+			}
+		})
+	}
+}
+
+func TestNushellCompletion(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Commands: []*Command{
+			GenerateCompletionCommand(),
+			{
+				Name:  "serve",
+				Usage: "Start the server",
+				Flags: []Flag{&IntFlag{Name: "port", Usage: "Port to listen on"}},
+			},
+		},
+	}
+
+	var script strings.Builder
+	if err := generateDynamicNushellCompletion(&script, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`def "nu-complete app" [context: string]`,
+		`completion nushell $"--command=($cmd_path)"`,
+		`completion nushell $"--flag=($cmd_path)"`,
+		`extern "app" [...args: string@"nu-complete app"]`,
+	} {
+		if !strings.Contains(script.String(), want) {
+			t.Errorf("expected the script to contain %q", want)
+		}
+	}
+
+	// Completions are returned with tab-separated descriptions
+	root.SetArgs([]string{"completion", "nushell", "--command=app"})
+	out := captureStdout(t, func() {
+		if err := root.Execute(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(out, "serve\tStart the server\n") {
+		t.Errorf("expected the command with its description, got %q", out)
+	}
+
+	root.SetArgs([]string{"completion", "nushell", "--flag=app serve"})
+	out = captureStdout(t, func() {
+		if err := root.Execute(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(out, "--port=\tPort to listen on\n") {
+		t.Errorf("expected the flag with its description, got %q", out)
+	}
+}
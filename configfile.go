@@ -4,17 +4,27 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 )
 
 var (
 	ConfigFileNotFoundError = fmt.Errorf("configuration file not found")
+
+	// DefaultReloadDelay is how long a watched configuration file must go without being written before it's reloaded,
+	// used when the ReloadDelay of the file isn't set
+	DefaultReloadDelay = 200 * time.Millisecond
 )
 
+// memoryFileName is reported by FileUsed for configuration read from memory
+const memoryFileName = "[memory]"
+
 type ConfigFileSource interface {
 	GetValue(string) (any, bool)            // Get the value from the configuration file at the specified path.
 	GetKeys(string) []string                // Get the keys from the configuration file at the specified path.
@@ -26,31 +36,57 @@ type ConfigFileSource interface {
 	LoadData() error                        // Load the configuration data from the file.
 }
 
+// ConfigFileKeysNotifier is implemented by configuration sources that can report which keys changed, it's optional so
+// check for it with a type assertion
+type ConfigFileKeysNotifier interface {
+	OnChangeKeys(ConfigFileKeysHandler) error // Track changes to the configuration file, with the paths of the keys that changed.
+}
+
 type SearchPathFunc func() []string
 type ConfigFileUnmarshal func(data []byte, v any) error
 type ConfigFileMarshal func(v any) ([]byte, error)
+type ConfigFileMerge func(original []byte, v any) ([]byte, error)
 type ConfigFileChangeHandler func()
+type ConfigFileKeysHandler func(changed []string)
 
 type ConfigFileBase struct {
 	FileName      *string                 // Point to the configuration file name
 	SearchPath    SearchPathFunc          // Function to define the search paths for the config file
 	Unmarshal     ConfigFileUnmarshal     // Function to decode the configuration file content
 	Marshal       ConfigFileMarshal       // Function to encode the configuration file content
+	Merge         ConfigFileMerge         // Optional function to update the existing file content on save, e.g. to keep comments
+	Content       []byte                  // In-memory configuration used in place of a file, e.g. for tests, Save does nothing when set
+	ReloadDelay   time.Duration           // Time to wait after the watched file is written before reloading it, so several writes reload it once, DefaultReloadDelay if not set
+	PruneEmpty    bool                    // Whether DeleteKey removes the tables left empty by deleting a key, set by InitConfigFile
 	data          map[string]any          // Parsed configuration data
 	isLoaded      bool                    // Indicates if the configuration file has been loaded
-	mutex         sync.Mutex              // Mutex for thread-safe access to the configuration data
+	mutex         sync.RWMutex            // Mutex for thread-safe access to the configuration data
 	fileUsed      string                  // The file that was used to load the configuration
 	watcher       *fsnotify.Watcher       // File system watcher for monitoring changes
 	changeHandler ConfigFileChangeHandler // Change handler for config file changes
+	keysHandler   ConfigFileKeysHandler   // Change handler given the keys that changed
 }
 
-var _ ConfigFileSource = (*ConfigFileBase)(nil)
+var (
+	_ ConfigFileSource       = (*ConfigFileBase)(nil)
+	_ ConfigFileKeysNotifier = (*ConfigFileBase)(nil)
+)
+
+// onChangeKeys registers the handler with the source if it implements ConfigFileKeysNotifier
+func onChangeKeys(source ConfigFileSource, handler ConfigFileKeysHandler) error {
+	notifier, ok := source.(ConfigFileKeysNotifier)
+	if !ok {
+		return fmt.Errorf("OnChangeKeys not supported by %T", source)
+	}
+	return notifier.OnChangeKeys(handler)
+}
 
 func (c *ConfigFileBase) InitConfigFile() {
 	c.data = make(map[string]any)
 	c.isLoaded = false
-	c.mutex = sync.Mutex{}
+	c.mutex = sync.RWMutex{}
 	c.fileUsed = ""
+	c.PruneEmpty = true
 }
 
 // searchForConfigFile searches for the configuration file in the defined search paths and returns the file name including the path, if not found it returns an empty string.
@@ -100,6 +136,17 @@ func (c *ConfigFileBase) LoadData() error {
 		c.mutex.Lock()
 		defer c.mutex.Unlock()
 
+		if !c.isLoaded && c.Content != nil {
+			if len(c.Content) > 0 {
+				if err := c.Unmarshal(c.Content, &c.data); err != nil {
+					return err
+				}
+			}
+
+			c.isLoaded = true
+			c.fileUsed = memoryFileName
+		}
+
 		if !c.isLoaded {
 			filename := c.searchForConfigFile()
 			if filename == "" {
@@ -124,6 +171,11 @@ func (c *ConfigFileBase) LoadData() error {
 }
 
 func (c *ConfigFileBase) Save() error {
+	// In-memory configuration has no file to save to
+	if c.Content != nil {
+		return nil
+	}
+
 	if !c.isLoaded || c.fileUsed == "" {
 		// Assume the filename points to where the file should be created
 		c.fileUsed = *c.FileName
@@ -132,7 +184,13 @@ func (c *ConfigFileBase) Save() error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	contentBytes, err := c.Marshal(c.data)
+	var contentBytes []byte
+	var err error
+	if original, readErr := os.ReadFile(c.fileUsed); c.Merge != nil && readErr == nil {
+		contentBytes, err = c.Merge(original, c.data)
+	} else {
+		contentBytes, err = c.Marshal(c.data)
+	}
 	if err != nil {
 		return err
 	}
@@ -166,6 +224,13 @@ func (c *ConfigFileBase) traversePath(keys []string, current map[string]any) (ma
 	return current, true
 }
 
+// loadedData returns the configuration data, holding the lock so a reload can't swap it while it's being read
+func (c *ConfigFileBase) loadedData() map[string]any {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.data
+}
+
 func (c *ConfigFileBase) GetValue(path string) (any, bool) {
 	if err := c.LoadData(); err != nil {
 		return nil, false
@@ -173,7 +238,7 @@ func (c *ConfigFileBase) GetValue(path string) (any, bool) {
 
 	// Extract the value based on the provided path
 	keys := strings.Split(path, ".")
-	current := c.data
+	current := c.loadedData()
 
 	var exists bool
 	if current, exists = c.traversePath(keys[:len(keys)-1], current); exists {
@@ -190,7 +255,7 @@ func (c *ConfigFileBase) GetKeys(path string) []string {
 		return nil
 	}
 
-	current := c.data
+	current := c.loadedData()
 	if path != "" {
 		var exists bool
 		current, exists = c.traversePath(strings.Split(path, "."), current)
@@ -208,6 +273,70 @@ func (c *ConfigFileBase) GetKeys(path string) []string {
 	return result
 }
 
+// GetKeysRecursive returns the full dotted paths of all the leaf keys below path in the configuration, sorted.
+// Arrays are treated as leaf values.
+func GetKeysRecursive(cfg ConfigFileSource, path string) []string {
+	var result []string
+
+	for _, key := range cfg.GetKeys(path) {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+
+		if v, ok := cfg.GetValue(keyPath); ok {
+			if _, isMap := v.(map[string]any); isMap {
+				result = append(result, GetKeysRecursive(cfg, keyPath)...)
+				continue
+			}
+		}
+		result = append(result, keyPath)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// changedConfigKeys returns the dotted paths of the leaf keys that were added, removed or changed between the old and
+// new configuration data, sorted. Arrays are compared as leaf values.
+func changedConfigKeys(old, new map[string]any) []string {
+	oldValues := make(map[string]any)
+	flattenConfig(old, "", oldValues)
+	newValues := make(map[string]any)
+	flattenConfig(new, "", newValues)
+
+	var changed []string
+	for key, value := range newValues {
+		if oldValue, ok := oldValues[key]; !ok || !reflect.DeepEqual(oldValue, value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range oldValues {
+		if _, ok := newValues[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+// flattenConfig adds the leaf values of the data to values keyed by their dotted path
+func flattenConfig(data map[string]any, path string, values map[string]any) {
+	for key, value := range data {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+
+		if nested, isMap := value.(map[string]any); isMap {
+			flattenConfig(nested, keyPath, values)
+		} else {
+			values[keyPath] = value
+		}
+	}
+}
+
 func (c *ConfigFileBase) SetValue(path string, value any) error {
 	// Extract the keys from the path
 	keys := strings.Split(path, ".")
@@ -246,7 +375,16 @@ func (c *ConfigFileBase) DeleteKey(path string) error {
 
 	var exists bool
 	if current, exists = c.traversePath(keys[:len(keys)-1], current); exists {
+		_, found := current[keys[len(keys)-1]]
 		delete(current, keys[len(keys)-1])
+
+		// Remove the parent tables left empty by deleting the key, deepest first
+		if c.PruneEmpty && found {
+			for i := len(keys) - 1; i > 0 && len(current) == 0; i-- {
+				current, _ = c.traversePath(keys[:i-1], c.data)
+				delete(current, keys[i-1])
+			}
+		}
 	}
 
 	return nil
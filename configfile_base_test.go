@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/BurntSushi/toml"
 )
 
 func newJSONConfigBase(t *testing.T, content string) (*ConfigFileBase, string) {
@@ -105,6 +108,57 @@ func TestConfigFileBase_SetValue_DeleteKey(t *testing.T) {
 	}
 }
 
+func TestConfigFileBase_DeleteKey_PrunesEmptyTables(t *testing.T) {
+	cfg, path := newJSONConfigBase(t, `{"service":{"name":"api","cache":{"ttl":30}},"other":{"empty":{}}}`)
+
+	if err := cfg.DeleteKey("service.cache.ttl"); err != nil {
+		t.Fatalf("DeleteKey error: %v", err)
+	}
+	if _, ok := cfg.GetValue("service.cache"); ok {
+		t.Error("service.cache should be removed once empty")
+	}
+	if v, ok := cfg.GetValue("service.name"); !ok || v != "api" {
+		t.Errorf("service.name: got %v, %v", v, ok)
+	}
+
+	// Deleting the last key removes every table left empty
+	if err := cfg.DeleteKey("service.name"); err != nil {
+		t.Fatalf("DeleteKey error: %v", err)
+	}
+	if _, ok := cfg.GetValue("service"); ok {
+		t.Error("service should be removed once empty")
+	}
+
+	// Deleting a key that doesn't exist leaves existing empty tables alone
+	if err := cfg.DeleteKey("other.empty.missing"); err != nil {
+		t.Fatalf("DeleteKey error: %v", err)
+	}
+	if _, ok := cfg.GetValue("other.empty"); !ok {
+		t.Error("other.empty should be kept")
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != `{"other":{"empty":{}}}` {
+		t.Errorf("saved %s", data)
+	}
+}
+
+func TestConfigFileBase_DeleteKey_KeepsEmptyTables(t *testing.T) {
+	cfg, _ := newJSONConfigBase(t, `{"service":{"cache":{"ttl":30}}}`)
+	cfg.PruneEmpty = false
+
+	if err := cfg.DeleteKey("service.cache.ttl"); err != nil {
+		t.Fatalf("DeleteKey error: %v", err)
+	}
+	v, ok := cfg.GetValue("service.cache")
+	if !ok || len(v.(map[string]any)) != 0 {
+		t.Errorf("service.cache: got %v, %v, want an empty table", v, ok)
+	}
+}
+
 func TestConfigFileBase_SetValue_NestedCreate(t *testing.T) {
 	cfg, _ := newJSONConfigBase(t, `{}`)
 
@@ -206,3 +260,66 @@ func TestConfigFileBase_Save_NewFile(t *testing.T) {
 		t.Error("saved file is empty")
 	}
 }
+
+func TestConfigFileBase_QuotedNumbers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.toml")
+	content := "port = \"8080\"\ntimeout = \" 30 \"\nratio = \"0.75\"\nmask = \"0xFF\"\nname = \"not-a-number\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	cfg := &ConfigFileBase{}
+	cfg.InitConfigFile()
+	cfg.FileName = &path
+	cfg.Unmarshal = toml.Unmarshal
+	cfg.Marshal = toml.Marshal
+
+	typed := NewTypedConfigFile(cfg)
+	if got := typed.GetInt("port"); got != 8080 {
+		t.Errorf("GetInt(port) = %d, want 8080", got)
+	}
+	if got := typed.GetInt64("timeout"); got != 30 {
+		t.Errorf("GetInt64(timeout) = %d, want 30", got)
+	}
+	if got := typed.GetUint16("port"); got != 8080 {
+		t.Errorf("GetUint16(port) = %d, want 8080", got)
+	}
+	if got := typed.GetFloat64("ratio"); got != 0.75 {
+		t.Errorf("GetFloat64(ratio) = %v, want 0.75", got)
+	}
+	if got := typed.GetInt("mask"); got != 255 {
+		t.Errorf("GetInt(mask) = %d, want 255", got)
+	}
+	if got := typed.GetInt("name"); got != 0 {
+		t.Errorf("GetInt(name) = %d, want 0", got)
+	}
+	if got := typed.GetString("port"); got != "8080" {
+		t.Errorf("GetString(port) = %q, want \"8080\"", got)
+	}
+}
+
+func TestChangedConfigKeys(t *testing.T) {
+	old := map[string]any{
+		"name":   "app",
+		"port":   float64(8080),
+		"tags":   []any{"a", "b"},
+		"server": map[string]any{"host": "localhost", "tls": map[string]any{"enabled": false}},
+		"log":    "info",
+	}
+	new := map[string]any{
+		"name":   "app",
+		"port":   float64(9090),
+		"tags":   []any{"a", "c"},
+		"server": map[string]any{"host": "localhost", "tls": map[string]any{"enabled": true}},
+		"debug":  true,
+	}
+
+	want := []string{"debug", "log", "port", "server.tls.enabled", "tags"}
+	if got := changedConfigKeys(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("changedConfigKeys() = %v, want %v", got, want)
+	}
+
+	if got := changedConfigKeys(old, old); len(got) != 0 {
+		t.Errorf("changedConfigKeys() of the same data = %v, want none", got)
+	}
+}
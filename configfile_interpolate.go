@@ -0,0 +1,70 @@
+package cli
+
+import "github.com/paularlott/cli/env"
+
+// InterpolatedConfigFile wraps a configuration source expanding environment variables, ${VAR} or $VAR, in the string
+// values it returns, e.g. url = "postgres://${DB_HOST}/app". Variables that aren't set are left as they are. Values are
+// stored and saved as written, only the values returned by GetValue are expanded.
+type InterpolatedConfigFile struct {
+	ConfigFileSource
+}
+
+var (
+	_ ConfigFileSource       = (*InterpolatedConfigFile)(nil)
+	_ ConfigFileKeysNotifier = (*InterpolatedConfigFile)(nil)
+)
+
+// NewInterpolatedConfigFile returns a configuration source expanding environment variables in the values of source
+func NewInterpolatedConfigFile(source ConfigFileSource) *InterpolatedConfigFile {
+	return &InterpolatedConfigFile{ConfigFileSource: source}
+}
+
+// GetValue returns the value at the path with environment variables expanded in strings, including those in slices
+// and objects
+func (c *InterpolatedConfigFile) GetValue(path string) (any, bool) {
+	value, ok := c.ConfigFileSource.GetValue(path)
+	if !ok {
+		return nil, false
+	}
+	return interpolateValue(value), true
+}
+
+// OnChangeKeys calls the handler with the keys that changed when the wrapped source changes, an error is returned if
+// the source doesn't implement ConfigFileKeysNotifier
+func (c *InterpolatedConfigFile) OnChangeKeys(handler ConfigFileKeysHandler) error {
+	return onChangeKeys(c.ConfigFileSource, handler)
+}
+
+// interpolateValue expands environment variables in strings, slices and objects are copied rather than changed in
+// place so the underlying configuration keeps the original text
+func interpolateValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return env.Expand(v)
+	case []string:
+		result := make([]string, len(v))
+		for i, s := range v {
+			result[i] = env.Expand(s)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = interpolateValue(item)
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for k, item := range v {
+			result[k] = interpolateValue(item)
+		}
+		return result
+	case []map[string]any:
+		result := make([]map[string]any, len(v))
+		for i, item := range v {
+			result[i] = interpolateValue(item).(map[string]any)
+		}
+		return result
+	}
+	return value
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInterpolatedConfigFileGetValue(t *testing.T) {
+	t.Setenv("CLI_TEST_HOST", "db.local")
+	t.Setenv("CLI_TEST_PORT", "5432")
+
+	base, _ := newJSONConfigBase(t, `{
+		"url": "postgres://${CLI_TEST_HOST}:$CLI_TEST_PORT/app",
+		"missing": "${CLI_TEST_UNSET}",
+		"port": 8080,
+		"hosts": ["$CLI_TEST_HOST", "other", 1],
+		"server": {"host": "${CLI_TEST_HOST}", "tags": ["$CLI_TEST_PORT"]},
+		"backends": [{"host": "$CLI_TEST_HOST"}]
+	}`)
+
+	cfg := NewInterpolatedConfigFile(base)
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("LoadData failed: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want any
+	}{
+		{"url", "postgres://db.local:5432/app"},
+		{"missing", "${CLI_TEST_UNSET}"},
+		{"port", float64(8080)},
+		{"hosts", []any{"db.local", "other", float64(1)}},
+		{"server.host", "db.local"},
+		{"server", map[string]any{"host": "db.local", "tags": []any{"5432"}}},
+		{"backends", []any{map[string]any{"host": "db.local"}}},
+	}
+
+	for _, tt := range tests {
+		got, ok := cfg.GetValue(tt.path)
+		if !ok {
+			t.Errorf("GetValue(%q) not found", tt.path)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetValue(%q) = %#v, want %#v", tt.path, got, tt.want)
+		}
+	}
+
+	if _, ok := cfg.GetValue("nope"); ok {
+		t.Error("GetValue(nope) should not be found")
+	}
+}
+
+func TestInterpolatedConfigFileKeepsReferences(t *testing.T) {
+	t.Setenv("CLI_TEST_HOST", "db.local")
+
+	base, _ := newJSONConfigBase(t, `{"hosts": ["$CLI_TEST_HOST"]}`)
+	cfg := NewInterpolatedConfigFile(base)
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("LoadData failed: %v", err)
+	}
+
+	cfg.GetValue("hosts")
+	got, _ := base.GetValue("hosts")
+	if !reflect.DeepEqual(got, []any{"$CLI_TEST_HOST"}) {
+		t.Errorf("underlying value = %#v, want the reference kept", got)
+	}
+
+	// Typed access goes through the interpolating source
+	typed := NewTypedConfigFile(cfg)
+	if got := typed.GetStringSlice("hosts"); !reflect.DeepEqual(got, []string{"db.local"}) {
+		t.Errorf("GetStringSlice(hosts) = %#v, want [db.local]", got)
+	}
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergedConfigFile layers several configuration sources, e.g. a system, user and project file, into one. Values are
+// read from the highest priority source that has them, objects are merged across the sources, and changes are made
+// to the highest priority source that is read from a file rather than memory.
+type MergedConfigFile struct {
+	sources []ConfigFileSource
+}
+
+var (
+	_ ConfigFileSource       = (*MergedConfigFile)(nil)
+	_ ConfigFileKeysNotifier = (*MergedConfigFile)(nil)
+)
+
+// NewMergedConfigFile returns a configuration source layering the sources given in priority order, highest first,
+// e.g. the project, user and then system configuration.
+func NewMergedConfigFile(sources ...ConfigFileSource) *MergedConfigFile {
+	return &MergedConfigFile{sources: sources}
+}
+
+// target returns the source changes are made to, the first source not read from memory, or the first source if all are
+func (m *MergedConfigFile) target() (ConfigFileSource, error) {
+	if len(m.sources) == 0 {
+		return nil, fmt.Errorf("merged configuration has no sources")
+	}
+
+	for _, source := range m.sources {
+		if source.FileUsed() != memoryFileName {
+			return source, nil
+		}
+	}
+	return m.sources[0], nil
+}
+
+// GetValue returns the value from the first source that has it, when the value is an object the keys of the same
+// object in the lower priority sources are merged in.
+func (m *MergedConfigFile) GetValue(path string) (any, bool) {
+	var result any
+	found := false
+
+	for _, source := range m.sources {
+		value, ok := source.GetValue(path)
+		if !ok {
+			continue
+		}
+
+		if !found {
+			result, found = value, true
+			if _, isMap := value.(map[string]any); !isMap {
+				return result, true
+			}
+			continue
+		}
+
+		if lower, isMap := value.(map[string]any); isMap {
+			result = mergeConfigMaps(result.(map[string]any), lower)
+		}
+	}
+
+	return result, found
+}
+
+// mergeConfigMaps returns a copy of high with the keys from low that it doesn't have, nested objects are merged
+func mergeConfigMaps(high, low map[string]any) map[string]any {
+	merged := make(map[string]any, len(high)+len(low))
+	for k, v := range low {
+		merged[k] = v
+	}
+
+	for k, v := range high {
+		highMap, highIsMap := v.(map[string]any)
+		lowMap, lowIsMap := merged[k].(map[string]any)
+		if highIsMap && lowIsMap {
+			merged[k] = mergeConfigMaps(highMap, lowMap)
+		} else {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
+
+// GetKeys returns the keys at the path across all the sources, each key is only returned once
+func (m *MergedConfigFile) GetKeys(path string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+
+	for _, source := range m.sources {
+		for _, key := range source.GetKeys(path) {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys
+}
+
+// SetValue sets the value in the highest priority source backed by a file
+func (m *MergedConfigFile) SetValue(path string, value any) error {
+	target, err := m.target()
+	if err != nil {
+		return err
+	}
+	return target.SetValue(path, value)
+}
+
+// DeleteKey deletes the key from the highest priority source backed by a file, a value for the key in a lower
+// priority source is still returned
+func (m *MergedConfigFile) DeleteKey(path string) error {
+	target, err := m.target()
+	if err != nil {
+		return err
+	}
+	return target.DeleteKey(path)
+}
+
+// Save saves the highest priority source backed by a file
+func (m *MergedConfigFile) Save() error {
+	target, err := m.target()
+	if err != nil {
+		return err
+	}
+	return target.Save()
+}
+
+// OnChange calls the handler when any of the sources change, sources whose file doesn't exist are not watched
+func (m *MergedConfigFile) OnChange(handler ConfigFileChangeHandler) error {
+	for _, source := range m.sources {
+		if err := source.OnChange(handler); err != nil && err != ConfigFileNotFoundError {
+			return err
+		}
+	}
+	return nil
+}
+
+// OnChangeKeys calls the handler with the keys that changed when any of the sources change, a key overridden by a
+// higher priority source is reported even if the merged value is the same. An error is returned if a source doesn't
+// implement ConfigFileKeysNotifier.
+func (m *MergedConfigFile) OnChangeKeys(handler ConfigFileKeysHandler) error {
+	for _, source := range m.sources {
+		if err := onChangeKeys(source, handler); err != nil && err != ConfigFileNotFoundError {
+			return err
+		}
+	}
+	return nil
+}
+
+// FileUsed returns the files used by the sources, highest priority first and separated by commas
+func (m *MergedConfigFile) FileUsed() string {
+	var files []string
+	for _, source := range m.sources {
+		if file := source.FileUsed(); file != "" {
+			files = append(files, file)
+		}
+	}
+	return strings.Join(files, ", ")
+}
+
+// LoadData loads all the sources, sources whose file can't be found are skipped and ConfigFileNotFoundError is only
+// returned if none of them could be found
+func (m *MergedConfigFile) LoadData() error {
+	loaded := false
+	for _, source := range m.sources {
+		err := source.LoadData()
+		if err == ConfigFileNotFoundError {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		loaded = true
+	}
+
+	if !loaded {
+		return ConfigFileNotFoundError
+	}
+	return nil
+}
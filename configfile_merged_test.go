@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newJSONConfigReader(content string) *ConfigFileBase {
+	cfg := &ConfigFileBase{}
+	cfg.InitConfigFile()
+	cfg.Content = []byte(content)
+	cfg.Unmarshal = json.Unmarshal
+	cfg.Marshal = json.Marshal
+	return cfg
+}
+
+func TestMergedConfigFileGetValue(t *testing.T) {
+	project, _ := newJSONConfigBase(t, `{"server":{"port":9090},"name":"project"}`)
+	user := newJSONConfigReader(`{"server":{"host":"user.local","tls":{"enabled":true}},"name":"user"}`)
+	system := newJSONConfigReader(`{"server":{"host":"system.local","tls":{"cert":"/etc/cert"}},"log":"info"}`)
+
+	cfg := NewMergedConfigFile(project, user, system)
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("LoadData failed: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want any
+	}{
+		{"name", "project"},
+		{"server.port", float64(9090)},
+		{"server.host", "user.local"},
+		{"server.tls.enabled", true},
+		{"server.tls.cert", "/etc/cert"},
+		{"log", "info"},
+	}
+	for _, tt := range tests {
+		if got, ok := cfg.GetValue(tt.path); !ok || got != tt.want {
+			t.Errorf("GetValue(%s): expected %v, got %v (found %v)", tt.path, tt.want, got, ok)
+		}
+	}
+
+	if _, ok := cfg.GetValue("missing"); ok {
+		t.Error("expected missing to not be found")
+	}
+
+	server, _ := cfg.GetValue("server")
+	if m := server.(map[string]any); len(m) != 3 || m["host"] != "user.local" {
+		t.Errorf("expected the server objects to be merged, got %v", server)
+	}
+
+	if keys := strings.Join(GetKeysRecursive(cfg, ""), ","); keys != "log,name,server.host,server.port,server.tls.cert,server.tls.enabled" {
+		t.Errorf("unexpected keys %s", keys)
+	}
+}
+
+func TestMergedConfigFileWritesHighestPriorityFile(t *testing.T) {
+	project, projectPath := newJSONConfigBase(t, `{"name":"project"}`)
+	user := newJSONConfigReader(`{"name":"user","log":"debug"}`)
+
+	// The in-memory source is skipped when choosing where to write
+	cfg := NewMergedConfigFile(user, project)
+	if err := cfg.SetValue("log", "warn"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	content, err := os.ReadFile(projectPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if string(content) != `{"log":"warn","name":"project"}` {
+		t.Errorf("expected the change to be saved to the project file, got %s", content)
+	}
+	if log, _ := cfg.GetValue("log"); log != "debug" {
+		t.Errorf("expected the higher priority value to still be returned, got %v", log)
+	}
+}
+
+func TestMergedConfigFileMissingFiles(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	newMissing := func() *ConfigFileBase {
+		cfg := &ConfigFileBase{}
+		cfg.InitConfigFile()
+		cfg.FileName = &missing
+		cfg.Unmarshal = json.Unmarshal
+		cfg.Marshal = json.Marshal
+		return cfg
+	}
+
+	if err := NewMergedConfigFile(newMissing(), newMissing()).LoadData(); err != ConfigFileNotFoundError {
+		t.Errorf("expected ConfigFileNotFoundError when no file is found, got %v", err)
+	}
+
+	present, path := newJSONConfigBase(t, `{"port":8080}`)
+	cfg := NewMergedConfigFile(newMissing(), present)
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("expected a missing file to be skipped, got %v", err)
+	}
+	if got := cfg.FileUsed(); got != path {
+		t.Errorf("expected FileUsed %q, got %q", path, got)
+	}
+
+	// Flags are read through the merged source
+	var port int
+	cmd := &Command{
+		Name:       "app",
+		ConfigFile: cfg,
+		Flags:      []Flag{&IntFlag{Name: "port", ConfigPath: []string{"port"}, AssignTo: &port}},
+		Run:        func(ctx context.Context, cmd *Command) error { return nil },
+	}
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if port != 8080 {
+		t.Errorf("expected port 8080 from the config, got %d", port)
+	}
+}
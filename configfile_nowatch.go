@@ -7,3 +7,7 @@ import "fmt"
 func (c *ConfigFileBase) OnChange(handler ConfigFileChangeHandler) error {
 	return fmt.Errorf("OnChange not supported: build with '-tags cli_watch' tag to enable")
 }
+
+func (c *ConfigFileBase) OnChangeKeys(handler ConfigFileKeysHandler) error {
+	return fmt.Errorf("OnChangeKeys not supported: build with '-tags cli_watch' tag to enable")
+}
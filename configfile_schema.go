@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// GenerateConfigJSONSchema writes a JSON Schema describing the configuration file keys used by the flags
+// in the command tree, allowing editors to validate and complete configuration files.
+func GenerateConfigJSONSchema(root *Command, w io.Writer) error {
+	schema := map[string]any{
+		"$schema":    "http://json-schema.org/draft-07/schema#",
+		"title":      root.Name + " configuration",
+		"type":       "object",
+		"properties": map[string]any{},
+	}
+
+	addConfigSchemaProperties(root, schema)
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// addConfigSchemaProperties adds the config paths of the command's flags, and those of its subcommands, to the schema
+func addConfigSchemaProperties(cmd *Command, schema map[string]any) {
+	for _, flag := range cmd.Flags {
+		for _, path := range flag.getConfigPaths() {
+			keys := strings.Split(path, ".")
+
+			// Walk down the tree creating the object schemas for each section
+			current := schema
+			for _, key := range keys[:len(keys)-1] {
+				properties := current["properties"].(map[string]any)
+				next, ok := properties[key].(map[string]any)
+				if !ok || next["type"] != "object" {
+					next = map[string]any{
+						"type":       "object",
+						"properties": map[string]any{},
+					}
+					properties[key] = next
+				}
+				current = next
+			}
+
+			// The first flag to claim a key defines it
+			properties := current["properties"].(map[string]any)
+			if _, exists := properties[keys[len(keys)-1]]; !exists {
+				property := jsonSchemaForType(flag.valueType())
+				if flag.getUsage() != "" {
+					property["description"] = flag.getUsage()
+				}
+				properties[keys[len(keys)-1]] = property
+			}
+		}
+	}
+
+	for _, sub := range cmd.Commands {
+		addConfigSchemaProperties(sub, schema)
+	}
+}
+
+// jsonSchemaForType returns the JSON Schema type definition for a flag value type
+func jsonSchemaForType(t reflect.Type) map[string]any {
+	// IP addresses, CIDRs and URLs are written as strings
+	if t == ipType || t == ipNetType || t == urlType {
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": jsonSchemaForType(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchemaForType(t.Elem())}
+	default:
+		return map[string]any{}
+	}
+}
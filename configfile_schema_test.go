@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestGenerateConfigJSONSchema(t *testing.T) {
+	root := &Command{
+		Name: "app",
+		Flags: []Flag{
+			&StringFlag{Name: "listen", Usage: "Listen address", ConfigPath: []string{"server.listen"}},
+			&IntFlag{Name: "port", ConfigPath: []string{"server.port"}},
+			&BoolFlag{Name: "debug", ConfigPath: []string{"debug"}},
+			&StringFlag{Name: "name"},
+		},
+		Commands: []*Command{
+			{
+				Name: "sync",
+				Flags: []Flag{
+					&StringSliceFlag{Name: "hosts", ConfigPath: []string{"sync.hosts"}},
+					&Float64Flag{Name: "ratio", ConfigPath: []string{"sync.ratio"}},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateConfigJSONSchema(root, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	if schema["type"] != "object" {
+		t.Errorf("expected root type object, got %v", schema["type"])
+	}
+
+	property := func(path ...string) map[string]any {
+		t.Helper()
+		current := schema
+		for _, key := range path {
+			props, ok := current["properties"].(map[string]any)
+			if !ok {
+				t.Fatalf("no properties at %v", path)
+			}
+			current, ok = props[key].(map[string]any)
+			if !ok {
+				t.Fatalf("missing property %v", path)
+			}
+		}
+		return current
+	}
+
+	tests := []struct {
+		path     []string
+		wantType string
+	}{
+		{[]string{"server"}, "object"},
+		{[]string{"server", "listen"}, "string"},
+		{[]string{"server", "port"}, "integer"},
+		{[]string{"debug"}, "boolean"},
+		{[]string{"sync", "hosts"}, "array"},
+		{[]string{"sync", "ratio"}, "number"},
+	}
+	for _, tt := range tests {
+		if got := property(tt.path...)["type"]; got != tt.wantType {
+			t.Errorf("%v: expected type %q, got %v", tt.path, tt.wantType, got)
+		}
+	}
+
+	if got := property("server", "listen")["description"]; got != "Listen address" {
+		t.Errorf("expected description from usage, got %v", got)
+	}
+	if items := property("sync", "hosts")["items"].(map[string]any); items["type"] != "string" {
+		t.Errorf("expected string items, got %v", items["type"])
+	}
+	if _, ok := schema["properties"].(map[string]any)["name"]; ok {
+		t.Error("flags without a config path should not be in the schema")
+	}
+}
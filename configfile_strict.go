@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkConfigKeys returns an error listing the keys in the configuration file that aren't used by any flag in the command tree
+// and aren't covered by IgnoreConfigKeys.
+func (c *Command) checkConfigKeys() error {
+	known := make([]string, 0)
+	collectConfigPaths(c, &known)
+	known = append(known, c.IgnoreConfigKeys...)
+
+	var unknown []string
+	for _, key := range GetKeysRecursive(c.ConfigFile, "") {
+		if !configKeyMatches(key, known) {
+			unknown = append(unknown, key)
+		}
+	}
+
+	switch len(unknown) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("unknown config key '%s' in %s", unknown[0], c.ConfigFile.FileUsed())
+	default:
+		return fmt.Errorf("unknown config keys '%s' in %s", strings.Join(unknown, "', '"), c.ConfigFile.FileUsed())
+	}
+}
+
+// collectConfigPaths adds the config paths of the flags on the command and all its subcommands to paths.
+func collectConfigPaths(cmd *Command, paths *[]string) {
+	for _, flag := range cmd.Flags {
+		*paths = append(*paths, flag.getConfigPaths()...)
+	}
+
+	for _, sub := range cmd.Commands {
+		collectConfigPaths(sub, paths)
+	}
+}
+
+// configKeyMatches checks if key is one of the paths or sits below one of them, e.g. "plugins.foo.enabled" is matched by "plugins".
+func configKeyMatches(key string, paths []string) bool {
+	for _, path := range paths {
+		if key == path || strings.HasPrefix(key, path+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newStrictConfigCommand(t *testing.T, content string) *Command {
+	t.Helper()
+	cfg, _ := newJSONConfigBase(t, content)
+	return &Command{
+		Name:             "app",
+		ConfigFile:       cfg,
+		StrictConfigKeys: true,
+		Flags: []Flag{
+			&IntFlag{Name: "timeout", ConfigPath: []string{"server.timeout"}},
+		},
+		Commands: []*Command{
+			{
+				Name: "sync",
+				Flags: []Flag{
+					&StringSliceFlag{Name: "hosts", ConfigPath: []string{"sync.hosts"}},
+				},
+				Run: func(ctx context.Context, cmd *Command) error { return nil },
+			},
+		},
+		Run: func(ctx context.Context, cmd *Command) error { return nil },
+	}
+}
+
+func TestGetKeysRecursive(t *testing.T) {
+	cfg, _ := newJSONConfigBase(t, `{"name":"x","server":{"timeout":5,"tls":{"cert":"a"}},"hosts":["a","b"]}`)
+
+	expected := []string{"hosts", "name", "server.timeout", "server.tls.cert"}
+	if got := GetKeysRecursive(cfg, ""); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	expected = []string{"server.timeout", "server.tls.cert"}
+	if got := GetKeysRecursive(cfg, "server"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestStrictConfigKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		ignore  []string
+		wantErr string
+	}{
+		{
+			name:    "known keys",
+			content: `{"server":{"timeout":5},"sync":{"hosts":["a"]}}`,
+		},
+		{
+			name:    "misspelled key",
+			content: `{"server":{"timout":5}}`,
+			wantErr: "unknown config key 'server.timout'",
+		},
+		{
+			name:    "multiple unknown keys",
+			content: `{"server":{"timout":5},"debug":true}`,
+			wantErr: "unknown config keys 'debug', 'server.timout'",
+		},
+		{
+			name:    "ignored section",
+			content: `{"server":{"timeout":5},"plugins":{"foo":{"enabled":true}}}`,
+			ignore:  []string{"plugins"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newStrictConfigCommand(t, tt.content)
+			cmd.IgnoreConfigKeys = tt.ignore
+
+			os.Args = []string{"app"}
+			err := cmd.Execute(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStrictConfigKeys_Disabled(t *testing.T) {
+	cmd := newStrictConfigCommand(t, `{"server":{"timout":5}}`)
+	cmd.StrictConfigKeys = false
+
+	os.Args = []string{"app"}
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -3,7 +3,9 @@ package cli
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // mapConfigSource implements ConfigFileSource for a map[string]any
@@ -130,6 +132,13 @@ func (m *mapConfigSource) OnChange(h ConfigFileChangeHandler) error {
 	return nil
 }
 
+func (m *mapConfigSource) OnChangeKeys(h ConfigFileKeysHandler) error {
+	if m.readOnly {
+		return fmt.Errorf("mapConfigSource is read-only")
+	}
+	return nil
+}
+
 func (m *mapConfigSource) FileUsed() string {
 	return "[map source]"
 }
@@ -155,6 +164,8 @@ type ConfigFileTyped interface {
 	GetFloat32(string) float32               // Get the float32 value from the configuration file at the specified path.
 	GetFloat64(string) float64               // Get the float64 value from the configuration file at the specified path.
 	GetBool(string) bool                     // Get the bool value from the configuration file at the specified path.
+	GetDuration(string) time.Duration        // Get the duration, e.g. "30s", from the configuration file at the specified path.
+	GetTime(string) time.Time                // Get the RFC 3339 time from the configuration file at the specified path.
 	GetStringSlice(string) []string          // Get the string slice value from the configuration file at the specified path.
 	GetIntSlice(string) []int                // Get the int slice value from the configuration file at the specified path.
 	GetInt64Slice(string) []int64            // Get the int64 slice value from the configuration file at the specified path.
@@ -168,6 +179,11 @@ type ConfigFileTyped interface {
 	GetUint8Slice(string) []uint8            // Get the uint8 slice value from the configuration file at the specified path.
 	GetFloat32Slice(string) []float32        // Get the float32 slice value from the configuration file at the specified path.
 	GetFloat64Slice(string) []float64        // Get the float64 slice value from the configuration file at the specified path.
+	GetStringMap(string) map[string]string   // Get the scalar values of an object as strings, nested objects and arrays are skipped.
+	Has(string) bool                         // Check if the configuration file has a value at the specified path.
+	TypeOf(string) reflect.Kind              // Get the kind of the value at the specified path, reflect.Invalid if there's no value.
+	Unmarshal(string, any) error             // Decode the object at the specified path into a struct or map, using the config struct tags.
+	Validate(Schema) error                   // Check the configuration has the keys and kinds of value in the schema.
 	SetString(string, string) error          // Set the string value in the configuration file at the specified path.
 	SetInt(string, int) error                // Set the int value in the configuration file at the specified path.
 	SetInt64(string, int64) error            // Set the int64 value in the configuration file at the specified path.
@@ -182,6 +198,8 @@ type ConfigFileTyped interface {
 	SetFloat32(string, float32) error        // Set the float32 value in the configuration file at the specified path.
 	SetFloat64(string, float64) error        // Set the float64 value in the configuration file at the specified path.
 	SetBool(string, bool) error              // Set the bool value in the configuration file at the specified path.
+	SetDuration(string, time.Duration) error // Set the duration in the configuration file at the specified path, stored as a string.
+	SetTime(string, time.Time) error         // Set the time in the configuration file at the specified path, stored as an RFC 3339 string.
 	SetStringSlice(string, []string) error   // Set the string slice value in the configuration file at the specified path.
 	SetIntSlice(string, []int) error         // Set the int slice value in the configuration file at the specified path.
 	SetInt64Slice(string, []int64) error     // Set the int64 slice value in the configuration file at the specified path.
@@ -238,6 +256,9 @@ func (w *ConfigFileTypedWrapper) Save() error                       { return w.i
 func (w *ConfigFileTypedWrapper) OnChange(h ConfigFileChangeHandler) error {
 	return w.inner.OnChange(h)
 }
+func (w *ConfigFileTypedWrapper) OnChangeKeys(h ConfigFileKeysHandler) error {
+	return onChangeKeys(w.inner, h)
+}
 func (w *ConfigFileTypedWrapper) FileUsed() string { return w.inner.FileUsed() }
 
 // convertValue handles type conversion from any value to target type T
@@ -249,6 +270,35 @@ func convertValue[T any](value any) T {
 		return cast
 	}
 
+	// Numbers given as strings, e.g. port = "8080", are parsed and then converted
+	if s, ok := value.(string); ok {
+		s = strings.TrimSpace(s)
+		switch any(zero).(type) {
+		case int, int64, int32, int16, int8:
+			if i, err := strconv.ParseInt(s, 0, 64); err == nil {
+				return convertValue[T](i)
+			}
+		case uint, uint64, uint32, uint16, uint8:
+			if u, err := strconv.ParseUint(s, 0, 64); err == nil {
+				return convertValue[T](u)
+			}
+		case float32, float64:
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return convertValue[T](f)
+			}
+		case time.Duration:
+			if d, err := time.ParseDuration(s); err == nil {
+				return any(d).(T)
+			}
+			return zero
+		case time.Time:
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				return any(t).(T)
+			}
+			return zero
+		}
+	}
+
 	// Handle type conversions based on target type
 	switch any(zero).(type) {
 	case int:
@@ -495,9 +545,12 @@ func convertValue[T any](value any) T {
 		} else if i, ok := value.(int64); ok {
 			return any(fmt.Sprintf("%d", i)).(T)
 		} else if f, ok := value.(float64); ok {
-			return any(fmt.Sprintf("%g", f)).(T)
+			// Formatted without an exponent, JSON decodes all numbers as float64, e.g. 12345678
+			return any(strconv.FormatFloat(f, 'f', -1, 64)).(T)
 		} else if b, ok := value.(bool); ok {
 			return any(fmt.Sprintf("%t", b)).(T)
+		} else if st, ok := value.(fmt.Stringer); ok {
+			return any(st.String()).(T)
 		}
 	case bool:
 		if b, ok := value.(bool); ok {
@@ -535,6 +588,26 @@ func getAs[T any](c ConfigFileSource, path string) T {
 	return zero
 }
 
+// GetOK returns the value at path converted to T and whether the path has a value, telling a missing value apart from
+// one set to the zero value, e.g. port = 0. A value that can't be converted, such as port = "http" for an int, returns
+// the zero value and true, TypeOf can be used to check the type of the value.
+func GetOK[T any](c ConfigFileSource, path string) (T, bool) {
+	value, exists := c.GetValue(path)
+	if !exists {
+		var zero T
+		return zero, false
+	}
+	return convertValue[T](value), true
+}
+
+// GetSliceOK returns the array at path with each item converted to T and whether the path has a value
+func GetSliceOK[T any](c ConfigFileSource, path string) ([]T, bool) {
+	if _, exists := c.GetValue(path); !exists {
+		return nil, false
+	}
+	return getAsSlice[T](c, path), true
+}
+
 // getAsSlice now uses convertValue for each element
 func getAsSlice[T any](c ConfigFileSource, path string) []T {
 	if value, exists := c.GetValue(path); exists {
@@ -618,6 +691,18 @@ func (c *ConfigFileTypedWrapper) GetFloat64(path string) float64 {
 	return getAs[float64](c.inner, path)
 }
 
+// GetDuration returns the duration at path parsed with time.ParseDuration, e.g. "30s" or "1h30m", or 0 if it's missing
+// or not a valid duration
+func (c *ConfigFileTypedWrapper) GetDuration(path string) time.Duration {
+	return getAs[time.Duration](c.inner, path)
+}
+
+// GetTime returns the time at path parsed as RFC 3339, e.g. "2024-01-01T00:00:00Z", or the zero time if it's missing
+// or not a valid time. Times the reader has already decoded, such as TOML datetimes, are returned as they are.
+func (c *ConfigFileTypedWrapper) GetTime(path string) time.Time {
+	return getAs[time.Time](c.inner, path)
+}
+
 func (c *ConfigFileTypedWrapper) GetStringSlice(path string) []string {
 	return getAsSlice[string](c.inner, path)
 }
@@ -670,6 +755,46 @@ func (c *ConfigFileTypedWrapper) GetFloat64Slice(path string) []float64 {
 	return getAsSlice[float64](c.inner, path)
 }
 
+// GetStringMap returns the scalar children of the object at path converted to strings, e.g. a section of labels.
+// Nested objects and arrays are skipped rather than flattened, use GetObject to read them.
+func (c *ConfigFileTypedWrapper) GetStringMap(path string) map[string]string {
+	v, ok := c.inner.GetValue(path)
+	if !ok {
+		return nil
+	}
+
+	objMap, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string]string, len(objMap))
+	for key, value := range objMap {
+		switch value.(type) {
+		case map[string]any, []any, []map[string]any:
+			continue
+		}
+		result[key] = convertValue[string](value)
+	}
+	return result
+}
+
+// Has reports whether there is a value at path
+func (c *ConfigFileTypedWrapper) Has(path string) bool {
+	_, ok := c.inner.GetValue(path)
+	return ok
+}
+
+// TypeOf returns the kind of the value at path as stored by the configuration source, e.g. reflect.Map for an object
+// or reflect.Float64 for a JSON number, and reflect.Invalid if there is no value or it's nil.
+func (c *ConfigFileTypedWrapper) TypeOf(path string) reflect.Kind {
+	v, ok := c.inner.GetValue(path)
+	if !ok || v == nil {
+		return reflect.Invalid
+	}
+	return reflect.TypeOf(v).Kind()
+}
+
 func (c *ConfigFileTypedWrapper) SetString(path string, value string) error {
 	return c.SetValue(path, value)
 }
@@ -726,6 +851,16 @@ func (c *ConfigFileTypedWrapper) SetBool(path string, value bool) error {
 	return c.SetValue(path, value)
 }
 
+// SetDuration stores the duration as a string, e.g. "1m30s", that GetDuration can read back
+func (c *ConfigFileTypedWrapper) SetDuration(path string, value time.Duration) error {
+	return c.SetValue(path, value.String())
+}
+
+// SetTime stores the time as an RFC 3339 string, including fractional seconds if it has them
+func (c *ConfigFileTypedWrapper) SetTime(path string, value time.Time) error {
+	return c.SetValue(path, value.Format(time.RFC3339Nano))
+}
+
 func (c *ConfigFileTypedWrapper) SetStringSlice(path string, value []string) error {
 	return c.SetValue(path, value)
 }
@@ -792,30 +927,27 @@ func (c *ConfigFileTypedWrapper) GetObject(path string) ConfigFileTyped {
 	return nil
 }
 
+// GetObjectSlice returns the objects in the array at path, e.g. a TOML array of tables. Arrays decoded as []any, such
+// as from JSON, or as []map[string]any, such as from TOML, are both accepted, items that aren't objects are skipped.
 func (c *ConfigFileTypedWrapper) GetObjectSlice(path string) []ConfigFileTyped {
-	if v, ok := c.inner.GetValue(path); ok {
-		// Handle []any (JSON style)
-		if slice, ok := v.([]any); ok {
-			objects := make([]ConfigFileTyped, 0, len(slice))
-			for _, item := range slice {
-				if objMap, ok := item.(map[string]any); ok {
-					mapSource := &mapConfigSource{data: objMap, readOnly: true}
-					objects = append(objects, &ConfigFileTypedWrapper{inner: mapSource})
-				}
-			}
-			return objects
-		}
-		// Handle []map[string]interface{} (TOML style - BurntSushi/toml produces this type)
-		if slice, ok := v.([]map[string]interface{}); ok {
-			objects := make([]ConfigFileTyped, 0, len(slice))
-			for _, item := range slice {
-				mapSource := &mapConfigSource{data: item, readOnly: true}
-				objects = append(objects, &ConfigFileTypedWrapper{inner: mapSource})
-			}
-			return objects
+	v, ok := c.inner.GetValue(path)
+	if !ok {
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil
+	}
+
+	objects := make([]ConfigFileTyped, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		if objMap, ok := rv.Index(i).Interface().(map[string]any); ok {
+			mapSource := &mapConfigSource{data: objMap, readOnly: true}
+			objects = append(objects, &ConfigFileTypedWrapper{inner: mapSource})
 		}
 	}
-	return nil
+	return objects
 }
 
 // SetObject sets an object at the specified path.
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 // mockConfigSource implements ConfigFileSource for testing
@@ -249,6 +250,37 @@ func TestConfigFileTyped_GetObject(t *testing.T) {
 	}
 }
 
+func TestConfigFileTyped_GetStringMap(t *testing.T) {
+	config := NewTypedConfigFile(&mockConfigSource{data: map[string]any{
+		"labels": map[string]any{
+			"env":      "production",
+			"replicas": int64(3),
+			"ratio":    0.5,
+			"canary":   true,
+			"owner":    map[string]any{"team": "platform"},
+			"zones":    []any{"a", "b"},
+		},
+		"name": "app",
+	}})
+
+	expected := map[string]string{
+		"env":      "production",
+		"replicas": "3",
+		"ratio":    "0.5",
+		"canary":   "true",
+	}
+	if got := config.GetStringMap("labels"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	if got := config.GetStringMap("name"); got != nil {
+		t.Errorf("Expected nil for non-object value, got %v", got)
+	}
+	if got := config.GetStringMap("non_existent"); got != nil {
+		t.Errorf("Expected nil for non-existent path, got %v", got)
+	}
+}
+
 func TestConfigFileTyped_GetObjectSlice(t *testing.T) {
 	config := createTestConfig()
 
@@ -563,4 +595,231 @@ func TestConfigFileTyped_NewTypedConfigObjectWithData(t *testing.T) {
 	if got := nested.GetString("value"); got != "nested value" {
 		t.Errorf("Expected 'nested value', got %q", got)
 	}
-}
\ No newline at end of file
+}
+
+func TestConfigFileTyped_HasAndTypeOf(t *testing.T) {
+	obj := NewTypedConfigObjectWithData(map[string]any{
+		"name":   "test",
+		"port":   int64(8080),
+		"ratio":  0.5,
+		"debug":  true,
+		"tags":   []any{"a", "b"},
+		"empty":  nil,
+		"server": map[string]any{"host": "localhost"},
+	})
+
+	tests := []struct {
+		path string
+		has  bool
+		kind reflect.Kind
+	}{
+		{"name", true, reflect.String},
+		{"port", true, reflect.Int64},
+		{"ratio", true, reflect.Float64},
+		{"debug", true, reflect.Bool},
+		{"tags", true, reflect.Slice},
+		{"empty", true, reflect.Invalid},
+		{"server", true, reflect.Map},
+		{"server.host", true, reflect.String},
+		{"server.port", false, reflect.Invalid},
+		{"missing", false, reflect.Invalid},
+	}
+
+	for _, tt := range tests {
+		if got := obj.Has(tt.path); got != tt.has {
+			t.Errorf("Has(%q) = %v, want %v", tt.path, got, tt.has)
+		}
+		if got := obj.TypeOf(tt.path); got != tt.kind {
+			t.Errorf("TypeOf(%q) = %v, want %v", tt.path, got, tt.kind)
+		}
+	}
+}
+
+func TestConfigFileTyped_DurationAndTime(t *testing.T) {
+	deadline := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	obj := NewTypedConfigObjectWithData(map[string]any{
+		"timeout":  "30s",
+		"interval": "1h30m",
+		"bad":      "soon",
+		"number":   int64(30),
+		"deadline": "2024-01-01T00:00:00Z",
+		"native":   deadline,
+	})
+
+	if got := obj.GetDuration("timeout"); got != 30*time.Second {
+		t.Errorf("GetDuration(timeout) = %v, want 30s", got)
+	}
+	if got := obj.GetDuration("interval"); got != 90*time.Minute {
+		t.Errorf("GetDuration(interval) = %v, want 1h30m", got)
+	}
+	for _, path := range []string{"bad", "number", "missing"} {
+		if got := obj.GetDuration(path); got != 0 {
+			t.Errorf("GetDuration(%s) = %v, want 0", path, got)
+		}
+		if got := obj.GetTime(path); !got.IsZero() {
+			t.Errorf("GetTime(%s) = %v, want zero time", path, got)
+		}
+	}
+
+	if got := obj.GetTime("deadline"); !got.Equal(deadline) {
+		t.Errorf("GetTime(deadline) = %v, want %v", got, deadline)
+	}
+	if got := obj.GetTime("native"); !got.Equal(deadline) {
+		t.Errorf("GetTime(native) = %v, want %v", got, deadline)
+	}
+
+	// Values are stored as strings and read back
+	if err := obj.SetDuration("retry", 90*time.Second); err != nil {
+		t.Fatalf("SetDuration failed: %v", err)
+	}
+	if got := obj.GetString("retry"); got != "1m30s" {
+		t.Errorf("stored duration = %q, want 1m30s", got)
+	}
+	if got := obj.GetDuration("retry"); got != 90*time.Second {
+		t.Errorf("GetDuration(retry) = %v, want 1m30s", got)
+	}
+
+	updated := time.Date(2024, 6, 1, 12, 30, 0, 500, time.FixedZone("", 2*60*60))
+	if err := obj.SetTime("updated", updated); err != nil {
+		t.Fatalf("SetTime failed: %v", err)
+	}
+	if got := obj.GetString("updated"); got != "2024-06-01T12:30:00.0000005+02:00" {
+		t.Errorf("stored time = %q", got)
+	}
+	if got := obj.GetTime("updated"); !got.Equal(updated) {
+		t.Errorf("GetTime(updated) = %v, want %v", got, updated)
+	}
+}
+
+func TestConfigFileTyped_GetObjectSliceShapes(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"slice of maps", []map[string]any{{"host": "one"}, {"host": "two"}}},
+		{"slice of any", []any{map[string]any{"host": "one"}, map[string]any{"host": "two"}}},
+		{"slice of any with non-objects", []any{map[string]any{"host": "one"}, "skipped", 1, map[string]any{"host": "two"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewTypedConfigObjectWithData(map[string]any{"backends": tt.value})
+
+			backends := config.GetObjectSlice("backends")
+			if len(backends) != 2 {
+				t.Fatalf("Expected 2 objects, got %d", len(backends))
+			}
+			if got := backends[0].GetString("host"); got != "one" {
+				t.Errorf("Expected 'one', got %q", got)
+			}
+			if got := backends[1].GetString("host"); got != "two" {
+				t.Errorf("Expected 'two', got %q", got)
+			}
+		})
+	}
+
+	config := NewTypedConfigObjectWithData(map[string]any{"name": "value", "empty": []any{}})
+	if got := config.GetObjectSlice("name"); got != nil {
+		t.Errorf("Expected nil for non-array value, got %v", got)
+	}
+	if got := config.GetObjectSlice("empty"); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice for an empty array, got %v", got)
+	}
+}
+
+func TestConfigFileTyped_GetObjectSliceNested(t *testing.T) {
+	config := NewTypedConfigObjectWithData(map[string]any{
+		"cluster": map[string]any{
+			"pools": []map[string]any{
+				{"name": "a", "nodes": []any{map[string]any{"host": "a1"}, map[string]any{"host": "a2"}}},
+				{"name": "b", "nodes": []map[string]any{{"host": "b1"}}},
+			},
+		},
+	})
+
+	cluster := config.GetObject("cluster")
+	if cluster == nil {
+		t.Fatal("Expected cluster object, got nil")
+	}
+
+	pools := cluster.GetObjectSlice("pools")
+	if len(pools) != 2 {
+		t.Fatalf("Expected 2 pools, got %d", len(pools))
+	}
+
+	var hosts []string
+	for _, pool := range pools {
+		for _, node := range pool.GetObjectSlice("nodes") {
+			hosts = append(hosts, pool.GetString("name")+":"+node.GetString("host"))
+		}
+	}
+	if want := []string{"a:a1", "a:a2", "b:b1"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("Expected %v, got %v", want, hosts)
+	}
+
+	// Dotted paths reach arrays inside objects directly
+	if got := config.GetObjectSlice("cluster.pools"); len(got) != 2 {
+		t.Errorf("Expected 2 pools from the dotted path, got %d", len(got))
+	}
+}
+
+func TestGetOK(t *testing.T) {
+	config := NewTypedConfigObjectWithData(map[string]any{
+		"port":    int64(0),
+		"name":    "app",
+		"ratio":   "0.5",
+		"timeout": "30s",
+		"server":  map[string]any{"debug": false},
+		"ports":   []any{int64(80), "443"},
+	})
+
+	if v, ok := GetOK[int](config, "port"); !ok || v != 0 {
+		t.Errorf("GetOK[int](port) = %v, %v, want 0, true", v, ok)
+	}
+	if v, ok := GetOK[int](config, "missing"); ok || v != 0 {
+		t.Errorf("GetOK[int](missing) = %v, %v, want 0, false", v, ok)
+	}
+	if v, ok := GetOK[string](config, "name"); !ok || v != "app" {
+		t.Errorf("GetOK[string](name) = %q, %v", v, ok)
+	}
+	if v, ok := GetOK[float64](config, "ratio"); !ok || v != 0.5 {
+		t.Errorf("GetOK[float64](ratio) = %v, %v", v, ok)
+	}
+	if v, ok := GetOK[bool](config, "server.debug"); !ok || v {
+		t.Errorf("GetOK[bool](server.debug) = %v, %v, want false, true", v, ok)
+	}
+	if v, ok := GetOK[time.Duration](config, "timeout"); !ok || v != 30*time.Second {
+		t.Errorf("GetOK[time.Duration](timeout) = %v, %v", v, ok)
+	}
+
+	// A value that can't be converted still exists
+	if v, ok := GetOK[int](config, "name"); !ok || v != 0 {
+		t.Errorf("GetOK[int](name) = %v, %v, want 0, true", v, ok)
+	}
+
+	if v, ok := GetSliceOK[int](config, "ports"); !ok || !reflect.DeepEqual(v, []int{80, 443}) {
+		t.Errorf("GetSliceOK[int](ports) = %v, %v", v, ok)
+	}
+	if v, ok := GetSliceOK[int](config, "missing"); ok || v != nil {
+		t.Errorf("GetSliceOK[int](missing) = %v, %v, want nil, false", v, ok)
+	}
+}
+
+func TestConfigFileTyped_OnChangeKeysOptional(t *testing.T) {
+	// The mock source doesn't implement ConfigFileKeysNotifier
+	config := NewTypedConfigFile(&mockConfigSource{data: make(map[string]any)})
+	if err := config.OnChangeKeys(func([]string) {}); err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected an unsupported error, got %v", err)
+	}
+
+	merged := NewMergedConfigFile(&mockConfigSource{data: make(map[string]any)})
+	if err := merged.OnChangeKeys(func([]string) {}); err == nil {
+		t.Error("expected an error from the merged source")
+	}
+
+	// Sources that implement it are used through the wrappers
+	objects := NewTypedConfigFile(NewInterpolatedConfigFile(NewTypedConfigObject()))
+	if err := objects.OnChangeKeys(func([]string) {}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
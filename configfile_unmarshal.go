@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	timeType     = reflect.TypeFor[time.Time]()
+)
+
+// Unmarshal decodes the object at path into out, which must be a pointer to a struct or map. An empty path decodes the
+// whole configuration.
+//
+// Struct fields are matched to keys using the config tag, e.g. `config:"pool_size"`, or the field name ignoring case if
+// there's no tag, fields tagged `config:"-"` are skipped. Nested objects decode into structs and maps, arrays into
+// slices, durations and times are parsed from strings as by GetDuration and GetTime. Keys without a field are ignored
+// and fields without a key are left unchanged. An error is returned if a value doesn't match the type of its field.
+func (c *ConfigFileTypedWrapper) Unmarshal(path string, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("unmarshal needs a non-nil pointer, got %T", out)
+	}
+
+	var value any
+	if path == "" {
+		root := make(map[string]any)
+		for _, key := range c.inner.GetKeys("") {
+			root[key], _ = c.inner.GetValue(key)
+		}
+		value = root
+	} else {
+		var ok bool
+		if value, ok = c.inner.GetValue(path); !ok {
+			return fmt.Errorf("config key '%s' not found", path)
+		}
+	}
+
+	return decodeConfigValue(value, rv.Elem(), path)
+}
+
+// decodeConfigValue decodes the configuration value into target, path is the location of the value used in errors
+func decodeConfigValue(value any, target reflect.Value, path string) error {
+	if value == nil {
+		return nil
+	}
+
+	switch target.Type() {
+	case durationType:
+		if s, ok := value.(string); ok {
+			if d, err := time.ParseDuration(strings.TrimSpace(s)); err == nil {
+				target.SetInt(int64(d))
+				return nil
+			}
+		}
+		return configTypeError(value, target, path)
+	case timeType:
+		switch t := value.(type) {
+		case time.Time:
+			target.Set(reflect.ValueOf(t))
+			return nil
+		case string:
+			if parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(t)); err == nil {
+				target.Set(reflect.ValueOf(parsed))
+				return nil
+			}
+		}
+		return configTypeError(value, target, path)
+	}
+
+	switch target.Kind() {
+	case reflect.Pointer:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return decodeConfigValue(value, target.Elem(), path)
+
+	case reflect.Interface:
+		v := reflect.ValueOf(value)
+		if !v.Type().AssignableTo(target.Type()) {
+			return configTypeError(value, target, path)
+		}
+		target.Set(v)
+		return nil
+
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return configTypeError(value, target, path)
+		}
+		return decodeConfigStruct(obj, target, path)
+
+	case reflect.Map:
+		obj, ok := value.(map[string]any)
+		if !ok || target.Type().Key().Kind() != reflect.String {
+			return configTypeError(value, target, path)
+		}
+		if target.IsNil() {
+			target.Set(reflect.MakeMapWithSize(target.Type(), len(obj)))
+		}
+		for key, item := range obj {
+			elem := reflect.New(target.Type().Elem()).Elem()
+			if err := decodeConfigValue(item, elem, joinConfigPath(path, key)); err != nil {
+				return err
+			}
+			target.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), elem)
+		}
+		return nil
+
+	case reflect.Slice:
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice {
+			return configTypeError(value, target, path)
+		}
+		slice := reflect.MakeSlice(target.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if err := decodeConfigValue(v.Index(i).Interface(), slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return configTypeError(value, target, path)
+		}
+		target.SetString(s)
+		return nil
+
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return configTypeError(value, target, path)
+		}
+		target.SetBool(b)
+		return nil
+
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		i, ok := configInt(value)
+		if !ok || target.OverflowInt(i) {
+			return configTypeError(value, target, path)
+		}
+		target.SetInt(i)
+		return nil
+
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		i, ok := configInt(value)
+		if !ok || i < 0 || target.OverflowUint(uint64(i)) {
+			return configTypeError(value, target, path)
+		}
+		target.SetUint(uint64(i))
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		f, ok := configNumber(value)
+		if !ok || target.OverflowFloat(f) {
+			return configTypeError(value, target, path)
+		}
+		target.SetFloat(f)
+		return nil
+	}
+
+	return configTypeError(value, target, path)
+}
+
+// decodeConfigStruct decodes the keys of the object into the matching fields of the struct
+func decodeConfigStruct(obj map[string]any, target reflect.Value, path string) error {
+	t := target.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("config")
+		if tag == "-" {
+			continue
+		}
+
+		// Embedded structs without a tag share the keys of the object they're in, even if their type isn't exported
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			if err := decodeConfigStruct(obj, target.Field(i), path); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		key, value, ok := configStructKey(obj, field.Name, tag)
+		if !ok {
+			continue
+		}
+		if err := decodeConfigValue(value, target.Field(i), joinConfigPath(path, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configStructKey finds the key of the object for a field, the tag if given or else the field name ignoring case
+func configStructKey(obj map[string]any, name, tag string) (string, any, bool) {
+	if tag != "" {
+		value, ok := obj[tag]
+		return tag, value, ok
+	}
+
+	if value, ok := obj[name]; ok {
+		return name, value, true
+	}
+	for key, value := range obj {
+		if strings.EqualFold(key, name) {
+			return key, value, true
+		}
+	}
+	return "", nil, false
+}
+
+// configNumber returns a numeric configuration value as a float64, numbers stored as strings are parsed
+func configNumber(value any) (float64, bool) {
+	if s, ok := value.(string); ok {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return f, err == nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// configInt returns a whole number configuration value as an int64, numbers stored as strings are parsed and floats,
+// such as JSON numbers, must not have a fractional part
+func configInt(value any) (int64, bool) {
+	if s, ok := value.(string); ok {
+		i, err := strconv.ParseInt(strings.TrimSpace(s), 0, 64)
+		return i, err == nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return int64(v.Uint()), v.Uint() <= math.MaxInt64
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		return int64(f), f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64
+	}
+	return 0, false
+}
+
+// joinConfigPath appends the key to the dotted path
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// configTypeError returns the error for a configuration value that can't be decoded into the target
+func configTypeError(value any, target reflect.Value, path string) error {
+	if path == "" {
+		return fmt.Errorf("cannot unmarshal %T into %s", value, target.Type())
+	}
+	return fmt.Errorf("config key '%s': cannot unmarshal %T into %s", path, value, target.Type())
+}
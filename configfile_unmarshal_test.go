@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type unmarshalTLS struct {
+	Enabled bool   `config:"enabled"`
+	Cert    string `config:"cert_file"`
+}
+
+type unmarshalBackend struct {
+	Host   string `config:"host"`
+	Weight int    `config:"weight"`
+}
+
+type unmarshalCommon struct {
+	Name string `config:"name"`
+}
+
+type unmarshalServer struct {
+	unmarshalCommon
+	Port     int                `config:"port"`
+	PoolSize uint8              `config:"pool_size"`
+	Ratio    float64            `config:"ratio"`
+	Timeout  time.Duration      `config:"timeout"`
+	Started  time.Time          `config:"started"`
+	Tags     []string           `config:"tags"`
+	TLS      *unmarshalTLS      `config:"tls"`
+	Backends []unmarshalBackend `config:"backends"`
+	Labels   map[string]string  `config:"labels"`
+	Extra    any                `config:"extra"`
+	Debug    bool
+	Skipped  string `config:"-"`
+}
+
+func TestConfigFileTyped_Unmarshal(t *testing.T) {
+	base, _ := newJSONConfigBase(t, `{
+		"server": {
+			"name": "api",
+			"port": 8080,
+			"pool_size": "16",
+			"ratio": 0.75,
+			"timeout": "30s",
+			"started": "2024-01-01T00:00:00Z",
+			"tags": ["a", "b"],
+			"tls": {"enabled": true, "cert_file": "/etc/cert"},
+			"backends": [{"host": "one", "weight": 1}, {"host": "two", "weight": 2}],
+			"labels": {"env": "prod"},
+			"extra": [1, "x"],
+			"DEBUG": true,
+			"Skipped": "no",
+			"unknown": "ignored"
+		}
+	}`)
+	cfg := NewTypedConfigFile(base)
+
+	server := unmarshalServer{Skipped: "kept"}
+	if err := cfg.Unmarshal("server", &server); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	want := unmarshalServer{
+		unmarshalCommon: unmarshalCommon{Name: "api"},
+		Port:            8080,
+		PoolSize:        16,
+		Ratio:           0.75,
+		Timeout:         30 * time.Second,
+		Started:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Tags:            []string{"a", "b"},
+		TLS:             &unmarshalTLS{Enabled: true, Cert: "/etc/cert"},
+		Backends:        []unmarshalBackend{{"one", 1}, {"two", 2}},
+		Labels:          map[string]string{"env": "prod"},
+		Extra:           []any{float64(1), "x"},
+		Debug:           true,
+		Skipped:         "kept",
+	}
+	if !reflect.DeepEqual(server, want) {
+		t.Errorf("Unmarshal() =\n%#v\nwant\n%#v", server, want)
+	}
+
+	// The whole configuration is decoded with an empty path
+	var root struct {
+		Server struct {
+			Port int `config:"port"`
+		} `config:"server"`
+	}
+	if err := cfg.Unmarshal("", &root); err != nil {
+		t.Fatalf("Unmarshal of the root failed: %v", err)
+	}
+	if root.Server.Port != 8080 {
+		t.Errorf("root.Server.Port = %d, want 8080", root.Server.Port)
+	}
+}
+
+func TestConfigFileTyped_UnmarshalErrors(t *testing.T) {
+	base, _ := newJSONConfigBase(t, `{
+		"server": {"port": "http", "ratio": 1.5, "size": 300, "name": 1, "tags": "a", "backends": [{"weight": "x"}]}
+	}`)
+	cfg := NewTypedConfigFile(base)
+
+	tests := []struct {
+		name    string
+		path    string
+		out     any
+		wantErr string
+	}{
+		{"not a pointer", "server", struct{}{}, "non-nil pointer"},
+		{"missing key", "nope", &struct{}{}, "'nope' not found"},
+		{"not an object", "server.port", &struct{}{}, "'server.port'"},
+		{"string into int", "server", &struct {
+			Port int `config:"port"`
+		}{}, "'server.port': cannot unmarshal string into int"},
+		{"fraction into int", "server", &struct {
+			Ratio int `config:"ratio"`
+		}{}, "'server.ratio'"},
+		{"overflow", "server", &struct {
+			Size uint8 `config:"size"`
+		}{}, "'server.size'"},
+		{"number into string", "server", &struct {
+			Name string `config:"name"`
+		}{}, "'server.name'"},
+		{"string into slice", "server", &struct {
+			Tags []string `config:"tags"`
+		}{}, "'server.tags'"},
+		{"nested", "server", &struct {
+			Backends []struct {
+				Weight int `config:"weight"`
+			} `config:"backends"`
+		}{}, "'server.backends[0].weight'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cfg.Unmarshal(tt.path, tt.out)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Unmarshal() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// SchemaKey describes a key expected in the configuration
+type SchemaKey struct {
+	Kind     reflect.Kind // Kind of the value, e.g. reflect.Int or reflect.Map, reflect.Invalid accepts any kind
+	Required bool         // Whether the key must be present
+}
+
+// Schema maps the dotted paths of configuration keys, e.g. "server.port", to what's expected of them
+type Schema map[string]SchemaKey
+
+// Validate checks the configuration against the schema, returning an error listing every key that is required but
+// missing or has the wrong kind of value.
+//
+// Numbers are checked by what they hold rather than how the reader decoded them, so reflect.Int accepts any whole
+// number, including a JSON 8080 decoded as float64, and the float kinds accept any number. reflect.Slice and
+// reflect.Map accept any array and object.
+func (c *ConfigFileTypedWrapper) Validate(schema Schema) error {
+	paths := make([]string, 0, len(schema))
+	for path := range schema {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		key := schema[path]
+
+		value, ok := c.inner.GetValue(path)
+		if !ok || value == nil {
+			if key.Required {
+				errs = append(errs, fmt.Errorf("config key '%s' is required", path))
+			}
+			continue
+		}
+
+		if key.Kind != reflect.Invalid && !matchesKind(value, key.Kind) {
+			errs = append(errs, fmt.Errorf("config key '%s' must be %s, got %T", path, key.Kind, value))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// matchesKind returns true if the value is of the kind, numbers match the kinds able to hold them so unsigned kinds
+// don't match negative numbers
+func matchesKind(value any, kind reflect.Kind) bool {
+	v := reflect.ValueOf(value)
+	switch kind {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+			reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+			return true
+		case reflect.Float32, reflect.Float64:
+			return v.Float() == math.Trunc(v.Float())
+		}
+		return false
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+			return v.Int() >= 0
+		case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+			return true
+		case reflect.Float32, reflect.Float64:
+			return v.Float() >= 0 && v.Float() == math.Trunc(v.Float())
+		}
+		return false
+	case reflect.Float32, reflect.Float64:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+			reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8,
+			reflect.Float32, reflect.Float64:
+			return true
+		}
+		return false
+	}
+	return v.Kind() == kind
+}
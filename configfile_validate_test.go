@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigFileTyped_Validate(t *testing.T) {
+	base, _ := newJSONConfigBase(t, `{
+		"server": {"port": 8080, "host": "localhost", "ratio": 0.5, "tags": ["a"], "tls": {}},
+		"debug": true
+	}`)
+	cfg := NewTypedConfigFile(base)
+
+	schema := Schema{
+		"server.port":  {Kind: reflect.Int, Required: true},
+		"server.host":  {Kind: reflect.String, Required: true},
+		"server.ratio": {Kind: reflect.Float64},
+		"server.tags":  {Kind: reflect.Slice},
+		"server.tls":   {Kind: reflect.Map},
+		"server":       {Required: true},
+		"debug":        {Kind: reflect.Bool},
+		"log.level":    {Kind: reflect.String},
+	}
+	if err := cfg.Validate(schema); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+
+	schema = Schema{
+		"server.port":  {Kind: reflect.String, Required: true},
+		"server.ratio": {Kind: reflect.Int},
+		"server.name":  {Kind: reflect.String, Required: true},
+		"debug":        {Kind: reflect.Bool},
+	}
+	err := cfg.Validate(schema)
+	if err == nil {
+		t.Fatal("Validate() error = nil, want errors")
+	}
+
+	want := []string{
+		"config key 'server.name' is required",
+		"config key 'server.port' must be string, got float64",
+		"config key 'server.ratio' must be int, got float64",
+	}
+	if got := strings.Split(err.Error(), "\n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() errors =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestMatchesKind(t *testing.T) {
+	tests := []struct {
+		value any
+		kind  reflect.Kind
+		want  bool
+	}{
+		{int64(8080), reflect.Int, true},
+		{8080, reflect.Uint16, true},
+		{-1, reflect.Uint16, false},
+		{int64(-1), reflect.Uint, false},
+		{float64(-1), reflect.Uint, false},
+		{float64(0), reflect.Uint, true},
+		{-1, reflect.Int, true},
+		{float64(8080), reflect.Int, true},
+		{8080.5, reflect.Int, false},
+		{int64(1), reflect.Float64, true},
+		{"8080", reflect.Int, false},
+		{"x", reflect.String, true},
+		{[]map[string]any{}, reflect.Slice, true},
+		{map[string]any{}, reflect.Map, true},
+		{true, reflect.String, false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesKind(tt.value, tt.kind); got != tt.want {
+			t.Errorf("matchesKind(%#v, %s) = %v, want %v", tt.value, tt.kind, got, tt.want)
+		}
+	}
+}
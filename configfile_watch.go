@@ -2,7 +2,12 @@
 
 package cli
 
-import "github.com/fsnotify/fsnotify"
+import (
+	"os"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
 
 func (c *ConfigFileBase) OnChange(handler ConfigFileChangeHandler) error {
 	// Ensure the config is loaded
@@ -13,35 +18,102 @@ func (c *ConfigFileBase) OnChange(handler ConfigFileChangeHandler) error {
 	// Remember the change handler
 	c.changeHandler = handler
 
+	return c.watch()
+}
+
+func (c *ConfigFileBase) OnChangeKeys(handler ConfigFileKeysHandler) error {
+	// Ensure the config is loaded
+	if err := c.LoadData(); err != nil {
+		return err
+	}
+
+	// Remember the change handler
+	c.keysHandler = handler
+
+	return c.watch()
+}
+
+// watch starts watching the configuration file, reloading it and calling the change handlers when it's written
+func (c *ConfigFileBase) watch() error {
+	// In-memory configuration never changes
+	if c.Content != nil {
+		return nil
+	}
+
 	// If no watcher then set it up
 	if c.watcher == nil {
 		c.watcher, _ = fsnotify.NewWatcher()
+		go c.watchEvents(c.watcher.Events, c.watcher.Errors)
+		c.watcher.Add(c.fileUsed)
+	}
+
+	return nil
+}
 
-		go func() {
-			for {
-				select {
-				case event, ok := <-c.watcher.Events:
-					if !ok {
-						return
-					}
-
-					if event.Op&fsnotify.Write == fsnotify.Write {
-						// Reload the config file & call the handler
-						c.isLoaded = false
-						c.LoadData()
-						c.changeHandler()
-					}
-
-				case _, ok := <-c.watcher.Errors:
-					if !ok {
-						return
-					}
+// watchEvents reloads the configuration once the file has gone ReloadDelay without being written, editors often write
+// a file more than once when saving it, e.g. truncating it and then writing the content
+func (c *ConfigFileBase) watchEvents(events <-chan fsnotify.Event, errors <-chan error) {
+	delay := c.ReloadDelay
+	if delay <= 0 {
+		delay = DefaultReloadDelay
+	}
+
+	var timer *time.Timer
+	var reload <-chan time.Time
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if event.Op&fsnotify.Write == fsnotify.Write {
+				// Restart the delay on each write
+				if timer == nil {
+					timer = time.NewTimer(delay)
+				} else {
+					timer.Reset(delay)
 				}
+				reload = timer.C
 			}
-		}()
 
-		c.watcher.Add(c.fileUsed)
+		case <-reload:
+			reload = nil
+			c.reload()
+
+		case _, ok := <-errors:
+			if !ok {
+				return
+			}
+		}
 	}
+}
 
-	return nil
+// reload reloads the configuration file and calls the change handlers
+func (c *ConfigFileBase) reload() {
+	// Decode the file into new data so removed keys are dropped, keeping the previous data if the file can't be read,
+	// e.g. it's part way through being written
+	contentBytes, err := os.ReadFile(c.fileUsed)
+	if err != nil {
+		return
+	}
+	data := make(map[string]any)
+	if err := c.Unmarshal(contentBytes, &data); err != nil {
+		return
+	}
+
+	// Swap in the new data in one step so readers see either the old or the new data
+	c.mutex.Lock()
+	old := c.data
+	c.data = data
+	c.mutex.Unlock()
+
+	if c.changeHandler != nil {
+		c.changeHandler()
+	}
+	if c.keysHandler != nil {
+		if changed := changedConfigKeys(old, data); len(changed) > 0 {
+			c.keysHandler(changed)
+		}
+	}
 }
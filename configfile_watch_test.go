@@ -0,0 +1,133 @@
+//go:build cli_watch
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestConfigFileBase_OnChangeKeys(t *testing.T) {
+	cfg, path := newJSONConfigBase(t, `{"server":{"port":8080,"host":"localhost"},"log":"info"}`)
+
+	changes := make(chan []string, 1)
+	if err := cfg.OnChangeKeys(func(changed []string) { changes <- changed }); err != nil {
+		t.Fatalf("OnChangeKeys failed: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"server":{"port":9090,"host":"localhost"},"debug":true}`), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	select {
+	case changed := <-changes:
+		want := []string{"debug", "log", "server.port"}
+		if !reflect.DeepEqual(changed, want) {
+			t.Errorf("changed = %v, want %v", changed, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the change handler")
+	}
+
+	// Removed keys are dropped when the file is reloaded
+	if _, ok := cfg.GetValue("log"); ok {
+		t.Error("removed key log still has a value")
+	}
+}
+
+func TestConfigFileBase_ReloadDebounced(t *testing.T) {
+	cfg, path := newJSONConfigBase(t, `{"port":8080}`)
+	cfg.ReloadDelay = 50 * time.Millisecond
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("LoadData failed: %v", err)
+	}
+
+	calls := make(chan struct{}, 10)
+	cfg.changeHandler = func() { calls <- struct{}{} }
+
+	events := make(chan fsnotify.Event)
+	errors := make(chan error)
+	defer close(events)
+	go cfg.watchEvents(events, errors)
+
+	// An editor truncating the file and then writing it gives two writes in quick succession
+	if err := os.WriteFile(path, []byte(`{"port":9090}`), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	events <- fsnotify.Event{Name: path, Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: path, Op: fsnotify.Write}
+
+	select {
+	case <-calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the change handler")
+	}
+
+	// No second call once the delay has passed again
+	select {
+	case <-calls:
+		t.Error("change handler called more than once")
+	case <-time.After(3 * cfg.ReloadDelay):
+	}
+
+	if v, _ := cfg.GetValue("port"); v != float64(9090) {
+		t.Errorf("port = %v, want 9090", v)
+	}
+}
+
+func TestConfigFileBase_ReloadSwapsData(t *testing.T) {
+	cfg, path := newJSONConfigBase(t, `{"port":8080}`)
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("LoadData failed: %v", err)
+	}
+
+	// Readers see the old or the new data while the file is reloaded, never an empty configuration
+	done := make(chan struct{})
+	missing := make(chan struct{}, 1)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if _, ok := cfg.GetValue("port"); !ok {
+				select {
+				case missing <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	for i := range 100 {
+		if err := os.WriteFile(path, []byte(fmt.Sprintf(`{"port":%d}`, 9000+i)), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		cfg.reload()
+	}
+	close(done)
+
+	select {
+	case <-missing:
+		t.Error("port was missing while the file was reloaded")
+	default:
+	}
+	if v, _ := cfg.GetValue("port"); v != float64(9099) {
+		t.Errorf("port = %v, want 9099", v)
+	}
+
+	// A file that can't be decoded keeps the previous data
+	if err := os.WriteFile(path, []byte(`{"port":`), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg.reload()
+	if v, _ := cfg.GetValue("port"); v != float64(9099) {
+		t.Errorf("port = %v after a bad reload, want 9099", v)
+	}
+}
@@ -13,17 +13,41 @@ import (
 
 var bracedVarRe = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
 
+// LoadOptions controls how .env files are loaded.
+type LoadOptions struct {
+	Override bool // Whether values from the files replace variables already set in the environment
+}
+
 // Load loads .env files. If no filenames are provided, it defaults to ".env".
 // Each file is loaded in turn, with later files overriding values from earlier ones.
 // It parses key=value pairs, expands variables, and sets them as environment variables.
 // Variables can reference other environment variables using ${VAR} or $VAR syntax.
+// Values from the files replace variables already set in the environment, use LoadWithOptions to keep them.
 func Load(filenames ...string) error {
+	return LoadWithOptions(LoadOptions{Override: true}, filenames...)
+}
+
+// LoadWithOptions loads .env files as Load does using the given options.
+// With Override false variables already set in the environment before loading are left unchanged, as is usual for
+// dotenv, while values from later files still override those from earlier ones.
+func LoadWithOptions(opts LoadOptions, filenames ...string) error {
 	if len(filenames) == 0 {
 		filenames = []string{".env"}
 	}
 
+	// Remember the variables set before loading so the files can't change them
+	var existing map[string]bool
+	if !opts.Override {
+		existing = make(map[string]bool)
+		for _, entry := range os.Environ() {
+			if key, _, ok := strings.Cut(entry, "="); ok {
+				existing[key] = true
+			}
+		}
+	}
+
 	for _, filename := range filenames {
-		if err := loadFile(filename); err != nil {
+		if err := loadFile(filename, existing); err != nil {
 			return err
 		}
 	}
@@ -32,8 +56,8 @@ func Load(filenames ...string) error {
 }
 
 // loadFile loads a specific .env file path.
-// It parses key=value pairs, expands variables, and sets them as environment variables.
-func loadFile(filePath string) error {
+// It parses key=value pairs, expands variables, and sets them as environment variables, except those in keep.
+func loadFile(filePath string, keep map[string]bool) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
@@ -59,6 +83,11 @@ func loadFile(filePath string) error {
 			return fmt.Errorf("error on line %d: %w", lineNum, err)
 		}
 
+		// Variables already set in the environment win over the file
+		if keep[key] {
+			continue
+		}
+
 		// Expand variables in the value
 		value = expandVariables(value)
 
@@ -271,6 +300,12 @@ func unescapeString(s string) string {
 	return result.String()
 }
 
+// Expand expands environment variable references in the form ${VAR} or $VAR in value, references to variables that
+// are unset or empty are left as they are.
+func Expand(value string) string {
+	return expandVariables(value)
+}
+
 // expandVariables expands variable references in the form ${VAR} or $VAR.
 func expandVariables(value string) string {
 	// First, handle ${VAR} syntax
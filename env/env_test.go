@@ -110,6 +110,69 @@ KEY3=value3
 	}
 }
 
+func TestLoad_OverridesExisting(t *testing.T) {
+	filePath := createTempEnvFile(t, "EXISTING_KEY=from_file\n")
+	t.Setenv("EXISTING_KEY", "from_env")
+
+	if err := Load(filePath); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := os.Getenv("EXISTING_KEY"); got != "from_file" {
+		t.Errorf("EXISTING_KEY = %q, want %q", got, "from_file")
+	}
+}
+
+func TestLoadWithOptions_NoOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	file1 := filepath.Join(tmpDir, ".env1")
+	file2 := filepath.Join(tmpDir, ".env2")
+
+	content1 := `EXISTING_KEY=from_file1
+NEW_KEY=from_file1
+EXPANDED=${EXISTING_KEY}
+`
+	content2 := `EXISTING_KEY=from_file2
+NEW_KEY=from_file2
+`
+	if err := os.WriteFile(file1, []byte(content1), 0644); err != nil {
+		t.Fatalf("Failed to create file1: %v", err)
+	}
+	if err := os.WriteFile(file2, []byte(content2), 0644); err != nil {
+		t.Fatalf("Failed to create file2: %v", err)
+	}
+	t.Setenv("EXISTING_KEY", "from_env")
+	defer clearEnvVars("NEW_KEY", "EXPANDED")
+
+	if err := LoadWithOptions(LoadOptions{Override: false}, file1, file2); err != nil {
+		t.Fatalf("LoadWithOptions failed: %v", err)
+	}
+
+	// The environment wins over the files, later files still override earlier ones
+	if got := os.Getenv("EXISTING_KEY"); got != "from_env" {
+		t.Errorf("EXISTING_KEY = %q, want %q", got, "from_env")
+	}
+	if got := os.Getenv("NEW_KEY"); got != "from_file2" {
+		t.Errorf("NEW_KEY = %q, want %q", got, "from_file2")
+	}
+	if got := os.Getenv("EXPANDED"); got != "from_env" {
+		t.Errorf("EXPANDED = %q, want %q", got, "from_env")
+	}
+}
+
+func TestLoadWithOptions_KeepsEmptyExisting(t *testing.T) {
+	filePath := createTempEnvFile(t, "EMPTY_KEY=from_file\n")
+	t.Setenv("EMPTY_KEY", "")
+
+	if err := LoadWithOptions(LoadOptions{}, filePath); err != nil {
+		t.Fatalf("LoadWithOptions failed: %v", err)
+	}
+
+	if got, ok := os.LookupEnv("EMPTY_KEY"); !ok || got != "" {
+		t.Errorf("EMPTY_KEY = %q, %v, want it kept empty", got, ok)
+	}
+}
+
 func TestLoad_SpacesAroundEquals(t *testing.T) {
 	content := `KEY1 = value1
 KEY2= value2
@@ -1120,3 +1183,24 @@ func TestLoadIntegration_Complex(t *testing.T) {
 		t.Errorf("API_ENDPOINT = %q, want %q", got, "https://api.example.com/v1/users")
 	}
 }
+
+func TestExpand(t *testing.T) {
+	t.Setenv("EXPAND_HOST", "db.local")
+	t.Setenv("EXPAND_PORT", "5432")
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"${EXPAND_HOST}:$EXPAND_PORT", "db.local:5432"},
+		{"postgres://${EXPAND_HOST}/app", "postgres://db.local/app"},
+		{"${EXPAND_UNSET} $EXPAND_UNSET", "${EXPAND_UNSET} $EXPAND_UNSET"},
+		{"no variables", "no variables"},
+	}
+
+	for _, tt := range tests {
+		if got := Expand(tt.value); got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
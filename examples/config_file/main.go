@@ -48,7 +48,7 @@ func main() {
 	if apiKeys != nil {
 		fmt.Printf("Found %d API keys:\n", len(apiKeys))
 		for i, key := range apiKeys {
-			fmt.Printf("  %d: %s\n", i+1, maskApiKey(key))
+			fmt.Printf("  %d: %s\n", i+1, cli.MaskSecret(key))
 		}
 	}
 
@@ -281,17 +281,6 @@ func demonstrateObjectModification(config cli.ConfigFileTyped) {
 	}
 }
 
-// Helper functions to mask sensitive information
-func maskApiKey(key string) string {
-	if key == "" {
-		return "[empty]"
-	}
-	if len(key) <= 8 {
-		return "[***]"
-	}
-	return key[:4] + "***" + key[len(key)-4:]
-}
-
 // demonstrateErrorHandling shows how to handle missing or invalid data gracefully
 func demonstrateErrorHandling(config cli.ConfigFileTyped) {
 	// Try to access a non-existent slice
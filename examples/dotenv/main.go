@@ -3,21 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 
 	"github.com/paularlott/cli"
-	"github.com/paularlott/cli/env"
 )
 
 func main() {
-	// Load .env file BEFORE executing the command
-	// This sets environment variables that can be accessed by the CLI
-	if err := env.Load(); err != nil {
-		// .env file is optional - log but don't fail if it doesn't exist
-		log.Printf("Note: .env file not found or could not be loaded: %v", err)
-	}
-
 	// Define flags that can read from environment variables
 	var (
 		appName   string
@@ -33,6 +24,7 @@ func main() {
 		Version:     "1.0.0",
 		Usage:       "Example CLI with .env file support",
 		Description: "This example demonstrates how to use the env package to load .env files for your CLI application.",
+		DotEnvFiles: []string{".env"}, // Loaded before the flags are processed, a missing file is ignored
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:         "name",
@@ -66,6 +58,7 @@ func main() {
 				Usage:        "API key for external services",
 				EnvVars:      []string{"API_KEY"},
 				Required:     true,
+				Secret:       true,
 				AssignTo:     &apiKey,
 			},
 			&cli.StringFlag{
@@ -82,7 +75,7 @@ func main() {
 			fmt.Printf("Port: %d\n", appPort)
 			fmt.Printf("Debug Mode: %t\n", appDebug)
 			fmt.Printf("Database URL: %s\n", dbURL)
-			fmt.Printf("API Key: %s\n", maskAPIKey(apiKey))
+			fmt.Printf("API Key: %s\n", cli.MaskSecret(apiKey))
 			fmt.Printf("Log Level: %s\n", logLevel)
 
 			// Show how to access environment variables directly
@@ -110,11 +103,3 @@ func main() {
 
 	os.Exit(0)
 }
-
-// maskAPIKey masks all but the first and last 4 characters of an API key
-func maskAPIKey(key string) string {
-	if len(key) <= 8 {
-		return "***"
-	}
-	return key[:4] + "..." + key[len(key)-4:]
-}
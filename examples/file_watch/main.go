@@ -61,18 +61,23 @@ This example must be built with -tags cli_watch to enable file watch support.`,
 			},
 		},
 		MaxArgs: cli.NoArgs,
+		OnFlagChanged: func(name string, oldValue, newValue any) {
+			fmt.Printf("Flag %s changed from %v to %v\n", name, oldValue, newValue)
+		},
 		Run: func(ctx context.Context, cmd *cli.Command) error {
 			fmt.Println("Name:", cmd.GetStringSlice("name"))
 			fmt.Println("Name Global:", globalName)
 
 			// Watch for changes in the config file and reload the flags
-			cmd.ConfigFile.OnChange(func() {
-				fmt.Println("Config file changed:", cmd.ConfigFile.FileUsed())
-				cmd.ReloadFlags()
-
-				fmt.Println("Name:", cmd.GetStringSlice("name"))
-				fmt.Println("Name Global:", globalName)
-			})
+			if notifier, ok := cmd.ConfigFile.(cli.ConfigFileKeysNotifier); ok {
+				notifier.OnChangeKeys(func(changed []string) {
+					fmt.Println("Config file changed:", cmd.ConfigFile.FileUsed(), changed)
+					cmd.ReloadFlags()
+
+					fmt.Println("Name:", cmd.GetStringSlice("name"))
+					fmt.Println("Name Global:", globalName)
+				})
+			}
 
 			fmt.Println("\nWatching for changes, press ctrl+c to quit...")
 
@@ -1,7 +1,11 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"net"
+	"net/url"
 	"os"
 	"reflect"
 	"strconv"
@@ -12,38 +16,70 @@ type Flag interface {
 	getName() string
 	getAliases() []string
 	isGlobal() bool
+	setGlobal()
 	register(longFlags, shortFlags map[string]Flag)
 	parseString(value string, hasValue bool, parsedFlags map[string]interface{}) error
-	setFromEnvVar(parsedFlags map[string]interface{})
+	setFromEnvVar(parsedFlags map[string]interface{}, envVars []string) error
 	setFromDefault(parsedFlags map[string]interface{})
+	setDefault(value any, parsedFlags map[string]interface{}) error // Sets the flag to a default from a command's FlagDefaults
 	configPaths() []string
 	isSlice() bool
 	isRequired() bool
 	isHidden() bool
-	flagDefinition() string      // Returns flag name and aliases with type (e.g., --port int, -p int)
-	getUsage() string            // Returns description of the flag (e.g., "Port to run the server on")
-	defaultValueText() string    // Returns formatted default value (e.g., "8080")
-	typeText() string            // Returns type information (e.g., "int", "string", etc.)
-	validateFlag(*Command) error // Runs optional user validation of the flag
-	getEnvVars() []string        // Returns environment variables associated with the flag
-	getConfigPaths() []string    // Returns configuration paths associated with the flag
+	isSecret() bool
+	forbidsArgs() bool
+	isBuiltin() bool
+	takesValue() bool                                // Returns false for flags given without a value, e.g. bool and count flags
+	flagDefinition() string                          // Returns flag name and aliases with type (e.g., --port int, -p int)
+	getUsage() string                                // Returns description of the flag (e.g., "Port to run the server on")
+	defaultValueText() string                        // Returns formatted default value (e.g., "8080")
+	defaultOverrideText(value any) string            // Returns a formatted default from a command's FlagDefaults
+	typeText() string                                // Returns type information (e.g., "int", "string", etc.)
+	validateFlag(*Command) error                     // Runs optional user validation of the flag
+	validateChoice(*Command) error                   // Checks a given value is one of the flag's choices
+	validateRequires(*Command) error                 // Checks the flags required by a given flag are also given
+	getChoices() []string                            // Returns the values the flag accepts, if restricted
+	complete(c *Command, toComplete string) []string // Returns the shell completions for the value of the flag
+	completeFiles() (bool, []string)                 // Returns whether the value is completed as a file path and the extensions to limit it to
+	getEnvVars() []string                            // Returns environment variables associated with the flag
+	getConfigPaths() []string                        // Returns configuration paths associated with the flag
+	valueType() reflect.Type                         // Returns the Go type of the flag value
+	makeUnique(parsedFlags map[string]interface{})   // Removes duplicate values from slice flags with Unique set
+	notifySet(parsedFlags map[string]interface{})    // Passes the final value of the flag to OnSet
 }
 
 type FlagTyped[T any] struct {
-	Name         string               // Name of the flag, e.g. "server"
-	Usage        string               // Short description of the flag, e.g. "The server to connect to"
-	Aliases      []string             // Aliases for the flag, e.g. "s" for "server"
-	ConfigPath   []string             // Configuration paths for the flag, e.g. "cli.server"
-	DefaultValue T                    // Default value for the flag, e.g. "localhost" for server
-	DefaultText  string               // Text to show in usage as the default value, e.g. "localhost"
-	AssignTo     *T                   // Optional pointer to the variable where the value should be stored
-	EnvVars      []string             // Environment variables that can be used to set this flag, first found will be used
-	Required     bool                 // Whether this flag is required
-	Global       bool                 // Whether this flag is global, i.e. available in all commands
-	HideDefault  bool                 // Whether to hide the default value in usage output
-	HideType     bool                 // Whether to hide the type in usage output
-	Hidden       bool                 // Whether this flag is hidden from help and command completions
-	ValidateFlag func(*Command) error // Validation function for the flag
+	Name                   string                                       // Name of the flag, e.g. "server"
+	Usage                  string                                       // Short description of the flag, e.g. "The server to connect to"
+	Aliases                []string                                     // Aliases for the flag, e.g. "s" for "server"
+	ConfigPath             []string                                     // Configuration paths for the flag, e.g. "cli.server"
+	DefaultValue           T                                            // Default value for the flag, e.g. "localhost" for server
+	DefaultFunc            func() T                                     // Function computing the default value when the flag isn't set, used in place of DefaultValue, e.g. os.Hostname
+	DefaultText            string                                       // Text to show in usage as the default value, e.g. "localhost"
+	AssignTo               *T                                           // Optional pointer to the variable where the value should be stored
+	OnSet                  func(value T)                                // Optional function called with the final value once the flags are parsed and checked, alongside AssignTo
+	EnvVars                []string                                     // Environment variables that can be used to set this flag, first found will be used
+	Required               bool                                         // Whether this flag is required
+	Global                 bool                                         // Whether this flag is global, i.e. available in all commands
+	HideDefault            bool                                         // Whether to hide the default value in usage output
+	HideType               bool                                         // Whether to hide the type in usage output
+	Hidden                 bool                                         // Whether this flag is hidden from help and command completions
+	Secret                 bool                                         // Whether this flag holds a secret, e.g. an API key, its value is masked when displayed
+	Unique                 bool                                         // Whether to remove duplicate values from a slice flag, keeping the first occurrence
+	Choices                []string                                     // Values the flag accepts, e.g. "debug", "info", any value is accepted if empty
+	ChoicesCaseInsensitive bool                                         // Whether to ignore case when matching values against Choices
+	ForbidArgs             bool                                         // Whether positional arguments are rejected when this flag is given, e.g. for --list
+	Count                  bool                                         // Whether an int flag counts how many times it's given, e.g. -vvv for 3, rather than taking a value
+	RequireAbsolute        bool                                         // Whether a URL flag rejects URLs without a scheme and host, e.g. "/path"
+	Requires               []string                                     // Flags that must also be set when this flag is set, e.g. "tls-key" for "tls-cert"
+	AllowFileValue         bool                                         // Whether a value of @path reads the value from the file, e.g. --token @/run/secrets/token, @@ escapes a literal @
+	Parse                  func(string) (any, error)                    // Custom parser for the flag value, used in place of the built-in parsing, called for each value of a slice flag
+	ValidateFlag           func(*Command) error                         // Validation function for the flag
+	CompleteFunc           func(c *Command, toComplete string) []string // Optional function returning the shell completions for the value, e.g. project names from an API
+	CompleteFiles          bool                                         // Whether the value is completed by the shell as a file path
+	FileExtensions         []string                                     // Extensions the file completion is limited to, e.g. "yaml", "yml", directories are always offered
+
+	builtin bool // Whether the flag was added by the library, e.g. --help
 }
 
 type StringFlag = FlagTyped[string]
@@ -75,6 +111,16 @@ type Uint64SliceFlag = FlagTyped[[]uint64]
 type Float32SliceFlag = FlagTyped[[]float32]
 type Float64SliceFlag = FlagTyped[[]float64]
 
+type StringMapFlag = FlagTyped[map[string]string]
+
+type IPFlag = FlagTyped[net.IP]
+type IPNetFlag = FlagTyped[*net.IPNet]
+type IPSliceFlag = FlagTyped[[]net.IP]
+type IPNetSliceFlag = FlagTyped[[]*net.IPNet]
+
+type URLFlag = FlagTyped[*url.URL]
+type URLSliceFlag = FlagTyped[[]*url.URL]
+
 func (f *FlagTyped[T]) getName() string {
 	return f.Name
 }
@@ -87,12 +133,20 @@ func (f *FlagTyped[T]) isGlobal() bool {
 	return f.Global
 }
 
+func (f *FlagTyped[T]) setGlobal() {
+	f.Global = true
+}
+
 func (f *FlagTyped[T]) configPaths() []string {
 	return f.ConfigPath
 }
 
 func (f *FlagTyped[T]) isSlice() bool {
-	return reflect.TypeOf(f.DefaultValue).Kind() == reflect.Slice
+	return isSliceType(f.valueType())
+}
+
+func (f *FlagTyped[T]) isMap() bool {
+	return reflect.TypeOf((*T)(nil)).Elem().Kind() == reflect.Map
 }
 
 func (f *FlagTyped[T]) isRequired() bool {
@@ -103,6 +157,28 @@ func (f *FlagTyped[T]) isHidden() bool {
 	return f.Hidden
 }
 
+func (f *FlagTyped[T]) isSecret() bool {
+	return f.Secret
+}
+
+func (f *FlagTyped[T]) forbidsArgs() bool {
+	return f.ForbidArgs
+}
+
+func (f *FlagTyped[T]) isBuiltin() bool {
+	return f.builtin
+}
+
+func (f *FlagTyped[T]) takesValue() bool {
+	switch any(f).(type) {
+	case *BoolFlag:
+		return false
+	case *IntFlag:
+		return !f.Count
+	}
+	return true
+}
+
 func (f *FlagTyped[T]) getEnvVars() []string {
 	return f.EnvVars
 }
@@ -111,6 +187,27 @@ func (f *FlagTyped[T]) getConfigPaths() []string {
 	return f.ConfigPath
 }
 
+func (f *FlagTyped[T]) getChoices() []string {
+	return f.Choices
+}
+
+// complete returns the shell completions for the value of the flag, its Choices followed by the values from CompleteFunc
+func (f *FlagTyped[T]) complete(c *Command, toComplete string) []string {
+	values := append([]string{}, f.Choices...)
+	if f.CompleteFunc != nil {
+		values = append(values, f.CompleteFunc(c, toComplete)...)
+	}
+	return values
+}
+
+func (f *FlagTyped[T]) completeFiles() (bool, []string) {
+	return f.CompleteFiles, f.FileExtensions
+}
+
+func (f *FlagTyped[T]) valueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
 func (f *FlagTyped[T]) register(longFlags, shortFlags map[string]Flag) {
 	longFlags[f.Name] = f
 	for _, alias := range f.Aliases {
@@ -122,34 +219,124 @@ func (f *FlagTyped[T]) register(longFlags, shortFlags map[string]Flag) {
 	}
 }
 
-func (f *FlagTyped[T]) setFromEnvVar(parsedFlags map[string]interface{}) {
-	if len(f.EnvVars) > 0 {
-		for _, envVar := range f.EnvVars {
-			if value, ok := os.LookupEnv(envVar); ok {
-				// If slice then split by comma
-				if f.isSlice() {
-					values := strings.Split(value, ",")
-					for _, v := range values {
-						v = strings.TrimSpace(v)
-						f.parseString(v, true, parsedFlags)
-					}
-				} else {
-					f.parseString(value, true, parsedFlags)
+func (f *FlagTyped[T]) setFromEnvVar(parsedFlags map[string]interface{}, envVars []string) error {
+	var errs []error
+	for _, envVar := range envVars {
+		value, ok := os.LookupEnv(envVar)
+		if !ok {
+			continue
+		}
+
+		// Parse into a separate map so a value that fails part way through a slice isn't kept
+		parsed := make(map[string]interface{})
+		var err error
+		if f.isSlice() || f.isMap() {
+			// If slice or map then split by comma
+			for _, v := range strings.Split(value, ",") {
+				if err = f.parseString(strings.TrimSpace(v), true, parsed); err != nil {
+					break
 				}
-				return // Use the first found environment variable
 			}
+		} else {
+			err = f.parseString(value, true, parsed)
+		}
+
+		// Use the first environment variable with a valid value
+		if err == nil {
+			parsedFlags[f.Name] = parsed[f.Name]
+			return nil
 		}
+		errs = append(errs, fmt.Errorf("environment variable %s: %w", envVar, err))
 	}
+
+	return errors.Join(errs...)
 }
 
 func (f *FlagTyped[T]) setFromDefault(parsedFlags map[string]interface{}) {
-	zero := reflect.Zero(reflect.TypeOf(f.DefaultValue)).Interface()
-	if !reflect.DeepEqual(f.DefaultValue, zero) {
-		parsedFlags[f.Name] = f.DefaultValue
+	value := f.DefaultValue
+	if f.DefaultFunc != nil {
+		value = f.DefaultFunc()
+	}
+
+	zero := reflect.Zero(f.valueType()).Interface()
+	if !reflect.DeepEqual(value, zero) {
+		parsedFlags[f.Name] = value
 		if f.AssignTo != nil {
-			*f.AssignTo = f.DefaultValue
+			*f.AssignTo = value
+		}
+	}
+}
+
+// setDefault sets the flag to a default given by a command in place of the flag's own default,
+// the value is either of the flag's type or a string that's parsed as if given on the command line
+func (f *FlagTyped[T]) setDefault(value any, parsedFlags map[string]interface{}) error {
+	v, ok := value.(T)
+	if !ok {
+		s, isString := value.(string)
+		if !isString {
+			return fmt.Errorf("default for flag --%s must be %s, got %T", f.Name, f.valueType(), value)
+		}
+		return f.parseString(s, true, parsedFlags)
+	}
+
+	parsedFlags[f.Name] = v
+	if f.AssignTo != nil {
+		*f.AssignTo = v
+	}
+	return nil
+}
+
+// notifySet passes the final value of the flag to OnSet, called once the flag sources have been applied and checked
+func (f *FlagTyped[T]) notifySet(parsedFlags map[string]interface{}) {
+	if f.OnSet == nil {
+		return
+	}
+	if value, ok := parsedFlags[f.Name].(T); ok {
+		f.OnSet(value)
+	}
+}
+
+// uintParseError describes why value couldn't be parsed as an unsigned integer, distinguishing overflow from negative or non-numeric input
+func uintParseError(subject, value string, bitSize int, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("%s must be at most %d, got %s", subject, uint64(math.MaxUint64)>>(64-bitSize), value)
+	}
+	return fmt.Errorf("%s must be a non-negative integer, got %s", subject, value)
+}
+
+func (f *FlagTyped[T]) makeUnique(parsedFlags map[string]interface{}) {
+	if !f.Unique || !f.isSlice() {
+		return
+	}
+
+	value, ok := parsedFlags[f.Name].(T)
+	if !ok {
+		return
+	}
+
+	values := reflect.ValueOf(value)
+	unique := reflect.MakeSlice(values.Type(), 0, values.Len())
+	seen := make(map[interface{}]bool, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		v := values.Index(i)
+
+		// Values such as IP addresses can't be map keys, or are pointers, so compare them by their text
+		key := v.Interface()
+		if !v.Type().Comparable() || v.Kind() == reflect.Pointer {
+			key = fmt.Sprint(key)
+		}
+
+		if !seen[key] {
+			seen[key] = true
+			unique = reflect.Append(unique, v)
 		}
 	}
+
+	result := unique.Interface().(T)
+	parsedFlags[f.Name] = result
+	if f.AssignTo != nil {
+		*f.AssignTo = result
+	}
 }
 
 func (f *FlagTyped[T]) validateFlag(c *Command) error {
@@ -159,7 +346,89 @@ func (f *FlagTyped[T]) validateFlag(c *Command) error {
 	return nil
 }
 
+func (f *FlagTyped[T]) validateChoice(c *Command) error {
+	if len(f.Choices) == 0 || !c.givenFlags[f.Name] {
+		return nil
+	}
+
+	value, ok := c.parsedFlags[f.Name].(T)
+	if !ok {
+		return nil
+	}
+
+	// Slice flags have each of their values checked
+	values := reflect.ValueOf(value)
+	if !f.isSlice() {
+		values = reflect.ValueOf([]T{value})
+	}
+
+	matched := make([]string, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		v := fmt.Sprint(values.Index(i).Interface())
+		choice, ok := f.matchChoice(v)
+		if !ok {
+			return fmt.Errorf("invalid value %q for --%s, must be one of: %s", v, f.Name, strings.Join(f.Choices, ", "))
+		}
+		matched[i] = choice
+	}
+
+	// Store string values as written in Choices rather than as given, e.g. "debug" for --log-level DEBUG
+	if f.ChoicesCaseInsensitive {
+		var result any
+		switch any(value).(type) {
+		case string:
+			result = matched[0]
+		case []string:
+			result = matched
+		}
+		if v, ok := result.(T); ok {
+			c.parsedFlags[f.Name] = v
+			if f.AssignTo != nil {
+				*f.AssignTo = v
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *FlagTyped[T]) validateRequires(c *Command) error {
+	if !c.givenFlags[f.Name] {
+		return nil
+	}
+
+	for _, name := range f.Requires {
+		if !c.givenFlags[c.resolveFlagKey(name)] {
+			return fmt.Errorf("--%s requires --%s to also be set", f.Name, name)
+		}
+	}
+
+	return nil
+}
+
+// matchChoice returns the choice the value matches, ignoring case if ChoicesCaseInsensitive is set
+func (f *FlagTyped[T]) matchChoice(value string) (string, bool) {
+	for _, choice := range f.Choices {
+		if choice == value || (f.ChoicesCaseInsensitive && strings.EqualFold(choice, value)) {
+			return choice, true
+		}
+	}
+	return "", false
+}
+
 func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[string]interface{}) error {
+	if f.AllowFileValue && (hasValue || f.takesValue()) {
+		var err error
+		if value, err = readFileValue(value); err != nil {
+			return fmt.Errorf("failed to read value for flag --%s: %w", f.Name, err)
+		}
+	}
+
+	// A custom parser replaces the built-in parsing, except for flags given without a value such as bool flags
+	if f.Parse != nil && (hasValue || f.takesValue()) {
+		return f.parseCustom(value, parsedFlags)
+	}
+
 	switch f := any(f).(type) {
 	case *StringFlag:
 		parsedFlags[f.Name] = value
@@ -168,17 +437,30 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *IntFlag:
-		intVal, err := strconv.Atoi(value)
+		// Count flags add one each time they're given, starting from the default value
+		if f.Count && !hasValue {
+			count, ok := parsedFlags[f.Name].(int)
+			if !ok {
+				count = f.DefaultValue
+			}
+			parsedFlags[f.Name] = count + 1
+			if f.AssignTo != nil {
+				*f.AssignTo = count + 1
+			}
+			break
+		}
+
+		intVal, err := strconv.ParseInt(value, 0, 0)
 		if err != nil {
 			return fmt.Errorf("invalid integer value for flag --%s: %s", f.Name, value)
 		}
-		parsedFlags[f.Name] = intVal
+		parsedFlags[f.Name] = int(intVal)
 		if f.AssignTo != nil {
-			*f.AssignTo = intVal
+			*f.AssignTo = int(intVal)
 		}
 
 	case *Int8Flag:
-		int8Val, err := strconv.ParseInt(value, 10, 8)
+		int8Val, err := strconv.ParseInt(value, 0, 8)
 		if err != nil {
 			return fmt.Errorf("invalid int8 value for flag --%s: %s", f.Name, value)
 		}
@@ -188,7 +470,7 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Int16Flag:
-		int16Val, err := strconv.ParseInt(value, 10, 16)
+		int16Val, err := strconv.ParseInt(value, 0, 16)
 		if err != nil {
 			return fmt.Errorf("invalid int16 value for flag --%s: %s", f.Name, value)
 		}
@@ -198,7 +480,7 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Int32Flag:
-		int32Val, err := strconv.ParseInt(value, 10, 32)
+		int32Val, err := strconv.ParseInt(value, 0, 32)
 		if err != nil {
 			return fmt.Errorf("invalid int32 value for flag --%s: %s", f.Name, value)
 		}
@@ -208,7 +490,7 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Int64Flag:
-		int64Val, err := strconv.ParseInt(value, 10, 64)
+		int64Val, err := strconv.ParseInt(value, 0, 64)
 		if err != nil {
 			return fmt.Errorf("invalid int64 value for flag --%s: %s", f.Name, value)
 		}
@@ -218,9 +500,9 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *UintFlag:
-		uintVal, err := strconv.ParseUint(value, 10, 64)
+		uintVal, err := strconv.ParseUint(value, 0, 64)
 		if err != nil {
-			return fmt.Errorf("invalid uint value for flag --%s: %s", f.Name, value)
+			return uintParseError("flag --"+f.Name, value, 64, err)
 		}
 		parsedFlags[f.Name] = uint(uintVal)
 		if f.AssignTo != nil {
@@ -228,9 +510,9 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Uint8Flag:
-		uint8Val, err := strconv.ParseUint(value, 10, 8)
+		uint8Val, err := strconv.ParseUint(value, 0, 8)
 		if err != nil {
-			return fmt.Errorf("invalid uint8 value for flag --%s: %s", f.Name, value)
+			return uintParseError("flag --"+f.Name, value, 8, err)
 		}
 		parsedFlags[f.Name] = uint8(uint8Val)
 		if f.AssignTo != nil {
@@ -238,9 +520,9 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Uint16Flag:
-		uint16Val, err := strconv.ParseUint(value, 10, 16)
+		uint16Val, err := strconv.ParseUint(value, 0, 16)
 		if err != nil {
-			return fmt.Errorf("invalid uint16 value for flag --%s: %s", f.Name, value)
+			return uintParseError("flag --"+f.Name, value, 16, err)
 		}
 		parsedFlags[f.Name] = uint16(uint16Val)
 		if f.AssignTo != nil {
@@ -248,9 +530,9 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Uint32Flag:
-		uint32Val, err := strconv.ParseUint(value, 10, 32)
+		uint32Val, err := strconv.ParseUint(value, 0, 32)
 		if err != nil {
-			return fmt.Errorf("invalid uint32 value for flag --%s: %s", f.Name, value)
+			return uintParseError("flag --"+f.Name, value, 32, err)
 		}
 		parsedFlags[f.Name] = uint32(uint32Val)
 		if f.AssignTo != nil {
@@ -258,9 +540,9 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Uint64Flag:
-		uint64Val, err := strconv.ParseUint(value, 10, 64)
+		uint64Val, err := strconv.ParseUint(value, 0, 64)
 		if err != nil {
-			return fmt.Errorf("invalid uint64 value for flag --%s: %s", f.Name, value)
+			return uintParseError("flag --"+f.Name, value, 64, err)
 		}
 		parsedFlags[f.Name] = uint64Val
 		if f.AssignTo != nil {
@@ -316,15 +598,15 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *IntSliceFlag:
-		intVal, err := strconv.Atoi(value)
+		intVal, err := strconv.ParseInt(value, 0, 0)
 		if err != nil {
 			return fmt.Errorf("invalid integer value for flag --%s: %s", f.Name, value)
 		}
 
 		if existing, ok := parsedFlags[f.Name]; ok {
-			parsedFlags[f.Name] = append(existing.([]int), intVal)
+			parsedFlags[f.Name] = append(existing.([]int), int(intVal))
 		} else {
-			parsedFlags[f.Name] = []int{intVal}
+			parsedFlags[f.Name] = []int{int(intVal)}
 		}
 
 		if f.AssignTo != nil {
@@ -332,7 +614,7 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Int8SliceFlag:
-		int8Val, err := strconv.ParseInt(value, 10, 8)
+		int8Val, err := strconv.ParseInt(value, 0, 8)
 		if err != nil {
 			return fmt.Errorf("invalid int8 value for flag --%s: %s", f.Name, value)
 		}
@@ -348,7 +630,7 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Int16SliceFlag:
-		int16Val, err := strconv.ParseInt(value, 10, 16)
+		int16Val, err := strconv.ParseInt(value, 0, 16)
 		if err != nil {
 			return fmt.Errorf("invalid int16 value for flag --%s: %s", f.Name, value)
 		}
@@ -364,7 +646,7 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Int32SliceFlag:
-		int32Val, err := strconv.ParseInt(value, 10, 32)
+		int32Val, err := strconv.ParseInt(value, 0, 32)
 		if err != nil {
 			return fmt.Errorf("invalid int32 value for flag --%s: %s", f.Name, value)
 		}
@@ -380,7 +662,7 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Int64SliceFlag:
-		int64Val, err := strconv.ParseInt(value, 10, 64)
+		int64Val, err := strconv.ParseInt(value, 0, 64)
 		if err != nil {
 			return fmt.Errorf("invalid int64 value for flag --%s: %s", f.Name, value)
 		}
@@ -395,9 +677,9 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *UintSliceFlag:
-		uintVal, err := strconv.ParseUint(value, 10, 64)
+		uintVal, err := strconv.ParseUint(value, 0, 64)
 		if err != nil {
-			return fmt.Errorf("invalid uint value for flag --%s: %s", f.Name, value)
+			return uintParseError("flag --"+f.Name, value, 64, err)
 		}
 
 		if existing, ok := parsedFlags[f.Name]; ok {
@@ -411,9 +693,9 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Uint8SliceFlag:
-		uint8Val, err := strconv.ParseUint(value, 10, 8)
+		uint8Val, err := strconv.ParseUint(value, 0, 8)
 		if err != nil {
-			return fmt.Errorf("invalid uint8 value for flag --%s: %s", f.Name, value)
+			return uintParseError("flag --"+f.Name, value, 8, err)
 		}
 
 		if existing, ok := parsedFlags[f.Name]; ok {
@@ -427,9 +709,9 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Uint16SliceFlag:
-		uint16Val, err := strconv.ParseUint(value, 10, 16)
+		uint16Val, err := strconv.ParseUint(value, 0, 16)
 		if err != nil {
-			return fmt.Errorf("invalid uint16 value for flag --%s: %s", f.Name, value)
+			return uintParseError("flag --"+f.Name, value, 16, err)
 		}
 
 		if existing, ok := parsedFlags[f.Name]; ok {
@@ -443,9 +725,9 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Uint32SliceFlag:
-		uint32Val, err := strconv.ParseUint(value, 10, 32)
+		uint32Val, err := strconv.ParseUint(value, 0, 32)
 		if err != nil {
-			return fmt.Errorf("invalid uint32 value for flag --%s: %s", f.Name, value)
+			return uintParseError("flag --"+f.Name, value, 32, err)
 		}
 
 		if existing, ok := parsedFlags[f.Name]; ok {
@@ -459,9 +741,9 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		}
 
 	case *Uint64SliceFlag:
-		uint64Val, err := strconv.ParseUint(value, 10, 64)
+		uint64Val, err := strconv.ParseUint(value, 0, 64)
 		if err != nil {
-			return fmt.Errorf("invalid uint64 value for flag --%s: %s", f.Name, value)
+			return uintParseError("flag --"+f.Name, value, 64, err)
 		}
 
 		if existing, ok := parsedFlags[f.Name]; ok {
@@ -505,16 +787,174 @@ func (f *FlagTyped[T]) parseString(value string, hasValue bool, parsedFlags map[
 		if f.AssignTo != nil {
 			*f.AssignTo = parsedFlags[f.Name].([]float64)
 		}
+
+	case *IPFlag:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return fmt.Errorf("invalid IP address for flag --%s: %s", f.Name, value)
+		}
+
+		parsedFlags[f.Name] = ip
+		if f.AssignTo != nil {
+			*f.AssignTo = ip
+		}
+
+	case *IPNetFlag:
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return fmt.Errorf("invalid CIDR for flag --%s: %s", f.Name, value)
+		}
+
+		parsedFlags[f.Name] = ipNet
+		if f.AssignTo != nil {
+			*f.AssignTo = ipNet
+		}
+
+	case *IPSliceFlag:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return fmt.Errorf("invalid IP address for flag --%s: %s", f.Name, value)
+		}
+
+		if existing, ok := parsedFlags[f.Name]; ok {
+			parsedFlags[f.Name] = append(existing.([]net.IP), ip)
+		} else {
+			parsedFlags[f.Name] = []net.IP{ip}
+		}
+
+		if f.AssignTo != nil {
+			*f.AssignTo = parsedFlags[f.Name].([]net.IP)
+		}
+
+	case *IPNetSliceFlag:
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return fmt.Errorf("invalid CIDR for flag --%s: %s", f.Name, value)
+		}
+
+		if existing, ok := parsedFlags[f.Name]; ok {
+			parsedFlags[f.Name] = append(existing.([]*net.IPNet), ipNet)
+		} else {
+			parsedFlags[f.Name] = []*net.IPNet{ipNet}
+		}
+
+		if f.AssignTo != nil {
+			*f.AssignTo = parsedFlags[f.Name].([]*net.IPNet)
+		}
+
+	case *URLFlag:
+		u, err := f.parseURL(value)
+		if err != nil {
+			return err
+		}
+
+		parsedFlags[f.Name] = u
+		if f.AssignTo != nil {
+			*f.AssignTo = u
+		}
+
+	case *URLSliceFlag:
+		u, err := f.parseURL(value)
+		if err != nil {
+			return err
+		}
+
+		if existing, ok := parsedFlags[f.Name]; ok {
+			parsedFlags[f.Name] = append(existing.([]*url.URL), u)
+		} else {
+			parsedFlags[f.Name] = []*url.URL{u}
+		}
+
+		if f.AssignTo != nil {
+			*f.AssignTo = parsedFlags[f.Name].([]*url.URL)
+		}
+
+	case *StringMapFlag:
+		key, val, found := strings.Cut(value, "=")
+		if !found {
+			return fmt.Errorf("invalid key=value pair for flag --%s: %s", f.Name, value)
+		}
+
+		if existing, ok := parsedFlags[f.Name]; ok {
+			existing.(map[string]string)[key] = val
+		} else {
+			parsedFlags[f.Name] = map[string]string{key: val}
+		}
+
+		if f.AssignTo != nil {
+			*f.AssignTo = parsedFlags[f.Name].(map[string]string)
+		}
 	}
 
 	return nil
 }
 
+// parseURL parses a value of a URL flag, checking it has a scheme and host if RequireAbsolute is set
+func (f *FlagTyped[T]) parseURL(value string) (*url.URL, error) {
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL for flag --%s: %s", f.Name, value)
+	}
+	if f.RequireAbsolute && (u.Scheme == "" || u.Host == "") {
+		return nil, fmt.Errorf("flag --%s must be an absolute URL with a scheme and host, got %s", f.Name, value)
+	}
+	return u, nil
+}
+
+// readFileValue returns the contents of the file named by a value starting with @, without the trailing newline,
+// a value starting with @@ is returned as a literal @ and any other value is returned unchanged
+func readFileValue(value string) (string, error) {
+	if strings.HasPrefix(value, "@@") {
+		return value[1:], nil
+	}
+
+	path, ok := strings.CutPrefix(value, "@")
+	if !ok {
+		return value, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+}
+
+// parseCustom parses the value with the flag's Parse function, appending the result to the value of slice flags
+func (f *FlagTyped[T]) parseCustom(value string, parsedFlags map[string]interface{}) error {
+	parsed, err := f.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for flag --%s: %w", f.Name, err)
+	}
+
+	var result T
+	if f.isSlice() {
+		result, _ = parsedFlags[f.Name].(T)
+		values := reflect.ValueOf(&result).Elem()
+		item := reflect.ValueOf(parsed)
+		if !item.IsValid() || !item.Type().AssignableTo(values.Type().Elem()) {
+			return fmt.Errorf("flag --%s: Parse returned %T, expected %s", f.Name, parsed, values.Type().Elem())
+		}
+		values.Set(reflect.Append(values, item))
+	} else {
+		var ok bool
+		if result, ok = parsed.(T); !ok {
+			return fmt.Errorf("flag --%s: Parse returned %T, expected %s", f.Name, parsed, f.valueType())
+		}
+	}
+
+	parsedFlags[f.Name] = result
+	if f.AssignTo != nil {
+		*f.AssignTo = result
+	}
+	return nil
+}
+
 func (f *FlagTyped[T]) flagDefinition() string {
 	var typeInfo string
 
-	// Determine type text based on type T
-	if !f.HideType {
+	// Determine type text based on type T, count flags don't take a value so have no type
+	if !f.HideType && !f.Count {
 		typeInfo = " " + f.typeText()
 	}
 
@@ -564,6 +1004,16 @@ func (f *FlagTyped[T]) defaultValueText() string {
 		return f.DefaultText
 	}
 
+	// A computed default isn't known until the flags are processed
+	if f.DefaultFunc != nil {
+		return ""
+	}
+
+	// False is a meaningful default for a bool so always show it
+	if b, ok := any(f.DefaultValue).(bool); ok {
+		return strconv.FormatBool(b)
+	}
+
 	// Use reflection to check if default value is set
 	zero := reflect.Zero(reflect.TypeOf(f.DefaultValue)).Interface()
 	if reflect.DeepEqual(f.DefaultValue, zero) {
@@ -571,9 +1021,22 @@ func (f *FlagTyped[T]) defaultValueText() string {
 	}
 
 	// Format the default value based on type
+	if f.Secret {
+		return MaskSecret(fmt.Sprintf("%v", f.DefaultValue))
+	}
 	return fmt.Sprintf("%v", f.DefaultValue)
 }
 
+func (f *FlagTyped[T]) defaultOverrideText(value any) string {
+	if f.HideDefault {
+		return ""
+	}
+	if f.Secret {
+		return MaskSecret(fmt.Sprintf("%v", value))
+	}
+	return fmt.Sprintf("%v", value)
+}
+
 func (f *FlagTyped[T]) typeText() string {
 	return GetTypeText(f.DefaultValue)
 }
@@ -2,7 +2,14 @@ package cli
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -358,6 +365,55 @@ func TestFlagEnvironmentVariableOverride(t *testing.T) {
 	}
 }
 
+func TestFlagEnvironmentVariableFallthrough(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    Flag
+		first   string
+		second  string
+		want    any
+		wantErr string
+	}{
+		{"first invalid", &IntFlag{Name: "port", EnvVars: []string{"TEST_PORT_STR", "TEST_PORT"}}, "8080/tcp", "8080", 8080, ""},
+		{"first valid", &IntFlag{Name: "port", EnvVars: []string{"TEST_PORT_STR", "TEST_PORT"}}, "80", "8080", 80, ""},
+		{"slice first invalid", &IntSliceFlag{Name: "port", EnvVars: []string{"TEST_PORT_STR", "TEST_PORT"}}, "80,http", "80,443", []int{80, 443}, ""},
+		{"all invalid", &IntFlag{Name: "port", EnvVars: []string{"TEST_PORT_STR", "TEST_PORT"}}, "http", "https", nil,
+			"environment variable TEST_PORT_STR: invalid integer value for flag --port: http\nenvironment variable TEST_PORT: invalid integer value for flag --port: https"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_PORT_STR", tt.first)
+			t.Setenv("TEST_PORT", tt.second)
+
+			var got any
+			cmd := &Command{
+				Name:  "test",
+				Flags: []Flag{tt.flag},
+				Run: func(ctx context.Context, cmd *Command) error {
+					got = cmd.RawFlags()["port"]
+					return nil
+				},
+			}
+
+			cmd.SetArgs([]string{})
+			err := cmd.Execute(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestUnknownFlag(t *testing.T) {
 	cmd := &Command{
 		Name:    "test",
@@ -413,3 +469,1286 @@ func TestBundledShortFlags(t *testing.T) {
 		t.Fatalf("expected no error, got %v", err)
 	}
 }
+
+func TestIntegerFlagBasePrefixes(t *testing.T) {
+	tests := []struct {
+		name  string
+		flag  Flag
+		value string
+		want  interface{}
+	}{
+		{"int hex", &IntFlag{Name: "n"}, "0xFF", 255},
+		{"int octal", &IntFlag{Name: "n"}, "0o755", 493},
+		{"int binary", &IntFlag{Name: "n"}, "0b1010", 10},
+		{"int decimal", &IntFlag{Name: "n"}, "42", 42},
+		{"int16 hex", &Int16Flag{Name: "n"}, "0x7F", int16(127)},
+		{"int64 binary", &Int64Flag{Name: "n"}, "-0b1010", int64(-10)},
+		{"uint8 hex", &Uint8Flag{Name: "n"}, "0xFF", uint8(255)},
+		{"uint32 octal", &Uint32Flag{Name: "n"}, "0o755", uint32(493)},
+		{"uint binary", &UintFlag{Name: "n"}, "0b1010", uint(10)},
+		{"int slice hex", &IntSliceFlag{Name: "n"}, "0xFF", []int{255}},
+		{"uint16 slice octal", &Uint16SliceFlag{Name: "n"}, "0o755", []uint16{493}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := make(map[string]interface{})
+			if err := tt.flag.parseString(tt.value, true, parsed); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(parsed["n"], tt.want) {
+				t.Fatalf("expected %v (%T), got %v (%T)", tt.want, tt.want, parsed["n"], parsed["n"])
+			}
+		})
+	}
+
+	// Values that overflow the width must still be rejected
+	parsed := make(map[string]interface{})
+	if err := (&Uint8Flag{Name: "n"}).parseString("0x100", true, parsed); err == nil {
+		t.Fatal("expected overflow error for uint8 0x100")
+	}
+}
+
+func TestSliceFlagUnique(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		args   []string
+		unique bool
+		want   []string
+	}{
+		{"config duplicates kept", `{"tags":["a","b","a"]}`, nil, false, []string{"a", "b", "a"}},
+		{"config duplicates removed", `{"tags":["a","b","a"]}`, nil, true, []string{"a", "b"}},
+		{"cli replaces config", `{"tags":["a","b"]}`, []string{"--tags", "c", "--tags", "b", "--tags", "c"}, false, []string{"c", "b", "c"}},
+		{"cli replaces config unique", `{"tags":["a","b"]}`, []string{"--tags", "c", "--tags", "b", "--tags", "c"}, true, []string{"c", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _ := newJSONConfigBase(t, tt.config)
+
+			var assigned []string
+			var got []string
+			cmd := &Command{
+				Name:       "test",
+				ConfigFile: cfg,
+				Flags: []Flag{
+					&StringSliceFlag{Name: "tags", ConfigPath: []string{"tags"}, Unique: tt.unique, AssignTo: &assigned},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					got = cmd.GetStringSlice("tags")
+					return nil
+				},
+			}
+
+			os.Args = append([]string{"test"}, tt.args...)
+			if err := cmd.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+			if !reflect.DeepEqual(assigned, tt.want) {
+				t.Errorf("expected assigned %v, got %v", tt.want, assigned)
+			}
+		})
+	}
+}
+
+func TestSliceFlagInlineValues(t *testing.T) {
+	tests := []struct {
+		name string
+		flag Flag
+		args []string
+		want string
+	}{
+		{"string", &StringSliceFlag{Name: "tag"}, []string{"--tag=x", "--tag", "y", "--tag=z"}, "[x y z]"},
+		{"string with equals", &StringSliceFlag{Name: "tag"}, []string{"--tag=a=b", "--tag", "c"}, "[a=b c]"},
+		{"int", &IntSliceFlag{Name: "tag"}, []string{"--tag=1", "--tag", "2", "--tag=3"}, "[1 2 3]"},
+		{"uint8", &Uint8SliceFlag{Name: "tag"}, []string{"--tag=1", "--tag", "2", "--tag=3"}, "[1 2 3]"},
+		{"float64", &Float64SliceFlag{Name: "tag"}, []string{"--tag=1.5", "--tag", "2", "--tag=-3"}, "[1.5 2 -3]"},
+		{"ip", &IPSliceFlag{Name: "tag"}, []string{"--tag=10.0.0.1", "--tag", "::1"}, "[10.0.0.1 ::1]"},
+		{"url", &URLSliceFlag{Name: "tag"}, []string{"--tag=http://a", "--tag", "http://b"}, "[http://a http://b]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Command{
+				Name:  "test",
+				Flags: []Flag{tt.flag},
+				Run:   func(ctx context.Context, cmd *Command) error { return nil },
+			}
+
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := fmt.Sprint(cmd.RawFlags()["tag"]); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("global across subcommand", func(t *testing.T) {
+		var tags []string
+		root := &Command{
+			Name:  "app",
+			Flags: []Flag{&StringSliceFlag{Name: "tag", Global: true}},
+			Commands: []*Command{
+				{
+					Name: "run",
+					Run: func(ctx context.Context, cmd *Command) error {
+						tags = cmd.GetStringSlice("tag")
+						return nil
+					},
+				},
+			},
+		}
+
+		root.SetArgs([]string{"--tag=x", "--tag", "y", "run", "--tag=z"})
+		if err := root.Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(tags, []string{"x", "y", "z"}) {
+			t.Errorf("expected [x y z], got %v", tags)
+		}
+	})
+}
+
+func TestSliceFlagEnvMatchesConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		flag   func(env, path string) Flag
+		env    string
+		config string
+		want   string
+	}{
+		{
+			name: "string",
+			flag: func(env, path string) Flag {
+				return &StringSliceFlag{Name: "v", EnvVars: []string{env}, ConfigPath: []string{path}}
+			},
+			env:    "x, y,z",
+			config: `["x", "y", "z"]`,
+			want:   "[x y z]",
+		},
+		{
+			name: "int",
+			flag: func(env, path string) Flag {
+				return &IntSliceFlag{Name: "v", EnvVars: []string{env}, ConfigPath: []string{path}}
+			},
+			env:    "1,2000000,3",
+			config: `[1, 2000000, 3]`,
+			want:   "[1 2000000 3]",
+		},
+		{
+			name: "float64",
+			flag: func(env, path string) Flag {
+				return &Float64SliceFlag{Name: "v", EnvVars: []string{env}, ConfigPath: []string{path}}
+			},
+			env:    "0.0000001,2.5",
+			config: `[0.0000001, 2.5]`,
+			want:   "[1e-07 2.5]",
+		},
+		{
+			name:   "invalid value is dropped",
+			flag:   func(env, path string) Flag { return &IntSliceFlag{Name: "v", ConfigPath: []string{path}} },
+			config: `[1, "two", 3]`,
+			want:   "<nil>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := map[string]string{}
+
+			if tt.env != "" {
+				t.Setenv("SLICE_VALUES", tt.env)
+				cmd := &Command{Name: "env", Flags: []Flag{tt.flag("SLICE_VALUES", "")}}
+				cmd.SetArgs([]string{})
+				if err := cmd.Execute(context.Background()); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				results["env"] = fmt.Sprint(cmd.RawFlags()["v"])
+			}
+
+			cfg, _ := newJSONConfigBase(t, `{"values": `+tt.config+`}`)
+			cmd := &Command{Name: "config", ConfigFile: cfg, Flags: []Flag{tt.flag("SLICE_UNSET", "values")}}
+			cmd.SetArgs([]string{})
+			if err := cmd.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			results["config"] = fmt.Sprint(cmd.RawFlags()["v"])
+
+			for source, got := range results {
+				if got != tt.want {
+					t.Errorf("%s: expected %s, got %s", source, tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigLargeWholeNumber(t *testing.T) {
+	cfg, _ := newJSONConfigBase(t, `{"limit": 1000000}`)
+	cmd := &Command{
+		Name:       "test",
+		ConfigFile: cfg,
+		Flags:      []Flag{&IntFlag{Name: "limit", ConfigPath: []string{"limit"}}},
+		Run:        func(ctx context.Context, cmd *Command) error { return nil },
+	}
+
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cmd.GetInt("limit"); got != 1000000 {
+		t.Errorf("expected 1000000, got %d", got)
+	}
+}
+
+func TestIntSliceFlagUniqueDefault(t *testing.T) {
+	var got []int
+	defaults := []int{1, 2, 1, 3}
+	cmd := &Command{
+		Name: "test",
+		Flags: []Flag{
+			&IntSliceFlag{Name: "ids", DefaultValue: defaults, Unique: true},
+		},
+		Run: func(ctx context.Context, cmd *Command) error {
+			got = cmd.GetIntSlice("ids")
+			return nil
+		},
+	}
+
+	os.Args = []string{"test"}
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("expected [1 2 3], got %v", got)
+	}
+	if !reflect.DeepEqual(defaults, []int{1, 2, 1, 3}) {
+		t.Errorf("default value should not be modified, got %v", defaults)
+	}
+}
+
+func TestUintFlagErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    Flag
+		value   string
+		wantErr string
+	}{
+		{"negative", &UintFlag{Name: "workers"}, "-1", "flag --workers must be a non-negative integer, got -1"},
+		{"non-numeric", &UintFlag{Name: "workers"}, "four", "flag --workers must be a non-negative integer, got four"},
+		{"overflow", &UintFlag{Name: "workers"}, "18446744073709551616", "flag --workers must be at most 18446744073709551615, got 18446744073709551616"},
+		{"uint8 overflow", &Uint8Flag{Name: "workers"}, "300", "flag --workers must be at most 255, got 300"},
+		{"slice negative", &UintSliceFlag{Name: "workers"}, "-2", "flag --workers must be a non-negative integer, got -2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Command{
+				Name:  "test",
+				Flags: []Flag{tt.flag},
+				Run:   func(ctx context.Context, cmd *Command) error { return nil },
+			}
+
+			os.Args = []string{"test", "--workers=" + tt.value}
+			err := cmd.Execute(context.Background())
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFlagChoices(t *testing.T) {
+	levels := []string{"debug", "info", "warn", "error"}
+	tests := []struct {
+		name    string
+		flag    Flag
+		args    []string
+		wantErr string
+	}{
+		{"valid", &StringFlag{Name: "log-level", Choices: levels}, []string{"--log-level", "warn"}, ""},
+		{"invalid", &StringFlag{Name: "log-level", Choices: levels}, []string{"--log-level", "trace"}, `invalid value "trace" for --log-level, must be one of: debug, info, warn, error`},
+		{"case sensitive", &StringFlag{Name: "log-level", Choices: levels}, []string{"--log-level", "INFO"}, `invalid value "INFO" for --log-level, must be one of: debug, info, warn, error`},
+		{"case insensitive", &StringFlag{Name: "log-level", Choices: levels, ChoicesCaseInsensitive: true}, []string{"--log-level", "INFO"}, ""},
+		{"default not checked", &StringFlag{Name: "log-level", Choices: levels}, []string{}, ""},
+		{"slice invalid", &StringSliceFlag{Name: "log-level", Choices: levels}, []string{"--log-level", "info", "--log-level", "trace"}, `invalid value "trace" for --log-level, must be one of: debug, info, warn, error`},
+		{"int", &IntFlag{Name: "log-level", Choices: []string{"1", "2"}}, []string{"--log-level", "3"}, `invalid value "3" for --log-level, must be one of: 1, 2`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Command{
+				Name:  "test",
+				Flags: []Flag{tt.flag},
+				Run:   func(ctx context.Context, cmd *Command) error { return nil },
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFlagChoicesCaseInsensitiveStoresChoice(t *testing.T) {
+	var level string
+	var levels []string
+	var onSet string
+	cmd := &Command{
+		Name: "test",
+		Flags: []Flag{
+			&StringFlag{Name: "log-level", Choices: []string{"debug", "info"}, ChoicesCaseInsensitive: true, AssignTo: &level,
+				OnSet: func(value string) { onSet = value }},
+			&StringSliceFlag{Name: "only", Choices: []string{"debug", "info"}, ChoicesCaseInsensitive: true, AssignTo: &levels},
+		},
+		Run: func(ctx context.Context, cmd *Command) error { return nil },
+	}
+
+	cmd.SetArgs([]string{"--log-level", "DEBUG", "--only", "Info", "--only", "debug"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cmd.GetString("log-level"); got != "debug" || level != "debug" || onSet != "debug" {
+		t.Errorf("expected the choice debug, got %q, assigned %q and OnSet %q", got, level, onSet)
+	}
+	if got := cmd.GetStringSlice("only"); !reflect.DeepEqual(got, []string{"info", "debug"}) || !reflect.DeepEqual(levels, got) {
+		t.Errorf("expected the choices [info debug], got %v and assigned %v", got, levels)
+	}
+}
+
+func TestBoolFlagNegation(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantVerbose bool
+		wantArgs    []string
+		wantErr     string
+	}{
+		{"default", []string{}, true, nil, ""},
+		{"negated", []string{"--no-verbose"}, false, nil, ""},
+		{"negated alias", []string{"--no-loud"}, false, nil, ""},
+		{"negated short alias", []string{"--no-v"}, false, nil, ""},
+		{"does not consume value", []string{"--no-verbose", "file"}, false, []string{"file"}, ""},
+		{"last one wins", []string{"--no-verbose", "--verbose"}, true, nil, ""},
+		{"inline value", []string{"--no-verbose=true"}, true, nil, "flag --no-verbose does not take a value"},
+		{"not a bool", []string{"--no-name"}, true, nil, "unknown flag: --no-name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var verbose bool
+			var args []string
+			cmd := &Command{
+				Name:            "test",
+				MaxArgs:         UnlimitedArgs,
+				AllowFlagPrefix: true,
+				Flags: []Flag{
+					&BoolFlag{Name: "verbose", Aliases: []string{"v", "loud"}, DefaultValue: true},
+					&StringFlag{Name: "name"},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					verbose = cmd.GetBool("verbose")
+					args = cmd.GetArgs()
+					return nil
+				},
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if verbose != tt.wantVerbose {
+				t.Errorf("expected verbose %v, got %v", tt.wantVerbose, verbose)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("expected args %v, got %v", tt.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestBoolFlagFalseOverridesTrueDefault(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string
+		config string
+		args   []string
+		want   bool
+	}{
+		{"default", "", `{}`, []string{}, true},
+		{"env false", "false", `{}`, []string{}, false},
+		{"env zero", "0", `{}`, []string{}, false},
+		{"config false", "", `{"log": {"verbose": false}}`, []string{}, false},
+		{"config string false", "", `{"log": {"verbose": "false"}}`, []string{}, false},
+		{"env beats config", "false", `{"log": {"verbose": true}}`, []string{}, false},
+		{"cli beats env", "false", `{}`, []string{"--verbose"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("APP_VERBOSE", tt.env)
+			}
+			cfg, _ := newJSONConfigBase(t, tt.config)
+
+			var verbose, assigned bool
+			root := &Command{
+				Name:       "app",
+				ConfigFile: cfg,
+				Flags: []Flag{
+					&BoolFlag{
+						Name:         "verbose",
+						DefaultValue: true,
+						Global:       true,
+						EnvVars:      []string{"APP_VERBOSE"},
+						ConfigPath:   []string{"log.verbose"},
+						AssignTo:     &assigned,
+					},
+				},
+				Commands: []*Command{
+					{
+						Name: "run",
+						Run: func(ctx context.Context, cmd *Command) error {
+							verbose = cmd.GetBool("verbose")
+							return nil
+						},
+					},
+				},
+			}
+
+			root.SetArgs(append([]string{"run"}, tt.args...))
+			if err := root.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if verbose != tt.want {
+				t.Errorf("expected verbose %v, got %v", tt.want, verbose)
+			}
+			if assigned != tt.want {
+				t.Errorf("expected assigned value %v, got %v", tt.want, assigned)
+			}
+		})
+	}
+}
+
+func TestCountFlag(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		env          string
+		defaultValue int
+		want         int
+		wantArgs     []string
+	}{
+		{"not given", []string{}, "", 0, 0, nil},
+		{"once", []string{"-v"}, "", 0, 1, nil},
+		{"bundled", []string{"-vvv"}, "", 0, 3, nil},
+		{"repeated", []string{"-v", "--verbose", "-vv"}, "", 0, 4, nil},
+		{"bundled with bool", []string{"-vqv"}, "", 0, 2, nil},
+		{"does not consume value", []string{"-v", "file"}, "", 0, 1, []string{"file"}},
+		{"explicit value", []string{"--verbose=5"}, "", 0, 5, nil},
+		{"default base", []string{"-vv"}, "", 1, 3, nil},
+		{"default only", []string{}, "", 1, 1, nil},
+		{"env absolute", []string{}, "2", 0, 2, nil},
+		{"cli overrides env", []string{"-v"}, "2", 0, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_VERBOSE", tt.env)
+			}
+
+			var got, assigned int
+			var args []string
+			cmd := &Command{
+				Name:    "test",
+				MaxArgs: UnlimitedArgs,
+				Flags: []Flag{
+					&IntFlag{Name: "verbose", Aliases: []string{"v"}, Count: true, DefaultValue: tt.defaultValue, EnvVars: []string{"TEST_VERBOSE"}, AssignTo: &assigned},
+					&BoolFlag{Name: "quiet", Aliases: []string{"q"}},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					got = cmd.GetInt("verbose")
+					args = cmd.GetArgs()
+					return nil
+				},
+			}
+
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || assigned != tt.want {
+				t.Errorf("expected count %d, got %d (assigned %d)", tt.want, got, assigned)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("expected args %v, got %v", tt.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestFlagRequires(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		config  string
+		wantErr string
+	}{
+		{"neither", []string{}, "", `{}`, ""},
+		{"both", []string{"--tls-cert", "cert.pem", "--tls-key", "key.pem"}, "", `{}`, ""},
+		{"missing", []string{"--tls-cert", "cert.pem"}, "", `{}`, "--tls-cert requires --tls-key to also be set"},
+		{"key alone", []string{"--tls-key", "key.pem"}, "", `{}`, ""},
+		{"key from env", []string{"--tls-cert", "cert.pem"}, "key.pem", `{}`, ""},
+		{"key from config", []string{"--tls-cert", "cert.pem"}, "", `{"tls":{"key":"key.pem"}}`, ""},
+		{"cert from config", []string{}, "", `{"tls":{"cert":"cert.pem"}}`, "--tls-cert requires --tls-key to also be set"},
+		{"help skips check", []string{"--tls-cert", "cert.pem", "--help"}, "", `{}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_TLS_KEY", tt.env)
+			}
+			cfg, _ := newJSONConfigBase(t, tt.config)
+
+			cmd := &Command{
+				Name:       "test",
+				ConfigFile: cfg,
+				Flags: []Flag{
+					&StringFlag{Name: "tls-cert", ConfigPath: []string{"tls.cert"}, Requires: []string{"tls-key"}},
+					&StringFlag{Name: "tls-key", ConfigPath: []string{"tls.key"}, EnvVars: []string{"TEST_TLS_KEY"}, DefaultValue: "default.pem"},
+				},
+				Run: func(ctx context.Context, cmd *Command) error { return nil },
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStringMapFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		config  string
+		want    map[string]string
+		wantErr string
+	}{
+		{"cli", []string{"--label", "env=prod", "--label", "team=core"}, "", `{}`, map[string]string{"env": "prod", "team": "core"}, ""},
+		{"split on first equals", []string{"--label=query=a=b"}, "", `{}`, map[string]string{"query": "a=b"}, ""},
+		{"later value wins", []string{"-l", "env=dev", "-l", "env=prod"}, "", `{}`, map[string]string{"env": "prod"}, ""},
+		{"missing equals", []string{"--label", "env"}, "", `{}`, nil, "invalid key=value pair for flag --label: env"},
+		{"env", []string{}, "env=prod, team=core", `{}`, map[string]string{"env": "prod", "team": "core"}, ""},
+		{"config table", []string{}, "", `{"labels":{"env":"prod","replicas":3}}`, map[string]string{"env": "prod", "replicas": "3"}, ""},
+		{"cli overrides config", []string{"--label", "env=dev"}, "", `{"labels":{"env":"prod"}}`, map[string]string{"env": "dev"}, ""},
+		{"default", []string{}, "", `{}`, map[string]string{"tier": "web"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_LABELS", tt.env)
+			}
+			cfg, _ := newJSONConfigBase(t, tt.config)
+
+			var got, assigned map[string]string
+			cmd := &Command{
+				Name:       "test",
+				ConfigFile: cfg,
+				Flags: []Flag{
+					&StringMapFlag{
+						Name:         "label",
+						Aliases:      []string{"l"},
+						EnvVars:      []string{"TEST_LABELS"},
+						ConfigPath:   []string{"labels"},
+						DefaultValue: map[string]string{"tier": "web"},
+						AssignTo:     &assigned,
+					},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					got = cmd.GetStringMap("label")
+					return nil
+				},
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || !reflect.DeepEqual(assigned, tt.want) {
+				t.Errorf("expected %v, got %v (assigned %v)", tt.want, got, assigned)
+			}
+		})
+	}
+}
+
+type testColour struct {
+	R, G, B uint8
+}
+
+func parseTestColour(value string) (any, error) {
+	var c testColour
+	if _, err := fmt.Sscanf(value, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return nil, fmt.Errorf("expected #rrggbb, got %s", value)
+	}
+	return c, nil
+}
+
+func TestFlagCustomParse(t *testing.T) {
+	t.Setenv("TEST_PALETTE", "#000000, #ffffff")
+
+	var fg testColour
+	var palette []testColour
+	var port int
+	cmd := &Command{
+		Name: "test",
+		Flags: []Flag{
+			&FlagTyped[testColour]{Name: "fg", Parse: parseTestColour, AssignTo: &fg},
+			&FlagTyped[[]testColour]{Name: "palette", Parse: parseTestColour, EnvVars: []string{"TEST_PALETTE"}, AssignTo: &palette},
+			&IntFlag{Name: "port", Parse: func(s string) (any, error) { return strconv.Atoi(strings.TrimSuffix(s, "/tcp")) }, AssignTo: &port},
+		},
+		Run: func(ctx context.Context, cmd *Command) error { return nil },
+	}
+
+	cmd.SetArgs([]string{"--fg", "#ff8000", "--port", "8080/tcp"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fg != (testColour{255, 128, 0}) {
+		t.Errorf("expected fg {255 128 0}, got %v", fg)
+	}
+	if !reflect.DeepEqual(palette, []testColour{{0, 0, 0}, {255, 255, 255}}) {
+		t.Errorf("expected palette from env, got %v", palette)
+	}
+	if port != 8080 || cmd.GetInt("port") != 8080 {
+		t.Errorf("expected port 8080, got %d", port)
+	}
+
+	errTests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"--fg", "orange"}, "invalid value for flag --fg: expected #rrggbb, got orange"},
+		{[]string{"--port", "http"}, `invalid value for flag --port: strconv.Atoi: parsing "http": invalid syntax`},
+	}
+	for _, tt := range errTests {
+		cmd.SetArgs(tt.args)
+		if err := cmd.Execute(context.Background()); err == nil || err.Error() != tt.wantErr {
+			t.Errorf("expected error %q, got %v", tt.wantErr, err)
+		}
+	}
+}
+
+func TestFlagCustomParseWrongType(t *testing.T) {
+	cmd := &Command{
+		Name: "test",
+		Flags: []Flag{
+			&IntSliceFlag{Name: "ids", Parse: func(s string) (any, error) { return s, nil }},
+		},
+		Run: func(ctx context.Context, cmd *Command) error { return nil },
+	}
+
+	cmd.SetArgs([]string{"--ids", "1"})
+	if err := cmd.Execute(context.Background()); err == nil || err.Error() != "flag --ids: Parse returned string, expected int" {
+		t.Errorf("expected type error, got %v", err)
+	}
+}
+
+func TestFlagFileValue(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		allow   bool
+		args    []string
+		env     string
+		want    string
+		wantErr string
+	}{
+		{"file", true, []string{"--token", "@" + tokenFile}, "", "s3cr3t", ""},
+		{"inline file", true, []string{"--token=@" + tokenFile}, "", "s3cr3t", ""},
+		{"env file", true, []string{}, "@" + tokenFile, "s3cr3t", ""},
+		{"escaped", true, []string{"--token", "@@handle"}, "", "@handle", ""},
+		{"plain", true, []string{"--token", "abc"}, "", "abc", ""},
+		{"not allowed", false, []string{"--token", "@" + tokenFile}, "", "@" + tokenFile, ""},
+		{"missing file", true, []string{"--token", "@" + filepath.Join(dir, "missing")}, "", "",
+			"failed to read value for flag --token: open " + filepath.Join(dir, "missing") + ": no such file or directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_TOKEN", tt.env)
+			}
+
+			var got string
+			cmd := &Command{
+				Name: "test",
+				Flags: []Flag{
+					&StringFlag{Name: "token", EnvVars: []string{"TEST_TOKEN"}, AllowFileValue: tt.allow},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					got = cmd.GetString("token")
+					return nil
+				},
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEnvPrefix(t *testing.T) {
+	t.Setenv("MYAPP_SERVER_PORT", "8080")
+	t.Setenv("MYAPP_DEBUG", "true")
+	t.Setenv("MYAPP_HOST", "prefixed")
+	t.Setenv("EXPLICIT_HOST", "explicit")
+	t.Setenv("WORKER_THREADS", "4")
+	t.Setenv("MYAPP_THREADS", "2")
+
+	var port, threads int
+	var debug bool
+	var host string
+	root := &Command{
+		Name:      "app",
+		EnvPrefix: "MYAPP",
+		Flags: []Flag{
+			&BoolFlag{Name: "debug", Global: true},
+		},
+		Commands: []*Command{
+			{
+				Name: "serve",
+				Flags: []Flag{
+					&IntFlag{Name: "server-port"},
+					&StringFlag{Name: "host", EnvVars: []string{"EXPLICIT_HOST"}},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					port, debug, host = cmd.GetInt("server-port"), cmd.GetBool("debug"), cmd.GetString("host")
+					return nil
+				},
+			},
+			{
+				Name:      "worker",
+				EnvPrefix: "WORKER",
+				Flags:     []Flag{&IntFlag{Name: "threads"}},
+				Run: func(ctx context.Context, cmd *Command) error {
+					threads = cmd.GetInt("threads")
+					return nil
+				},
+			},
+		},
+	}
+
+	root.SetArgs([]string{"serve"})
+	if err := root.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if port != 8080 || !debug || host != "explicit" {
+		t.Errorf("expected port 8080, debug and explicit host, got %d, %v, %q", port, debug, host)
+	}
+
+	root.SetArgs([]string{"worker"})
+	if err := root.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if threads != 4 {
+		t.Errorf("expected the subcommand prefix to override, got %d threads", threads)
+	}
+}
+
+func TestEnvPrefix_SkipsBuiltinFlags(t *testing.T) {
+	t.Setenv("APP_VERSION", "1.2.3")
+	t.Setenv("APP_HELP", "1")
+	t.Setenv("APP_YES", "true")
+
+	ran := false
+	cmd := &Command{
+		Name:      "app",
+		Version:   "1.0.0",
+		EnvPrefix: "APP",
+		Confirm:   "Continue?",
+		Run: func(ctx context.Context, cmd *Command) error {
+			ran = true
+			return nil
+		},
+	}
+
+	// The built-in flags ignore the prefix, so the version isn't parsed as a bool and the prompt still shows
+	withConfirmInput(t, "y\n", true)
+	cmd.SetArgs([]string{})
+	cmd.Output = &strings.Builder{}
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ran {
+		t.Error("expected the command to run")
+	}
+	if cmd.WantsHelp() || cmd.WantsVersion() || cmd.GetBool("yes") {
+		t.Error("expected the built-in flags not to be set from the environment")
+	}
+}
+
+func TestIPFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		env       string
+		config    string
+		wantBind  string
+		wantAllow string
+		wantErr   string
+	}{
+		{"cli", []string{"--bind", "0.0.0.0", "--allow", "10.0.0.0/8", "--allow", "fd00::/8"}, "", `{}`, "0.0.0.0", "[10.0.0.0/8 fd00::/8]", ""},
+		{"ipv6", []string{"--bind=::1"}, "", `{}`, "::1", "[]", ""},
+		{"cidr is masked", []string{"--allow", "192.168.1.5/24"}, "", `{}`, "127.0.0.1", "[192.168.1.0/24]", ""},
+		{"unique", []string{"--allow", "10.0.0.0/8", "--allow", "10.1.0.0/8"}, "", `{}`, "127.0.0.1", "[10.0.0.0/8]", ""},
+		{"bad ip", []string{"--bind", "localhost"}, "", `{}`, "", "", "invalid IP address for flag --bind: localhost"},
+		{"bad cidr", []string{"--allow", "10.0.0.0"}, "", `{}`, "", "", "invalid CIDR for flag --allow: 10.0.0.0"},
+		{"env", []string{}, "10.0.0.0/8, 172.16.0.0/12", `{}`, "127.0.0.1", "[10.0.0.0/8 172.16.0.0/12]", ""},
+		{"config", []string{}, "", `{"bind":"192.168.0.1","allow":["10.0.0.0/8"]}`, "192.168.0.1", "[10.0.0.0/8]", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_ALLOW", tt.env)
+			}
+			cfg, _ := newJSONConfigBase(t, tt.config)
+
+			var bind net.IP
+			var allow []*net.IPNet
+			cmd := &Command{
+				Name:       "test",
+				ConfigFile: cfg,
+				Flags: []Flag{
+					&IPFlag{Name: "bind", ConfigPath: []string{"bind"}, DefaultValue: net.IPv4(127, 0, 0, 1)},
+					&IPNetSliceFlag{Name: "allow", ConfigPath: []string{"allow"}, EnvVars: []string{"TEST_ALLOW"}, Unique: true},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					bind, allow = cmd.GetIP("bind"), cmd.GetIPNetSlice("allow")
+					return nil
+				},
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if bind.String() != tt.wantBind {
+				t.Errorf("expected bind %s, got %s", tt.wantBind, bind)
+			}
+			if fmt.Sprint(allow) != tt.wantAllow {
+				t.Errorf("expected allow %s, got %v", tt.wantAllow, allow)
+			}
+		})
+	}
+}
+
+func TestNetworkFlagTypeText(t *testing.T) {
+	tests := []struct {
+		flag Flag
+		want string
+	}{
+		{&IPFlag{Name: "bind"}, "    --bind ip"},
+		{&IPNetFlag{Name: "subnet"}, "    --subnet cidr"},
+		{&IPSliceFlag{Name: "dns"}, "    --dns ips"},
+		{&IPNetSliceFlag{Name: "allow"}, "    --allow cidrs"},
+		{&URLFlag{Name: "endpoint"}, "    --endpoint url"},
+		{&URLSliceFlag{Name: "mirror"}, "    --mirror urls"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.flag.flagDefinition(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+		if tt.flag.isSlice() != strings.HasSuffix(tt.want, "s") {
+			t.Errorf("unexpected isSlice for %q", tt.want)
+		}
+	}
+}
+
+func TestOnSet(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		env    string
+		config string
+		want   []string
+	}{
+		{"cli", []string{"--host", "cli.example.com"}, "", `{}`, []string{"cli.example.com"}},
+		{"env", []string{}, "env.example.com", `{}`, []string{"env.example.com"}},
+		{"config", []string{}, "", `{"host":"config.example.com"}`, []string{"config.example.com"}},
+		{"default", []string{}, "", `{}`, []string{"localhost"}},
+		{"repeated", []string{"--host", "a", "--host", "b"}, "", `{}`, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_HOST", tt.env)
+			}
+			cfg, _ := newJSONConfigBase(t, tt.config)
+
+			var got []string
+			var assigned string
+			cmd := &Command{
+				Name:       "test",
+				ConfigFile: cfg,
+				Flags: []Flag{
+					&StringFlag{
+						Name:         "host",
+						EnvVars:      []string{"TEST_HOST"},
+						ConfigPath:   []string{"host"},
+						DefaultValue: "localhost",
+						AssignTo:     &assigned,
+						OnSet:        func(value string) { got = append(got, value) },
+					},
+				},
+				Run: func(ctx context.Context, cmd *Command) error { return nil },
+			}
+
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected OnSet calls %v, got %v", tt.want, got)
+			}
+			if assigned != tt.want[len(tt.want)-1] {
+				t.Errorf("expected AssignTo to hold %q, got %q", tt.want[len(tt.want)-1], assigned)
+			}
+		})
+	}
+}
+
+func TestOnSetSlice(t *testing.T) {
+	var got []string
+	cmd := &Command{
+		Name: "test",
+		Flags: []Flag{
+			&StringSliceFlag{Name: "tag", Unique: true, OnSet: func(value []string) { got = value }},
+		},
+		Run: func(ctx context.Context, cmd *Command) error { return nil },
+	}
+
+	cmd.SetArgs([]string{"--tag", "a", "--tag", "b", "--tag", "a"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected the last OnSet call to get the unique values, got %v", got)
+	}
+}
+
+func TestOnSetFinalValueOnly(t *testing.T) {
+	// The first environment variable fails part way through, only the value from the second is kept
+	t.Setenv("TEST_PORTS_A", "80,http")
+	t.Setenv("TEST_PORTS_B", "8080,8443")
+
+	var ports [][]int
+	var levels []string
+	newCmd := func() *Command {
+		return &Command{
+			Name: "test",
+			Flags: []Flag{
+				&IntSliceFlag{Name: "port", EnvVars: []string{"TEST_PORTS_A", "TEST_PORTS_B"}, OnSet: func(value []int) { ports = append(ports, value) }},
+				&StringFlag{Name: "level", Choices: []string{"debug", "info"}, OnSet: func(value string) { levels = append(levels, value) }},
+			},
+			Run: func(ctx context.Context, cmd *Command) error { return nil },
+		}
+	}
+
+	cmd := newCmd()
+	cmd.SetArgs([]string{"--level", "info"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ports, [][]int{{8080, 8443}}) || !reflect.DeepEqual(levels, []string{"info"}) {
+		t.Errorf("expected one OnSet call with each final value, got ports %v and levels %v", ports, levels)
+	}
+
+	// A value rejected by Choices isn't passed on
+	ports, levels = nil, nil
+	cmd = newCmd()
+	cmd.SetArgs([]string{"--level", "trace"})
+	if err := cmd.Execute(context.Background()); err == nil {
+		t.Fatal("expected an error for the invalid choice")
+	}
+	if len(ports) != 0 || len(levels) != 0 {
+		t.Errorf("expected no OnSet calls for invalid flags, got ports %v and levels %v", ports, levels)
+	}
+}
+
+func TestURLFlags(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		env          string
+		config       string
+		wantEndpoint string
+		wantMirrors  string
+		wantErr      string
+	}{
+		{"cli", []string{"--endpoint", "https://api.example.com/v1", "--mirror", "https://a.example.com", "--mirror", "/local"}, "", `{}`, "https://api.example.com/v1", "[https://a.example.com /local]", ""},
+		{"default", []string{}, "", `{}`, "http://localhost:8080", "[]", ""},
+		{"not absolute", []string{"--endpoint", "api.example.com"}, "", `{}`, "", "", "flag --endpoint must be an absolute URL with a scheme and host, got api.example.com"},
+		{"no host", []string{"--endpoint", "file:///tmp"}, "", `{}`, "", "", "flag --endpoint must be an absolute URL with a scheme and host, got file:///tmp"},
+		{"invalid", []string{"--mirror", "http://[::1"}, "", `{}`, "", "", "invalid URL for flag --mirror: http://[::1"},
+		{"env", []string{}, "https://env.example.com", `{}`, "https://env.example.com", "[]", ""},
+		{"config", []string{}, "", `{"endpoint":"https://config.example.com","mirrors":["https://b.example.com"]}`, "https://config.example.com", "[https://b.example.com]", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_ENDPOINT", tt.env)
+			}
+			cfg, _ := newJSONConfigBase(t, tt.config)
+
+			defaultURL, _ := url.Parse("http://localhost:8080")
+			var endpoint *url.URL
+			var mirrors []*url.URL
+			cmd := &Command{
+				Name:       "test",
+				ConfigFile: cfg,
+				Flags: []Flag{
+					&URLFlag{Name: "endpoint", EnvVars: []string{"TEST_ENDPOINT"}, ConfigPath: []string{"endpoint"}, DefaultValue: defaultURL, RequireAbsolute: true},
+					&URLSliceFlag{Name: "mirror", ConfigPath: []string{"mirrors"}},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					endpoint, mirrors = cmd.GetURL("endpoint"), cmd.GetURLSlice("mirror")
+					return nil
+				},
+			}
+
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if endpoint.String() != tt.wantEndpoint {
+				t.Errorf("expected endpoint %s, got %s", tt.wantEndpoint, endpoint)
+			}
+			if fmt.Sprint(mirrors) != tt.wantMirrors {
+				t.Errorf("expected mirrors %s, got %v", tt.wantMirrors, mirrors)
+			}
+		})
+	}
+}
+
+func TestDefaultFunc(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		env       string
+		config    string
+		want      string
+		wantCalls int
+	}{
+		{"default", []string{}, "", `{}`, "computed", 1},
+		{"cli", []string{"--host", "cli"}, "", `{}`, "cli", 0},
+		{"env", []string{}, "env", `{}`, "env", 0},
+		{"config", []string{}, "", `{"host":"config"}`, "config", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_HOST", tt.env)
+			}
+			cfg, _ := newJSONConfigBase(t, tt.config)
+
+			calls := 0
+			var got, assigned string
+			cmd := &Command{
+				Name:       "test",
+				ConfigFile: cfg,
+				Flags: []Flag{
+					&StringFlag{
+						Name:         "host",
+						EnvVars:      []string{"TEST_HOST"},
+						ConfigPath:   []string{"host"},
+						DefaultValue: "static",
+						DefaultFunc: func() string {
+							calls++
+							return "computed"
+						},
+						AssignTo: &assigned,
+					},
+				},
+				Run: func(ctx context.Context, cmd *Command) error {
+					got = cmd.GetString("host")
+					return nil
+				},
+			}
+
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || assigned != tt.want {
+				t.Errorf("expected %q, got %q and assigned %q", tt.want, got, assigned)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("expected DefaultFunc to be called %d times, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestDefaultFuncHelpText(t *testing.T) {
+	flag := &StringFlag{Name: "host", DefaultFunc: func() string { return "computed" }}
+	if text := flag.defaultValueText(); text != "" {
+		t.Errorf("expected no default text for a computed default, got %q", text)
+	}
+
+	flag.DefaultText = "the hostname"
+	if text := flag.defaultValueText(); text != "the hostname" {
+		t.Errorf("expected DefaultText, got %q", text)
+	}
+}
+
+func TestCommandFlagDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantLevel   string
+		wantWorkers int
+		wantErr     string
+	}{
+		{"root keeps default", []string{}, "info", 1, ""},
+		{"subcommand overrides", []string{"debug"}, "debug", 1, ""},
+		{"nested inherits override", []string{"debug", "trace"}, "debug", 8, ""},
+		{"cli wins", []string{"debug", "--log-level", "warn"}, "warn", 1, ""},
+		{"wrong type", []string{"broken"}, "", 0, "default for flag --workers must be int, got float64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var level string
+			var workers int
+			run := func(ctx context.Context, cmd *Command) error {
+				level, workers = cmd.GetString("log-level"), cmd.GetInt("workers")
+				return nil
+			}
+
+			root := &Command{
+				Name: "app",
+				Flags: []Flag{
+					&StringFlag{Name: "log-level", DefaultValue: "info", Global: true},
+					&IntFlag{Name: "workers", DefaultValue: 1, Global: true},
+				},
+				Run: run,
+				Commands: []*Command{
+					{
+						Name:         "debug",
+						FlagDefaults: map[string]any{"log-level": "debug"},
+						Run:          run,
+						Commands: []*Command{
+							{Name: "trace", FlagDefaults: map[string]any{"workers": "8"}, Run: run},
+						},
+					},
+					{Name: "broken", FlagDefaults: map[string]any{"workers": 2.5}, Run: run},
+				},
+			}
+
+			root.SetArgs(tt.args)
+			err := root.Execute(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if level != tt.wantLevel || workers != tt.wantWorkers {
+				t.Errorf("expected %s and %d workers, got %s and %d", tt.wantLevel, tt.wantWorkers, level, workers)
+			}
+		})
+	}
+}
+
+func TestCommandFlagDefaultsHelp(t *testing.T) {
+	sub := &Command{Name: "debug", FlagDefaults: map[string]any{"log-level": "debug"}}
+	root := &Command{
+		Name:     "app",
+		Flags:    []Flag{&StringFlag{Name: "log-level", Usage: "Log level", DefaultValue: "info", Global: true}},
+		Commands: []*Command{sub},
+	}
+
+	root.SetArgs([]string{"debug", "--help"})
+	if err := root.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if help := sub.HelpString(); !strings.Contains(help, "Log level (default: debug)") {
+		t.Errorf("expected the overridden default in help, got:\n%s", help)
+	}
+	if help := root.HelpString(); !strings.Contains(help, "Log level (default: info)") {
+		t.Errorf("expected the flag's default in the root help, got:\n%s", help)
+	}
+}
@@ -18,7 +18,33 @@ func NewConfigFile(fileName *string, searchPathFunc cli.SearchPathFunc) cli.Conf
 	cfg.FileName = fileName
 	cfg.SearchPath = searchPathFunc
 	cfg.Unmarshal = json.Unmarshal
-	cfg.Marshal = json.Marshal
+	cfg.Marshal = marshalIndent
 
 	return cfg
 }
+
+// NewConfigReader returns a configuration source that reads JSON from data rather than a file, e.g. for tests.
+// FileUsed reports "[memory]" and Save does nothing.
+func NewConfigReader(data []byte) cli.ConfigFileSource {
+	cfg := &jsonConfiguration{}
+
+	cfg.InitConfigFile()
+
+	if data == nil {
+		data = []byte{}
+	}
+	cfg.Content = data
+	cfg.Unmarshal = json.Unmarshal
+	cfg.Marshal = marshalIndent
+
+	return cfg
+}
+
+// marshalIndent encodes the configuration indented by two spaces, so the saved file stays readable
+func marshalIndent(v any) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
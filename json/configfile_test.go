@@ -0,0 +1,112 @@
+package cli_json
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paularlott/cli"
+)
+
+const testConfig = `{
+  "name": "demo",
+  "server": {
+    "port": 8080,
+    "id": 12345678,
+    "ratio": 0.5,
+    "ports": [80, 443]
+  },
+  "backends": [
+    {"name": "one", "weight": 1},
+    {"name": "two", "weight": 2}
+  ]
+}`
+
+func TestNewConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg := NewConfigFile(&path, nil)
+	if keys := strings.Join(cli.GetKeysRecursive(cfg, ""), ","); keys != "backends,name,server.id,server.port,server.ports,server.ratio" {
+		t.Errorf("unexpected keys %s", keys)
+	}
+
+	if err := cfg.SetValue("server.port", 9090); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if !strings.Contains(string(content), "\n  \"server\": {\n    \"id\": 12345678,\n") || !strings.HasSuffix(string(content), "}\n") {
+		t.Errorf("expected the saved file to be indented, got:\n%s", content)
+	}
+
+	reloaded := cli.NewTypedConfigFile(NewConfigFile(&path, nil))
+	if port := reloaded.GetInt("server.port"); port != 9090 {
+		t.Errorf("expected port 9090 after reload, got %d", port)
+	}
+}
+
+func TestNumbersFromJSON(t *testing.T) {
+	cfg := cli.NewTypedConfigFile(NewConfigReader([]byte(testConfig)))
+
+	if v := cfg.GetInt("server.port"); v != 8080 {
+		t.Errorf("GetInt: expected 8080, got %d", v)
+	}
+	if v := cfg.GetUint16("server.port"); v != 8080 {
+		t.Errorf("GetUint16: expected 8080, got %d", v)
+	}
+	if v := cfg.GetInt64("server.id"); v != 12345678 {
+		t.Errorf("GetInt64: expected 12345678, got %d", v)
+	}
+	if v := cfg.GetString("server.id"); v != "12345678" {
+		t.Errorf("GetString: expected 12345678, got %q", v)
+	}
+	if v := cfg.GetFloat32("server.ratio"); v != 0.5 {
+		t.Errorf("GetFloat32: expected 0.5, got %v", v)
+	}
+	if v := cfg.GetUintSlice("server.ports"); len(v) != 2 || v[0] != 80 || v[1] != 443 {
+		t.Errorf("GetUintSlice: expected [80 443], got %v", v)
+	}
+	if v := cfg.GetStringSlice("server.ports"); strings.Join(v, ",") != "80,443" {
+		t.Errorf("GetStringSlice: expected [80 443], got %v", v)
+	}
+
+	backends := cfg.GetObjectSlice("backends")
+	if len(backends) != 2 || backends[1].GetString("name") != "two" || backends[1].GetInt("weight") != 2 {
+		t.Errorf("unexpected backends %v", backends)
+	}
+}
+
+func TestFlagsFromJSON(t *testing.T) {
+	var port int
+	var id string
+	var ports []uint
+	cmd := &cli.Command{
+		Name:       "app",
+		ConfigFile: NewConfigReader([]byte(testConfig)),
+		Flags: []cli.Flag{
+			&cli.IntFlag{Name: "port", ConfigPath: []string{"server.port"}, AssignTo: &port},
+			&cli.StringFlag{Name: "id", ConfigPath: []string{"server.id"}, AssignTo: &id},
+			&cli.UintSliceFlag{Name: "ports", ConfigPath: []string{"server.ports"}, AssignTo: &ports},
+		},
+		Run: func(ctx context.Context, cmd *cli.Command) error { return nil },
+	}
+
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if port != 8080 || id != "12345678" || len(ports) != 2 || ports[1] != 443 {
+		t.Errorf("unexpected values from the config: port %d, id %q, ports %v", port, id, ports)
+	}
+}
@@ -19,6 +19,24 @@ func NewConfigFile(fileName *string, searchPathFunc cli.SearchPathFunc) cli.Conf
 	cfg.SearchPath = searchPathFunc
 	cfg.Unmarshal = toml.Unmarshal
 	cfg.Marshal = toml.Marshal
+	cfg.Merge = merge
+
+	return cfg
+}
+
+// NewConfigReader returns a configuration source that reads TOML from data rather than a file, e.g. for tests.
+// FileUsed reports "[memory]" and Save does nothing.
+func NewConfigReader(data []byte) cli.ConfigFileSource {
+	cfg := &tomlConfiguration{}
+
+	cfg.InitConfigFile()
+
+	if data == nil {
+		data = []byte{}
+	}
+	cfg.Content = data
+	cfg.Unmarshal = toml.Unmarshal
+	cfg.Marshal = toml.Marshal
 
 	return cfg
 }
@@ -0,0 +1,105 @@
+package cli_toml
+
+import (
+	"context"
+	"testing"
+
+	"github.com/paularlott/cli"
+)
+
+func TestNewConfigReader(t *testing.T) {
+	cfg := NewConfigReader([]byte(commentedConfig))
+
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("LoadData failed: %v", err)
+	}
+	if got := cfg.FileUsed(); got != "[memory]" {
+		t.Errorf("expected FileUsed to be [memory], got %q", got)
+	}
+
+	if port, ok := cfg.GetValue("server.port"); !ok || port != int64(8080) {
+		t.Errorf("expected server.port 8080, got %v", port)
+	}
+	if level, ok := cfg.GetValue("logging.level"); !ok || level != "info" {
+		t.Errorf("expected logging.level info, got %v", level)
+	}
+	if _, ok := cfg.GetValue("server.missing"); ok {
+		t.Error("expected server.missing to not be found")
+	}
+
+	if err := cfg.SetValue("server.port", 9090); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if port, _ := cfg.GetValue("server.port"); port != 9090 {
+		t.Errorf("expected the set value to be kept in memory, got %v", port)
+	}
+}
+
+func TestNewConfigReaderEmpty(t *testing.T) {
+	for _, data := range [][]byte{nil, {}} {
+		cfg := NewConfigReader(data)
+		if err := cfg.LoadData(); err != nil {
+			t.Fatalf("LoadData failed: %v", err)
+		}
+		if keys := cfg.GetKeys(""); len(keys) != 0 {
+			t.Errorf("expected no keys, got %v", keys)
+		}
+	}
+}
+
+func TestNewConfigReaderInvalid(t *testing.T) {
+	cfg := NewConfigReader([]byte("not = [valid"))
+	if err := cfg.LoadData(); err == nil {
+		t.Error("expected an error for invalid TOML")
+	}
+}
+
+func TestBoolFlagFalseFromConfig(t *testing.T) {
+	var verbose bool
+	cmd := &cli.Command{
+		Name:       "app",
+		ConfigFile: NewConfigReader([]byte("[log]\nverbose = false\n")),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "verbose", DefaultValue: true, ConfigPath: []string{"log.verbose"}},
+		},
+		Run: func(ctx context.Context, cmd *cli.Command) error {
+			verbose = cmd.GetBool("verbose")
+			return nil
+		},
+	}
+
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verbose {
+		t.Error("expected verbose = false in the config to override the true default")
+	}
+}
+
+func TestArrayOfTables(t *testing.T) {
+	cfg := cli.NewTypedConfigFile(NewConfigReader([]byte(`
+[[backends]]
+host = "one"
+
+[[backends]]
+host = "two"
+
+[cluster]
+pools = [{ name = "a" }, { name = "b" }]
+`)))
+
+	backends := cfg.GetObjectSlice("backends")
+	if len(backends) != 2 || backends[0].GetString("host") != "one" || backends[1].GetString("host") != "two" {
+		t.Errorf("unexpected backends %v", backends)
+	}
+
+	// Inline arrays of tables decode differently to [[tables]] but are read the same way
+	pools := cfg.GetObject("cluster").GetObjectSlice("pools")
+	if len(pools) != 2 || pools[1].GetString("name") != "b" {
+		t.Errorf("unexpected pools %v", pools)
+	}
+}
@@ -0,0 +1,506 @@
+package cli_toml
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+var bareKeyRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlEntry is a key / value pair in the original document, spanning one or more lines
+type tomlEntry struct {
+	start, end int      // First and last line of the entry
+	path       []string // Full path to the key, including the table
+}
+
+// merge updates the original TOML document with the values in v, keeping comments, blank lines and the order of keys.
+// Values that haven't changed are left untouched, changed values are rewritten in place, removed keys are dropped and
+// new keys are added to the end of their table. If the document can't be updated in place, e.g. an array of tables
+// has changed, the whole document is encoded again keeping only the comments at the top of the file.
+func merge(original []byte, v any) ([]byte, error) {
+	if data, ok := v.(map[string]any); ok {
+		merged, err := mergeDocument(string(original), data)
+		if err == nil && sameContent(merged, data) {
+			return []byte(merged), nil
+		}
+	}
+
+	encoded, err := toml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if header := headerComments(string(original)); header != "" {
+		encoded = append([]byte(header+"\n\n"), encoded...)
+	}
+	return encoded, nil
+}
+
+// headerComments returns the comment lines at the top of the document, before the first key or table
+func headerComments(original string) string {
+	var header []string
+	for _, line := range strings.Split(original, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		header = append(header, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.Trim(strings.Join(header, "\n"), "\n")
+}
+
+func mergeDocument(original string, data map[string]any) (string, error) {
+	var origData map[string]any
+	if err := toml.Unmarshal([]byte(original), &origData); err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(original, "\n")
+
+	var entries []tomlEntry
+	headers := make(map[string]int)    // Line of each table header
+	sectionEnd := make(map[string]int) // Last line of each table with a header
+	var headerLines []int
+	var arrayTables [][]string
+	lastRootLine := -1
+
+	var table []string
+	inArray := false
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[[") {
+			table = parseKey(headerName(trimmed, 2))
+			arrayTables = append(arrayTables, table)
+			headerLines = append(headerLines, i)
+			inArray = true
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") {
+			table = parseKey(headerName(trimmed, 1))
+			key := strings.Join(table, "\x00")
+			headers[key] = i
+			sectionEnd[key] = i
+			headerLines = append(headerLines, i)
+			inArray = false
+			continue
+		}
+
+		keyText, value, ok := splitKeyValue(lines[i])
+		if !ok {
+			return "", errors.New("unsupported line")
+		}
+		end := valueEnd(lines, i, value)
+
+		if !inArray {
+			path := append(append([]string{}, table...), parseKey(keyText)...)
+			entries = append(entries, tomlEntry{start: i, end: end, path: path})
+			if len(table) == 0 {
+				lastRootLine = end
+			} else {
+				sectionEnd[strings.Join(table, "\x00")] = end
+			}
+		}
+		i = end
+	}
+
+	// Arrays of tables are left as they are, so they can't have changed
+	for _, path := range arrayTables {
+		origValue, _ := getPath(origData, path)
+		newValue, _ := getPath(data, path)
+		if !reflect.DeepEqual(origValue, newValue) {
+			return "", errors.New("array of tables changed")
+		}
+	}
+
+	removed := make(map[int]bool)
+	replaced := make(map[int]string)
+
+	for _, entry := range entries {
+		newValue, ok := getPath(data, entry.path)
+		if !ok {
+			for i := entry.start; i <= entry.end; i++ {
+				removed[i] = true
+			}
+			continue
+		}
+
+		if origValue, _ := getPath(origData, entry.path); reflect.DeepEqual(origValue, newValue) {
+			continue
+		}
+
+		encoded, err := encodeValue(newValue)
+		if err != nil {
+			return "", err
+		}
+
+		line := lines[entry.start]
+		keyText, value, _ := splitKeyValue(line)
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		replacement := indent + strings.TrimSpace(keyText) + " = " + encoded
+		if entry.start == entry.end {
+			if comment := inlineComment(value); comment != "" {
+				replacement += " " + comment
+			}
+		}
+		replaced[entry.start] = replacement
+		for i := entry.start + 1; i <= entry.end; i++ {
+			removed[i] = true
+		}
+	}
+
+	// Drop the headers of tables that have been deleted
+	for key, line := range headers {
+		if v, ok := getPath(data, strings.Split(key, "\x00")); !ok || !isMap(v) {
+			removed[line] = true
+		}
+	}
+
+	// Find the keys that need adding
+	inserts := make(map[int][]string)
+	var sections []string
+	var addMissing func(prefix []string, m map[string]any) error
+	addMissing = func(prefix []string, m map[string]any) error {
+		for _, k := range sortedKeys(m) {
+			path := append(append([]string{}, prefix...), k)
+			v := m[k]
+
+			if origValue, ok := getPath(origData, path); ok {
+				if sub, ok := v.(map[string]any); ok && isMap(origValue) {
+					if err := addMissing(path, sub); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			if sub, ok := v.(map[string]any); ok {
+				section, err := encodeTable(path, sub)
+				if err != nil {
+					return err
+				}
+				sections = append(sections, section)
+				continue
+			}
+
+			encoded, err := encodeValue(v)
+			if err != nil {
+				return err
+			}
+			line := formatKey(k) + " = " + encoded
+
+			tableKey := strings.Join(prefix, "\x00")
+			switch {
+			case len(prefix) == 0 && lastRootLine >= 0:
+				inserts[lastRootLine] = append(inserts[lastRootLine], line)
+			case len(prefix) == 0 && len(headerLines) > 0:
+				inserts[headerLines[0]-1] = append(inserts[headerLines[0]-1], line)
+			case len(prefix) == 0:
+				inserts[len(lines)-1] = append(inserts[len(lines)-1], line)
+			default:
+				end, ok := sectionEnd[tableKey]
+				if !ok {
+					return errors.New("table without a header")
+				}
+				inserts[end] = append(inserts[end], line)
+			}
+		}
+		return nil
+	}
+	if err := addMissing(nil, data); err != nil {
+		return "", err
+	}
+
+	var out []string
+	out = append(out, inserts[-1]...)
+	for i, line := range lines {
+		if replacement, ok := replaced[i]; ok {
+			out = append(out, replacement)
+		} else if !removed[i] {
+			out = append(out, line)
+		}
+		out = append(out, inserts[i]...)
+	}
+
+	result := strings.Join(out, "\n")
+	for _, section := range sections {
+		result = strings.TrimRight(result, "\n") + "\n\n" + section
+	}
+	if !strings.HasSuffix(result, "\n") {
+		result += "\n"
+	}
+
+	return result, nil
+}
+
+// sameContent checks the merged document holds exactly the data, as a guard against layouts the merge doesn't understand
+func sameContent(merged string, data map[string]any) bool {
+	var got map[string]any
+	if err := toml.Unmarshal([]byte(merged), &got); err != nil {
+		return false
+	}
+
+	encoded, err := toml.Marshal(data)
+	if err != nil {
+		return false
+	}
+	var want map[string]any
+	if err := toml.Unmarshal(encoded, &want); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(got, want)
+}
+
+// headerName returns the table name from a header line, e.g. "server" from "[server] # comment"
+func headerName(line string, brackets int) string {
+	line = line[brackets:]
+	if idx := indexOutsideQuotes(line, ']'); idx >= 0 {
+		line = line[:idx]
+	}
+	return line
+}
+
+// splitKeyValue splits a "key = value" line into the key text and value text
+func splitKeyValue(line string) (string, string, bool) {
+	idx := indexOutsideQuotes(line, '=')
+	if idx < 0 {
+		return "", "", false
+	}
+	return line[:idx], line[idx+1:], true
+}
+
+// valueEnd returns the last line of a value that starts on line start, following multi-line strings and arrays
+func valueEnd(lines []string, start int, value string) int {
+	value = strings.TrimSpace(value)
+
+	for _, delim := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(value, delim) {
+			if strings.Contains(value[3:], delim) {
+				return start
+			}
+			for i := start + 1; i < len(lines); i++ {
+				if strings.Contains(lines[i], delim) {
+					return i
+				}
+			}
+			return len(lines) - 1
+		}
+	}
+
+	depth := bracketDepth(value)
+	i := start
+	for depth > 0 && i+1 < len(lines) {
+		i++
+		depth += bracketDepth(lines[i])
+	}
+	return i
+}
+
+// bracketDepth returns the change in nesting of arrays and inline tables across the text, ignoring strings and comments
+func bracketDepth(text string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return depth
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// indexOutsideQuotes returns the index of the first target character that isn't inside a quoted string
+func indexOutsideQuotes(text string, target byte) int {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == target:
+			return i
+		}
+	}
+	return -1
+}
+
+// inlineComment returns the comment at the end of a single line value, including the #
+func inlineComment(value string) string {
+	if idx := indexOutsideQuotes(value, '#'); idx >= 0 {
+		return strings.TrimSpace(value[idx:])
+	}
+	return ""
+}
+
+// parseKey splits a possibly dotted and quoted key into its parts
+func parseKey(text string) []string {
+	var parts []string
+	for {
+		idx := indexOutsideQuotes(text, '.')
+		part := text
+		if idx >= 0 {
+			part = text[:idx]
+		}
+
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, `"`) {
+			if unquoted, err := strconv.Unquote(part); err == nil {
+				part = unquoted
+			}
+		} else if strings.HasPrefix(part, "'") {
+			part = strings.Trim(part, "'")
+		}
+		parts = append(parts, part)
+
+		if idx < 0 {
+			return parts
+		}
+		text = text[idx+1:]
+	}
+}
+
+// formatKey quotes a key if it can't be written as a bare key
+func formatKey(key string) string {
+	if bareKeyRe.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+// encodeValue encodes a single value as TOML, tables are written inline
+func encodeValue(v any) (string, error) {
+	if m, ok := v.(map[string]any); ok {
+		parts := make([]string, 0, len(m))
+		for _, k := range sortedKeys(m) {
+			encoded, err := encodeValue(m[k])
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, formatKey(k)+" = "+encoded)
+		}
+		if len(parts) == 0 {
+			return "{}", nil
+		}
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && containsMap(rv) {
+		parts := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			encoded, err := encodeValue(rv.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, encoded)
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	}
+
+	encoded, err := toml.Marshal(map[string]any{"v": v})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.TrimPrefix(string(encoded), "v = ")), nil
+}
+
+// encodeTable encodes a new table with its header, sub-tables follow with their own headers
+func encodeTable(path []string, m map[string]any) (string, error) {
+	keys := make([]string, len(path))
+	for i, k := range path {
+		keys[i] = formatKey(k)
+	}
+
+	var b strings.Builder
+	b.WriteString("[" + strings.Join(keys, ".") + "]\n")
+
+	var subTables []string
+	for _, k := range sortedKeys(m) {
+		if sub, ok := m[k].(map[string]any); ok {
+			section, err := encodeTable(append(append([]string{}, path...), k), sub)
+			if err != nil {
+				return "", err
+			}
+			subTables = append(subTables, section)
+			continue
+		}
+
+		encoded, err := encodeValue(m[k])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(formatKey(k) + " = " + encoded + "\n")
+	}
+
+	for _, section := range subTables {
+		b.WriteString("\n" + section)
+	}
+
+	return b.String(), nil
+}
+
+func containsMap(rv reflect.Value) bool {
+	for i := 0; i < rv.Len(); i++ {
+		if isMap(rv.Index(i).Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+func isMap(v any) bool {
+	_, ok := v.(map[string]any)
+	return ok
+}
+
+func getPath(m map[string]any, path []string) (any, bool) {
+	var current any = m
+	for _, key := range path {
+		next, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = next[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,166 @@
+package cli_toml
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const commentedConfig = `# Application configuration
+name = "demo" # the application name
+
+# Server settings
+[server]
+# Port to listen on
+port = 8080 # default port
+host = "localhost"
+
+[logging]
+level = "info"
+`
+
+func loadCommentedConfig(t *testing.T) (*tomlConfiguration, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(commentedConfig), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg := NewConfigFile(&path, nil).(*tomlConfiguration)
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("LoadData failed: %v", err)
+	}
+
+	return cfg, path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	return string(content)
+}
+
+func TestSavePreservesComments(t *testing.T) {
+	cfg, path := loadCommentedConfig(t)
+
+	if err := cfg.SetValue("server.port", 9090); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	expected := strings.Replace(commentedConfig, "port = 8080 # default port", "port = 9090 # default port", 1)
+	if got := readFile(t, path); got != expected {
+		t.Errorf("unexpected content:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestSaveAddsAndRemovesKeys(t *testing.T) {
+	cfg, path := loadCommentedConfig(t)
+
+	if err := cfg.SetValue("server.timeout", 30); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := cfg.DeleteKey("server.host"); err != nil {
+		t.Fatalf("DeleteKey failed: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got := readFile(t, path)
+	if strings.Contains(got, "host") {
+		t.Errorf("expected host to be removed:\n%s", got)
+	}
+	if !strings.Contains(got, "# Port to listen on") || !strings.Contains(got, "# Server settings") {
+		t.Errorf("expected comments to be kept:\n%s", got)
+	}
+
+	timeout := strings.Index(got, "timeout = 30")
+	logging := strings.Index(got, "[logging]")
+	if timeout < 0 || timeout < strings.Index(got, "port = 8080") || timeout > logging {
+		t.Errorf("expected timeout to be added to the server table:\n%s", got)
+	}
+
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("LoadData failed: %v", err)
+	}
+	if port, _ := cfg.GetValue("server.port"); port != int64(8080) {
+		t.Errorf("expected port 8080 after reload, got %v", port)
+	}
+}
+
+func TestSaveKeepsHeaderCommentsWhenEncodingAgain(t *testing.T) {
+	const config = `# Application configuration
+# Edit with care
+
+name = "demo"
+
+[[servers]]
+host = "a"
+`
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg := NewConfigFile(&path, nil).(*tomlConfiguration)
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("LoadData failed: %v", err)
+	}
+
+	// Changing an array of tables can't be done in place, so the document is encoded again
+	if err := cfg.SetValue("servers", []map[string]any{{"host": "b"}}); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got := readFile(t, path)
+	if !strings.HasPrefix(got, "# Application configuration\n# Edit with care\n\n") {
+		t.Errorf("expected the header comments to be kept:\n%s", got)
+	}
+	if !strings.Contains(got, `host = "b"`) || strings.Contains(got, `host = "a"`) {
+		t.Errorf("expected the array of tables to be updated:\n%s", got)
+	}
+}
+
+func TestSaveRemovesEmptiedTables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `name = "demo"
+
+[service]
+name = "api"
+
+[service.cache]
+ttl = 30
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg := NewConfigFile(&path, nil)
+	if err := cfg.DeleteKey("service.cache.ttl"); err != nil {
+		t.Fatalf("DeleteKey failed: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got := readFile(t, path)
+	if strings.Contains(got, "[service.cache]") || strings.Contains(got, "ttl") {
+		t.Errorf("expected the emptied table to be removed, got:\n%s", got)
+	}
+	if !strings.Contains(got, "[service]\nname = \"api\"") {
+		t.Errorf("expected the service table to be kept, got:\n%s", got)
+	}
+}
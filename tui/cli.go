@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/paularlott/cli"
+)
+
+// CommandsFromCLI maps the subcommands of a CLI command tree to slash commands, so a REPL style TUI can expose the same commands.
+// Each command takes its description from Usage and offers as args its own subcommands, its ValidArgs, the Choices of its
+// first argument and the Choices of its flags as --flag=value.
+//
+// The handler runs the command with root.InvokeSubcommand using the context of t, passing any text typed after the command
+// as arguments. Anything the command writes to the Output of root, such as help, is added to t as an assistant message and
+// an error as a system message.
+func CommandsFromCLI(t *TUI, root *cli.Command) []*Command {
+	commands := make([]*Command, 0, len(root.Commands))
+	for _, sub := range root.Commands {
+		name := sub.Name
+		commands = append(commands, &Command{
+			Name:        name,
+			Description: sub.Usage,
+			Args:        cliCommandArgs(root, sub),
+			Handler: func(input string) {
+				runCLICommand(t, root, append([]string{name}, strings.Fields(input)...))
+			},
+		})
+	}
+	return commands
+}
+
+// cliCommandArgs returns the values offered in the palette after the command
+func cliCommandArgs(root, cmd *cli.Command) []string {
+	var args []string
+	add := func(values ...string) {
+		for _, value := range values {
+			if !slices.Contains(args, value) {
+				args = append(args, value)
+			}
+		}
+	}
+
+	for _, child := range cmd.Commands {
+		add(child.Name)
+	}
+	add(cmd.ValidArgs...)
+
+	first := true
+	cmd.VisitArguments(func(info cli.ArgumentInfo) {
+		if first {
+			add(info.Choices...)
+			first = false
+		}
+	})
+
+	addFlag := func(info cli.FlagInfo) {
+		if info.Hidden {
+			return
+		}
+		for _, choice := range info.Choices {
+			add("--" + info.Name + "=" + choice)
+		}
+	}
+	cmd.VisitFlags(addFlag)
+
+	// The global flags of the root are only inherited once the command has been run
+	root.VisitFlags(func(info cli.FlagInfo) {
+		if info.Global {
+			addFlag(info)
+		}
+	})
+
+	return args
+}
+
+// runCLICommand runs the command line with the context of t, adding the output and any error to t
+func runCLICommand(t *TUI, root *cli.Command, args []string) {
+	ctx := t.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var out strings.Builder
+	previous := root.Output
+	root.Output = &out
+	err := root.InvokeSubcommand(ctx, args)
+	root.Output = previous
+
+	if text := strings.TrimRight(out.String(), "\n"); text != "" {
+		t.AddMessage(RoleAssistant, text)
+	}
+	if err != nil {
+		t.AddMessage(RoleSystem, "Error: "+err.Error())
+	}
+}
@@ -166,7 +166,7 @@ func (p *palette) render(buf *strings.Builder, t *Theme, w, maxRows, startRow in
 		}
 		buf.WriteString(cursorPos(startRow+len(visible), 1))
 		buf.WriteString(clearLine())
-		buf.WriteString(fg(t.Dim) + "  ↑↓ navigate · Tab select · Esc close" + reset)
+		buf.WriteString(fg(t.muted()) + "  ↑↓ navigate · Tab select · Esc close" + reset)
 		return len(visible) + 1
 	}
 	if len(p.filtered) == 0 {
@@ -184,12 +184,12 @@ func (p *palette) render(buf *strings.Builder, t *Theme, w, maxRows, startRow in
 			buf.WriteString("  " + italic() + fg(t.Secondary) + cmd.Description + reset)
 		} else {
 			buf.WriteString("  " + fg(t.Secondary) + "/" + cmd.Name + reset)
-			buf.WriteString("  " + fg(t.Dim) + cmd.Description + reset)
+			buf.WriteString("  " + fg(t.muted()) + cmd.Description + reset)
 		}
 	}
 	buf.WriteString(cursorPos(startRow+len(visible), 1))
 	buf.WriteString(clearLine())
-	buf.WriteString(fg(t.Dim) + "  ↑↓ navigate · Tab select · Esc close" + reset)
+	buf.WriteString(fg(t.muted()) + "  ↑↓ navigate · Tab select · Esc close" + reset)
 	return len(visible) + 1
 }
 
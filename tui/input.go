@@ -3,16 +3,38 @@ package tui
 import "strings"
 
 type inputArea struct {
-	lines   [][]rune
-	row     int
-	col     int
-	viewOff int
-	history []string
-	hisIdx  int    // current position in history (-1 = not browsing)
-	draft   string // saved current input while browsing
+	lines    [][]rune
+	row      int
+	col      int
+	viewOff  int
+	history  []string
+	hisIdx   int    // current position in history (-1 = not browsing)
+	draft    string // saved current input while browsing
+	undo     []inputSnapshot
+	redo     []inputSnapshot
+	lastEdit editKind // kind of the previous edit, runs of the same kind share one undo entry
 }
 
-const inputMinHeight = 4
+// inputSnapshot is the state of the input saved for undo and redo.
+type inputSnapshot struct {
+	lines [][]rune
+	row   int
+	col   int
+}
+
+type editKind int
+
+const (
+	editNone editKind = iota
+	editInsert
+	editDelete
+	editOther
+)
+
+const (
+	inputMinHeight = 4
+	inputUndoLimit = 100 // maximum number of undo entries kept
+)
 
 func newInputArea() *inputArea {
 	return &inputArea{lines: [][]rune{{}}, hisIdx: -1}
@@ -25,6 +47,60 @@ func (a *inputArea) reset() {
 	a.viewOff = 0
 	a.hisIdx = -1
 	a.draft = ""
+	a.undo = nil
+	a.redo = nil
+	a.lastEdit = editNone
+}
+
+func (a *inputArea) snapshot() inputSnapshot {
+	lines := make([][]rune, len(a.lines))
+	for i, l := range a.lines {
+		lines[i] = append([]rune(nil), l...)
+	}
+	return inputSnapshot{lines: lines, row: a.row, col: a.col}
+}
+
+func (a *inputArea) restore(s inputSnapshot) {
+	a.lines = s.lines
+	a.row = s.row
+	a.col = s.col
+	a.lastEdit = editNone
+}
+
+// saveUndo records the input before an edit of the given kind.
+// Consecutive inserts or deletes are grouped so they're undone together.
+func (a *inputArea) saveUndo(kind editKind) {
+	if kind != editOther && kind == a.lastEdit {
+		return
+	}
+	a.lastEdit = kind
+	a.redo = nil
+	a.undo = append(a.undo, a.snapshot())
+	if len(a.undo) > inputUndoLimit {
+		a.undo = a.undo[len(a.undo)-inputUndoLimit:]
+	}
+}
+
+// undoEdit reverts the last edit, returns true if the input was changed.
+func (a *inputArea) undoEdit() bool {
+	if len(a.undo) == 0 {
+		return false
+	}
+	a.redo = append(a.redo, a.snapshot())
+	a.restore(a.undo[len(a.undo)-1])
+	a.undo = a.undo[:len(a.undo)-1]
+	return true
+}
+
+// redoEdit reapplies the last undone edit, returns true if the input was changed.
+func (a *inputArea) redoEdit() bool {
+	if len(a.redo) == 0 {
+		return false
+	}
+	a.undo = append(a.undo, a.snapshot())
+	a.restore(a.redo[len(a.redo)-1])
+	a.redo = a.redo[:len(a.redo)-1]
+	return true
 }
 
 // pushHistory saves a submitted entry to history.
@@ -85,6 +161,7 @@ func (a *inputArea) setLines(text string) {
 	a.row = 0
 	a.col = len(a.lines[0])
 	a.viewOff = 0
+	a.lastEdit = editNone
 }
 
 func (a *inputArea) text() string {
@@ -96,6 +173,7 @@ func (a *inputArea) text() string {
 }
 
 func (a *inputArea) insertRune(r rune) {
+	a.saveUndo(editInsert)
 	line := a.lines[a.row]
 	newLine := make([]rune, len(line)+1)
 	copy(newLine, line[:a.col])
@@ -106,6 +184,7 @@ func (a *inputArea) insertRune(r rune) {
 }
 
 func (a *inputArea) insertNewline() {
+	a.saveUndo(editOther)
 	line := a.lines[a.row]
 	before := make([]rune, a.col)
 	copy(before, line[:a.col])
@@ -122,6 +201,9 @@ func (a *inputArea) insertNewline() {
 }
 
 func (a *inputArea) backspace() {
+	if a.col > 0 || a.row > 0 {
+		a.saveUndo(editDelete)
+	}
 	if a.col > 0 {
 		line := a.lines[a.row]
 		a.lines[a.row] = append(line[:a.col-1], line[a.col:]...)
@@ -138,6 +220,9 @@ func (a *inputArea) backspace() {
 
 func (a *inputArea) deleteForward() {
 	line := a.lines[a.row]
+	if a.col < len(line) || a.row < len(a.lines)-1 {
+		a.saveUndo(editDelete)
+	}
 	if a.col < len(line) {
 		a.lines[a.row] = append(line[:a.col], line[a.col+1:]...)
 	} else if a.row < len(a.lines)-1 {
@@ -147,6 +232,7 @@ func (a *inputArea) deleteForward() {
 }
 
 func (a *inputArea) moveLeft() {
+	a.lastEdit = editNone
 	if a.col > 0 {
 		a.col--
 	} else if a.row > 0 {
@@ -156,6 +242,7 @@ func (a *inputArea) moveLeft() {
 }
 
 func (a *inputArea) moveRight() {
+	a.lastEdit = editNone
 	if a.col < len(a.lines[a.row]) {
 		a.col++
 	} else if a.row < len(a.lines)-1 {
@@ -165,6 +252,7 @@ func (a *inputArea) moveRight() {
 }
 
 func (a *inputArea) moveUp() {
+	a.lastEdit = editNone
 	if a.row > 0 {
 		a.row--
 		if a.col > len(a.lines[a.row]) {
@@ -174,6 +262,7 @@ func (a *inputArea) moveUp() {
 }
 
 func (a *inputArea) moveDown() {
+	a.lastEdit = editNone
 	if a.row < len(a.lines)-1 {
 		a.row++
 		if a.col > len(a.lines[a.row]) {
@@ -182,16 +271,29 @@ func (a *inputArea) moveDown() {
 	}
 }
 
-func (a *inputArea) home() { a.col = 0 }
-func (a *inputArea) end()  { a.col = len(a.lines[a.row]) }
+func (a *inputArea) home() {
+	a.lastEdit = editNone
+	a.col = 0
+}
+
+func (a *inputArea) end() {
+	a.lastEdit = editNone
+	a.col = len(a.lines[a.row])
+}
 
 // ctrlK clears from cursor to end of line.
 func (a *inputArea) ctrlK() {
+	if a.col < len(a.lines[a.row]) {
+		a.saveUndo(editOther)
+	}
 	a.lines[a.row] = a.lines[a.row][:a.col]
 }
 
 // ctrlU clears from start of line to cursor.
 func (a *inputArea) ctrlU() {
+	if a.col > 0 {
+		a.saveUndo(editOther)
+	}
 	a.lines[a.row] = a.lines[a.row][a.col:]
 	a.col = 0
 }
@@ -206,10 +308,37 @@ func (a *inputArea) ctrlW() {
 	for i > 0 && line[i-1] != ' ' {
 		i--
 	}
+	if i < a.col {
+		a.saveUndo(editOther)
+	}
 	a.lines[a.row] = append(line[:i], line[a.col:]...)
 	a.col = i
 }
 
+// moveTo places the cursor at a visible content row and text column, as clicked by the mouse.
+// Clicks past the end of a line go to the end of the line, clicks below the text go to the last line.
+func (a *inputArea) moveTo(visRow, visCol, contentW int) {
+	if visRow < 0 || visCol < 0 {
+		return
+	}
+	row := a.viewOff + visRow
+	if row >= len(a.lines) {
+		row = len(a.lines) - 1
+	}
+	// The active line is scrolled horizontally to keep the cursor in view, see renderLineWithCursor.
+	start := 0
+	if row == a.row && a.col >= contentW {
+		start = a.col - contentW + 1
+	}
+	col := start + visCol
+	if col > len(a.lines[row]) {
+		col = len(a.lines[row])
+	}
+	a.row = row
+	a.col = col
+	a.lastEdit = editNone
+}
+
 // render draws the input box into buf using absolute cursor positioning.
 // overlay is optional text embedded right-aligned into the top border (replaces ─ chars).
 // statusLeft/statusRight are embedded into the bottom border; empty strings are hidden.
@@ -242,7 +371,7 @@ func (a *inputArea) render(buf *strings.Builder, t *Theme, w, maxHeight, startRo
 	if overlay == "" {
 		buf.WriteString(fg(t.Dim) + "┌" + strings.Repeat("─", w-2) + "┐" + reset)
 	} else {
-		ovl := " " + overlay + " "
+		ovl := " " + ellipsize(overlay, w-4) + " "
 		ovlLen := visibleLen(ovl)
 		dashW := w - 2 - ovlLen
 		if dashW < 0 {
@@ -286,9 +415,10 @@ func (a *inputArea) render(buf *strings.Builder, t *Theme, w, maxHeight, startRo
 	buf.WriteString(clearLine())
 	buf.WriteString(fg(t.Dim) + "│" + reset + strings.Repeat(" ", w-2) + fg(t.Dim) + "│" + reset)
 
-	// bottom border — embed statusLeft and statusRight if provided
+	// bottom border — embed statusLeft and statusRight if provided, shrunk to fit the border
 	buf.WriteString(cursorPos(startRow+3+innerH, 1))
 	buf.WriteString(clearLine())
+	statusLeft, statusRight = fitStatus(statusLeft, statusRight, w-2-statusPadding(statusLeft, statusRight))
 	switch {
 	case statusLeft != "" && statusRight != "":
 		left := " " + statusLeft + " "
@@ -323,6 +453,18 @@ func (a *inputArea) render(buf *strings.Builder, t *Theme, w, maxHeight, startRo
 	return height
 }
 
+// statusPadding returns the number of spaces added around the non-empty status texts.
+func statusPadding(left, right string) int {
+	n := 0
+	if left != "" {
+		n += 2
+	}
+	if right != "" {
+		n += 2
+	}
+	return n
+}
+
 // renderLineWithCursor renders a line of runes clipped to maxW visible chars,
 // inserting a cursor marker if active.
 func renderLineWithCursor(line []rune, col int, active bool, maxW int) string {
@@ -169,7 +169,7 @@ func (ms *menuState) render(buf *strings.Builder, t *Theme, w, height, startRow
 			hintPad = 0
 		}
 		buf.WriteString(fg(t.Dim) + "│" + reset)
-		buf.WriteString(fg(t.Dim) + hint + strings.Repeat(" ", hintPad) + reset)
+		buf.WriteString(fg(t.muted()) + hint + strings.Repeat(" ", hintPad) + reset)
 		buf.WriteString(fg(t.Dim) + "│" + reset)
 		row++
 	} else {
@@ -230,7 +230,7 @@ func (ms *menuState) render(buf *strings.Builder, t *Theme, w, height, startRow
 			hintPad = 0
 		}
 		buf.WriteString(fg(t.Dim) + "│" + reset)
-		buf.WriteString(fg(t.Dim) + hint + strings.Repeat(" ", hintPad) + reset)
+		buf.WriteString(fg(t.muted()) + hint + strings.Repeat(" ", hintPad) + reset)
 		buf.WriteString(fg(t.Dim) + "│" + reset)
 		row++
 	}
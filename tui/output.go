@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"math"
 	"strings"
 	"unicode/utf8"
 )
@@ -81,6 +82,12 @@ func (o *outputRegion) scrollDown(n int) {
 	}
 }
 
+// scrollToTop scrolls to the first line, render clamps the offset to the number of lines.
+func (o *outputRegion) scrollToTop() { o.scrollOff = math.MaxInt }
+
+// scrollToBottom scrolls to the last line so new output is followed again.
+func (o *outputRegion) scrollToBottom() { o.scrollOff = 0 }
+
 // render draws the output region into buf, using height terminal rows of width w.
 // startRow is the 1-based terminal row where the region begins.
 func (o *outputRegion) render(buf *strings.Builder, t *Theme, w, height, startRow int) {
@@ -195,7 +202,7 @@ func roleHeader(m *message, t *Theme, w int, userLabel, assistantLabel, systemLa
 	b.WriteString(fg(t.Dim))
 	b.WriteString("━━")
 	b.WriteString(reset)
-	b.WriteString(fg(t.Primary))
+	b.WriteString(fg(roleColor(m.role, t)))
 	b.WriteString(bold())
 	b.WriteString(label)
 	b.WriteString(reset)
@@ -205,6 +212,18 @@ func roleHeader(m *message, t *Theme, w int, userLabel, assistantLabel, systemLa
 	return b.String()
 }
 
+// roleColor returns the header color for a message role, assistants use the accent so they stand apart from the user.
+func roleColor(role MessageRole, t *Theme) Color {
+	switch role {
+	case RoleAssistant:
+		return t.accent()
+	case RoleUser:
+		return t.Primary
+	default:
+		return t.muted()
+	}
+}
+
 func renderText(text string, t *Theme, role MessageRole, w int) []string {
 	var lines []string
 	c := fg(t.Text)
@@ -294,6 +313,34 @@ func truncate(s string, n int) string {
 	return truncatePlain(stripANSI(s), n)
 }
 
+// ellipsize trims s to at most n visible runes, marking the cut with an ellipsis.
+func ellipsize(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if visibleLen(s) <= n {
+		return s
+	}
+	return truncatePlain(stripANSI(s), n-1) + "…"
+}
+
+// fitStatus shrinks the left and right status texts so that together they are
+// at most w visible runes, taking from the longer text first.
+func fitStatus(left, right string, w int) (string, string) {
+	if w < 0 {
+		w = 0
+	}
+	l, r := visibleLen(left), visibleLen(right)
+	for l+r > w {
+		if l >= r {
+			l--
+		} else {
+			r--
+		}
+	}
+	return ellipsize(left, l), ellipsize(right, r)
+}
+
 func truncatePlain(s string, n int) string {
 	runes := []rune(s)
 	if len(runes) <= n {
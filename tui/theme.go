@@ -10,12 +10,16 @@ type Theme struct {
 	Name      string
 	Primary   Color // Accents, prompt >
 	Secondary Color // Muted text, hints
+	Accent    Color // Assistant labels, highlights that need to stand apart from Primary
 	Text      Color // Normal content
 	UserText  Color // User message text
+	Muted     Color // Secondary content such as system labels and key hints
 	Dim       Color // Very muted (scrollbar, borders)
 	CodeBG    Color // Code block background
 	CodeText  Color // Code block text
 	Error     Color // Error messages
+	Warning   Color // Warning messages
+	Success   Color // Success messages
 }
 
 // Built-in themes.
@@ -25,12 +29,16 @@ var (
 		Name:      "default",
 		Primary:   0x4EB8C8,
 		Secondary: 0xC0395A,
+		Accent:    0xE0B45C,
 		Text:      0xE8EAF0,
 		UserText:  0x4EB8C8,
+		Muted:     0x9AA3B0,
 		Dim:       0x7A8492,
 		CodeBG:    0x111A26,
 		CodeText:  0xE8EAF0,
 		Error:     0xC0395A,
+		Warning:   0xE5C07B,
+		Success:   0x6CC88A,
 	}
 
 	// ThemeAmber — warm dark background, amber primary, teal secondary.
@@ -38,12 +46,16 @@ var (
 		Name:      "amber",
 		Primary:   0xE8A87C,
 		Secondary: 0x7EC8A4,
+		Accent:    0xB4A0E8,
 		Text:      0xCDD6F4,
 		UserText:  0xE8A87C,
+		Muted:     0x9399B2,
 		Dim:       0x6C6F85,
 		CodeBG:    0x1E1A14,
 		CodeText:  0xCDD6F4,
 		Error:     0xF38BA8,
+		Warning:   0xF9E2AF,
+		Success:   0xA6E3A1,
 	}
 
 	// ThemeBlue — deep dark background, periwinkle primary, sky secondary.
@@ -51,12 +63,16 @@ var (
 		Name:      "blue",
 		Primary:   0x7BA7E8,
 		Secondary: 0x5BC8D8,
+		Accent:    0xC792EA,
 		Text:      0xD0D8F0,
 		UserText:  0x7BA7E8,
+		Muted:     0x8A92A6,
 		Dim:       0x5A6070,
 		CodeBG:    0x0D1117,
 		CodeText:  0xD0D8F0,
 		Error:     0xE06C75,
+		Warning:   0xE5C07B,
+		Success:   0x98C379,
 	}
 
 	// ThemeGreen — dark terminal, mint primary, gold secondary.
@@ -64,12 +80,16 @@ var (
 		Name:      "green",
 		Primary:   0x7EC87A,
 		Secondary: 0xD4A843,
+		Accent:    0x5BC8D8,
 		Text:      0xD8E0D0,
 		UserText:  0x7EC87A,
+		Muted:     0x8A9680,
 		Dim:       0x5A6650,
 		CodeBG:    0x0D1A0F,
 		CodeText:  0xD8E0D0,
 		Error:     0xE05050,
+		Warning:   0xE0C050,
+		Success:   0x9ED89A,
 	}
 
 	// ThemePurple — dark background, lavender primary, rose secondary.
@@ -77,12 +97,16 @@ var (
 		Name:      "purple",
 		Primary:   0xB48EE8,
 		Secondary: 0xE87EB4,
+		Accent:    0x7EC8E8,
 		Text:      0xE0D8F0,
 		UserText:  0xB48EE8,
+		Muted:     0x9A90B0,
 		Dim:       0x6A6080,
 		CodeBG:    0x130D1E,
 		CodeText:  0xE0D8F0,
 		Error:     0xF07070,
+		Warning:   0xE8C07E,
+		Success:   0x8EE8A4,
 	}
 
 	// ThemeLight — light background, blue primary, green secondary.
@@ -90,12 +114,16 @@ var (
 		Name:      "light",
 		Primary:   0x1A56CC,
 		Secondary: 0x0A7A50,
+		Accent:    0x8A2BB8,
 		Text:      0x1A1A2E,
 		UserText:  0x1A56CC,
+		Muted:     0x4A4A5E,
 		Dim:       0x666677,
 		CodeBG:    0xE8EAF0,
 		CodeText:  0x1A1A2E,
 		Error:     0xCC2020,
+		Warning:   0xB36B00,
+		Success:   0x0A7A50,
 	}
 
 	// ThemePlain uses no colors (monochrome).
@@ -104,6 +132,22 @@ var (
 	}
 )
 
+// accent returns the Accent color, falling back to Primary for themes that don't set it.
+func (t *Theme) accent() Color {
+	if t.Accent != 0 {
+		return t.Accent
+	}
+	return t.Primary
+}
+
+// muted returns the Muted color, falling back to Dim for themes that don't set it.
+func (t *Theme) muted() Color {
+	if t.Muted != 0 {
+		return t.Muted
+	}
+	return t.Dim
+}
+
 var themeRegistry = map[string]*Theme{
 	"default": ThemeDefault,
 	"amber":   ThemeAmber,
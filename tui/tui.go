@@ -52,6 +52,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -127,8 +128,12 @@ type TUI struct {
 	progressLabel string
 	ctx           context.Context
 	menu          *menuState
+	lastPaste     time.Time // when input containing newlines last arrived in a burst, see inPasteBurst
 }
 
+// pasteBurstGap is how soon after pasted input an Enter is taken to be part of the paste.
+const pasteBurstGap = 20 * time.Millisecond
+
 // New creates a new TUI with the given configuration.
 func New(cfg Config) *TUI {
 	if cfg.Theme == nil {
@@ -272,6 +277,22 @@ func (t *TUI) ClearOutput() {
 	t.draw()
 }
 
+// ScrollToTop scrolls the output region to the first message.
+func (t *TUI) ScrollToTop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.output.scrollToTop()
+	t.draw()
+}
+
+// ScrollToBottom scrolls the output region to the latest message and follows new output.
+func (t *TUI) ScrollToBottom() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.output.scrollToBottom()
+	t.draw()
+}
+
 // refresh redraws the screen.
 func (t *TUI) refresh() {
 	t.mu.Lock()
@@ -427,7 +448,7 @@ func (t *TUI) Run(ctx context.Context) error {
 	t.resize()
 	t.draw()
 
-	// Enable mouse wheel reporting (SGR extended mode).
+	// Enable mouse wheel and click reporting (SGR extended mode).
 	fmt.Print("\x1b[?1000h\x1b[?1006h")
 	defer fmt.Print("\x1b[?1006l\x1b[?1000l")
 
@@ -555,25 +576,26 @@ func (t *TUI) draw() {
 			}
 			buf.WriteString(fg(t.theme.Dim) + strings.Repeat("─", sepW) + " " + reset + fg(t.theme.Primary) + scrollHint + " " + reset)
 		} else if !t.inputEnabled() && (t.cfg.StatusLeft != "" || t.cfg.StatusRight != "") {
-			// Embed status into the separator line.
+			// Embed status into the separator line, shrunk so it never overflows.
+			statusLeft, statusRight := fitStatus(t.cfg.StatusLeft, t.cfg.StatusRight, t.width-statusPadding(t.cfg.StatusLeft, t.cfg.StatusRight))
 			switch {
-			case t.cfg.StatusLeft != "" && t.cfg.StatusRight != "":
-				left := " " + t.cfg.StatusLeft + " "
-				right := " " + t.cfg.StatusRight + " "
+			case statusLeft != "" && statusRight != "":
+				left := " " + statusLeft + " "
+				right := " " + statusRight + " "
 				dashW := t.width - visibleLen(left) - visibleLen(right)
 				if dashW < 0 {
 					dashW = 0
 				}
 				buf.WriteString(fg(t.theme.Primary) + left + reset + fg(t.theme.Dim) + strings.Repeat("─", dashW) + reset + fg(t.theme.Primary) + right + reset)
-			case t.cfg.StatusLeft != "":
-				left := " " + t.cfg.StatusLeft + " "
+			case statusLeft != "":
+				left := " " + statusLeft + " "
 				dashW := t.width - visibleLen(left)
 				if dashW < 0 {
 					dashW = 0
 				}
 				buf.WriteString(fg(t.theme.Primary) + left + reset + fg(t.theme.Dim) + strings.Repeat("─", dashW) + reset)
-			case t.cfg.StatusRight != "":
-				right := " " + t.cfg.StatusRight + " "
+			case statusRight != "":
+				right := " " + statusRight + " "
 				dashW := t.width - visibleLen(right)
 				if dashW < 0 {
 					dashW = 0
@@ -631,6 +653,26 @@ func (t *TUI) draw() {
 	fmt.Print(buf.String())
 }
 
+// clickInput moves the input cursor to the clicked terminal cell, x and y are 1-based.
+// Clicks outside the text area of the input box are ignored.
+func (t *TUI) clickInput(x, y int) {
+	if !t.inputEnabled() || t.menu != nil {
+		return
+	}
+	inputH := t.inputBoxHeight()
+	startRow := t.height - inputH + 1
+	innerH := inputH - 4
+
+	// Content rows start below the top border and padding row, text starts after "│ > ".
+	visRow := y - startRow - 2
+	visCol := x - 5
+	contentW := t.width - 5
+	if visRow < 0 || visRow >= innerH || visCol < 0 || visCol >= contentW {
+		return
+	}
+	t.input.moveTo(visRow, visCol, contentW)
+}
+
 func (t *TUI) handleInput(b []byte) func() {
 	// Ctrl+C
 	if len(b) == 1 && b[0] == 3 {
@@ -767,6 +809,14 @@ func (t *TUI) handleInput(b []byte) func() {
 		case 'F': // End
 			t.input.end()
 			return nil
+		case '1': // Shift+Home: ESC [ 1 ; 2 H, Shift+End: ESC [ 1 ; 2 F
+			switch string(b) {
+			case "\x1b[1;2H":
+				t.output.scrollToTop()
+			case "\x1b[1;2F":
+				t.output.scrollToBottom()
+			}
+			return nil
 		case '2': // Shift+Enter: ESC [ 2 7 ; 2 ; 1 3 ~
 			if len(b) == 10 && string(b) == "\x1b[27;2;13~" {
 				t.input.insertNewline()
@@ -805,6 +855,14 @@ func (t *TUI) handleInput(b []byte) func() {
 				parts := strings.SplitN(s[:len(s)-1], ";", 3)
 				if len(parts) == 3 {
 					switch parts[0] {
+					case "0": // left button
+						if s[len(s)-1] == 'M' {
+							x, errX := strconv.Atoi(parts[1])
+							y, errY := strconv.Atoi(parts[2])
+							if errX == nil && errY == nil {
+								t.clickInput(x, y)
+							}
+						}
 					case "64": // wheel up
 						t.output.scrollUp(3)
 					case "65": // wheel down
@@ -858,8 +916,8 @@ func (t *TUI) handleInput(b []byte) func() {
 		return nil
 	}
 
-	// Enter.
-	if len(b) == 1 && (b[0] == '\r' || b[0] == '\n') {
+	// Enter, unless it arrived as part of a paste.
+	if len(b) == 1 && (b[0] == '\r' || b[0] == '\n') && !t.inPasteBurst() {
 		if t.palette.active {
 			if t.palette.argMode {
 				if arg := t.palette.selectedArg(); arg != "" {
@@ -963,11 +1021,27 @@ func (t *TUI) handleInput(b []byte) func() {
 		case 23: // Ctrl+W
 			t.input.ctrlW()
 			return nil
+		case 25: // Ctrl+Y — redo
+			if t.input.redoEdit() {
+				t.syncPalette()
+			}
+			return nil
+		case 26: // Ctrl+Z — undo
+			if t.input.undoEdit() {
+				t.syncPalette()
+			}
+			return nil
 		}
 	}
 
 	// Printable runes (including pasted newlines).
 	s := string(b)
+	if (len(b) > 1 && strings.ContainsAny(s, "\r\n")) || t.inPasteBurst() {
+		// Several bytes with newlines in one read is a paste, so its newlines are inserted rather
+		// than submitting, which also works for terminals without bracketed paste.
+		t.lastPaste = time.Now()
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
 	for _, r := range s {
 		if r == '\r' || r == '\n' {
 			t.input.insertNewline()
@@ -979,6 +1053,17 @@ func (t *TUI) handleInput(b []byte) func() {
 		t.input.insertRune(r)
 	}
 
+	t.syncPalette()
+	return nil
+}
+
+// inPasteBurst returns true if pasted input arrived recently enough that an Enter is still part of the paste.
+func (t *TUI) inPasteBurst() bool {
+	return !t.lastPaste.IsZero() && time.Since(t.lastPaste) < pasteBurstGap
+}
+
+// syncPalette opens, filters or closes the command palette to match the input.
+func (t *TUI) syncPalette() {
 	// Check for palette activation: / at start of otherwise empty input.
 	text := t.input.text()
 	if strings.HasPrefix(text, "/") {
@@ -991,5 +1076,4 @@ func (t *TUI) handleInput(b []byte) func() {
 	} else if t.palette.active {
 		t.palette.close()
 	}
-	return nil
 }
@@ -1,8 +1,17 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/paularlott/cli"
 )
 
 // --- theme tests ---
@@ -29,6 +38,33 @@ func TestRegisterTheme(t *testing.T) {
 	}
 }
 
+func TestBuiltinThemePalette(t *testing.T) {
+	for _, th := range []*Theme{ThemeDefault, ThemeAmber, ThemeBlue, ThemeGreen, ThemePurple, ThemeLight} {
+		colors := map[string]Color{
+			"Accent":  th.Accent,
+			"Warning": th.Warning,
+			"Success": th.Success,
+			"Error":   th.Error,
+			"Muted":   th.Muted,
+		}
+		for field, c := range colors {
+			if c == 0 {
+				t.Errorf("theme %q: %s not set", th.Name, field)
+			}
+		}
+	}
+}
+
+func TestThemePaletteFallback(t *testing.T) {
+	custom := &Theme{Name: "fallback", Primary: 0x112233, Dim: 0x445566}
+	if custom.accent() != custom.Primary {
+		t.Errorf("expected accent to fall back to Primary, got %06X", custom.accent())
+	}
+	if custom.muted() != custom.Dim {
+		t.Errorf("expected muted to fall back to Dim, got %06X", custom.muted())
+	}
+}
+
 // --- inputArea tests ---
 
 func TestInputAreaBasic(t *testing.T) {
@@ -209,6 +245,91 @@ func TestInputAreaReset(t *testing.T) {
 	}
 }
 
+func TestInputAreaUndoRedo(t *testing.T) {
+	typeText := func(a *inputArea, text string) {
+		for _, r := range text {
+			a.insertRune(r)
+		}
+	}
+
+	tests := []struct {
+		name string
+		edit func(a *inputArea)
+		want string
+	}{
+		{"insert run", func(a *inputArea) { typeText(a, " world") }, "hello"},
+		{"delete run", func(a *inputArea) { a.backspace(); a.backspace() }, "hello"},
+		{"delete forward", func(a *inputArea) { a.home(); a.deleteForward() }, "hello"},
+		{"newline", func(a *inputArea) { a.insertNewline() }, "hello"},
+		{"ctrlK", func(a *inputArea) { a.home(); a.ctrlK() }, "hello"},
+		{"ctrlU", func(a *inputArea) { a.ctrlU() }, "hello"},
+		{"ctrlW", func(a *inputArea) { a.ctrlW() }, "hello"},
+		{"insert after move", func(a *inputArea) { typeText(a, "!"); a.home(); typeText(a, ">") }, "hello!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newInputArea()
+			typeText(a, "hello")
+			a.end() // moving the cursor ends the typing run
+			tt.edit(a)
+			edited := a.text()
+
+			if !a.undoEdit() {
+				t.Fatal("undoEdit should return true")
+			}
+			if a.text() != tt.want {
+				t.Errorf("undo: got %q, want %q", a.text(), tt.want)
+			}
+			if !a.redoEdit() {
+				t.Fatal("redoEdit should return true")
+			}
+			if a.text() != edited {
+				t.Errorf("redo: got %q, want %q", a.text(), edited)
+			}
+			if a.redoEdit() {
+				t.Error("redoEdit with nothing to redo should return false")
+			}
+		})
+	}
+}
+
+func TestInputAreaUndoStack(t *testing.T) {
+	a := newInputArea()
+	a.insertRune('a')
+	a.insertNewline()
+	a.insertRune('b')
+	for _, want := range []string{"a\n", "a", ""} {
+		a.undoEdit()
+		if a.text() != want {
+			t.Errorf("undo: got %q, want %q", a.text(), want)
+		}
+	}
+	if a.undoEdit() {
+		t.Error("undoEdit with nothing to undo should return false")
+	}
+
+	// A new edit clears the redo stack.
+	a.redoEdit()
+	a.insertRune('c')
+	if a.redoEdit() {
+		t.Error("redoEdit after a new edit should return false")
+	}
+
+	// The stack is bounded.
+	for i := 0; i < inputUndoLimit*2; i++ {
+		a.insertNewline()
+	}
+	if len(a.undo) != inputUndoLimit {
+		t.Errorf("expected %d undo entries, got %d", inputUndoLimit, len(a.undo))
+	}
+
+	a.reset()
+	if a.undoEdit() {
+		t.Error("undoEdit after reset should return false")
+	}
+}
+
 // --- palette tests ---
 
 func TestPaletteFilterAndSelect(t *testing.T) {
@@ -341,6 +462,35 @@ func TestOutputRegionScroll(t *testing.T) {
 	}
 }
 
+func TestOutputRegionScrollToExtremes(t *testing.T) {
+	o := &outputRegion{hideHeaders: true}
+	for i := 1; i <= 20; i++ {
+		o.AddMessage(RoleSystem, fmt.Sprintf("line %d", i))
+	}
+
+	first := regexp.MustCompile(`line 1\b`)
+
+	var buf strings.Builder
+	o.scrollToTop()
+	o.render(&buf, ThemeAmber, 80, 5, 1)
+	if o.scrollOff <= 0 || o.scrollOff == math.MaxInt {
+		t.Fatalf("scrollToTop should clamp to the maximum offset, got %d", o.scrollOff)
+	}
+	if !first.MatchString(buf.String()) || strings.Contains(buf.String(), "line 20") {
+		t.Errorf("expected the first lines at the top, got %q", buf.String())
+	}
+
+	o.scrollToBottom()
+	buf.Reset()
+	o.render(&buf, ThemeAmber, 80, 5, 1)
+	if o.scrollOff != 0 {
+		t.Errorf("scrollToBottom: %d", o.scrollOff)
+	}
+	if !strings.Contains(buf.String(), "line 20") || first.MatchString(buf.String()) {
+		t.Errorf("expected the last lines at the bottom, got %q", buf.String())
+	}
+}
+
 func TestAddMessageAs(t *testing.T) {
 	o := &outputRegion{assistantLabel: "Assistant"}
 	o.AddMessageAs(RoleAssistant, "Claude", "hi")
@@ -393,6 +543,9 @@ func TestRenderMessage(t *testing.T) {
 	if !strings.Contains(stripANSI(joined), "hello") {
 		t.Error("rendered message missing content")
 	}
+	if !strings.Contains(joined, fg(ThemeAmber.Accent)) {
+		t.Error("assistant header should use the accent color")
+	}
 }
 
 // --- ANSI helpers ---
@@ -612,3 +765,183 @@ func TestAddRemoveCommand(t *testing.T) {
 		t.Errorf("RemoveCommand unknown: expected 1 command, got %d", len(tui.palette.commands))
 	}
 }
+
+func TestFitStatus(t *testing.T) {
+	left, right := fitStatus("short", "a much longer status text", 20)
+	if visibleLen(left)+visibleLen(right) > 20 {
+		t.Errorf("fitStatus overflow: %q + %q", left, right)
+	}
+	if left != "short" {
+		t.Errorf("expected shorter text untouched, got %q", left)
+	}
+	if !strings.HasSuffix(right, "…") {
+		t.Errorf("expected ellipsis on truncated text, got %q", right)
+	}
+
+	left, right = fitStatus("left", "right", 20)
+	if left != "left" || right != "right" {
+		t.Errorf("fitStatus changed text that fits: %q %q", left, right)
+	}
+}
+
+func TestInputRenderStatusNoOverflow(t *testing.T) {
+	rowStart := regexp.MustCompile(`\x1b\[\d+;\d+H`)
+	for _, w := range []int{20, 30, 40} {
+		a := newInputArea()
+		var buf strings.Builder
+		a.render(&buf, ThemeAmber, w, 10, 1,
+			"a very long overlay message that cannot fit",
+			"model: some-really-long-model-name-here",
+			"1234567890 chars typed so far")
+		for _, line := range rowStart.Split(buf.String(), -1) {
+			if n := visibleLen(line); n > w {
+				t.Errorf("width %d: line overflows (%d): %q", w, n, stripANSI(line))
+			}
+		}
+		if !strings.Contains(buf.String(), "…") {
+			t.Errorf("width %d: expected truncated status with ellipsis", w)
+		}
+	}
+}
+
+func TestMouseClickMovesInputCursor(t *testing.T) {
+	tui := New(Config{})
+	tui.width, tui.height = 80, 24
+	tui.input.setLines("hello\nworld")
+	// Box is 6 rows high at the bottom: border, padding, 2 content rows, padding, border.
+	// Content rows are on 21 and 22, text starts in column 5 after "│ > ".
+
+	tui.handleInput([]byte("\x1b[<0;7;22M"))
+	if tui.input.row != 1 || tui.input.col != 2 {
+		t.Errorf("click: expected cursor at 1,2, got %d,%d", tui.input.row, tui.input.col)
+	}
+
+	tui.handleInput([]byte("\x1b[<0;40;21M"))
+	if tui.input.row != 0 || tui.input.col != 5 {
+		t.Errorf("click past end of line: expected cursor at 0,5, got %d,%d", tui.input.row, tui.input.col)
+	}
+
+	tui.handleInput([]byte("\x1b[<0;7;5M"))
+	if tui.input.row != 0 || tui.input.col != 5 {
+		t.Errorf("click outside input box should be ignored, got %d,%d", tui.input.row, tui.input.col)
+	}
+
+	tui.handleInput([]byte("\x1b[<0;5;22m"))
+	if tui.input.row != 0 || tui.input.col != 5 {
+		t.Errorf("button release should be ignored, got %d,%d", tui.input.row, tui.input.col)
+	}
+}
+
+func TestCommandsFromCLI(t *testing.T) {
+	type ctxKey struct{}
+	var ran []string
+	var gotValue any
+	root := &cli.Command{
+		Name:  "app",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "format", Global: true, Choices: []string{"json", "text"}}},
+		Commands: []*cli.Command{
+			{
+				Name:  "server",
+				Usage: "Manage the server",
+				Commands: []*cli.Command{
+					{
+						Name: "start",
+						Run: func(ctx context.Context, cmd *cli.Command) error {
+							ran = append(ran, "server start "+strings.Join(cmd.GetArgs(), ","))
+							gotValue = ctx.Value(ctxKey{})
+							return nil
+						},
+						MaxArgs: cli.UnlimitedArgs,
+					},
+					{Name: "stop", Run: func(ctx context.Context, cmd *cli.Command) error { return errors.New("not running") }},
+				},
+			},
+			{
+				Name:      "get",
+				Usage:     "Get a resource",
+				ValidArgs: []string{"pods", "services"},
+				Flags:     []cli.Flag{&cli.StringFlag{Name: "level", Choices: []string{"debug", "info"}}},
+			},
+			{
+				Name:      "set",
+				Usage:     "Set the status",
+				Arguments: []cli.Argument{&cli.StringArg{Name: "status", Choices: []string{"active", "paused"}}},
+			},
+		},
+	}
+
+	tui := New(Config{})
+	tui.ctx = context.WithValue(context.Background(), ctxKey{}, "tui")
+	cmds := CommandsFromCLI(tui, root)
+	if len(cmds) != 3 {
+		t.Fatalf("expected 3 commands, got %d", len(cmds))
+	}
+	if cmds[0].Name != "server" || cmds[0].Description != "Manage the server" {
+		t.Errorf("unexpected server command %+v", cmds[0])
+	}
+	wantArgs := [][]string{
+		{"start", "stop", "--format=json", "--format=text"},
+		{"pods", "services", "--level=debug", "--level=info", "--format=json", "--format=text"},
+		{"active", "paused", "--format=json", "--format=text"},
+	}
+	for i, want := range wantArgs {
+		if !reflect.DeepEqual(cmds[i].Args, want) {
+			t.Errorf("expected args %v for %s, got %v", want, cmds[i].Name, cmds[i].Args)
+		}
+	}
+
+	cmds[0].Handler("start a b")
+	if !reflect.DeepEqual(ran, []string{"server start a,b"}) {
+		t.Errorf("expected handler to run the subcommand, got %v", ran)
+	}
+	if gotValue != "tui" {
+		t.Errorf("expected the command to run with the TUI context, got value %v", gotValue)
+	}
+
+	// Errors and the output of the command, here the help, are added to the TUI
+	cmds[0].Handler("stop")
+	cmds[0].Handler("--help")
+	msgs := tui.output.messages
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+	if msgs[0].role != RoleSystem || msgs[0].content != "Error: not running" {
+		t.Errorf("expected the error as a system message, got %+v", msgs[0])
+	}
+	if msgs[1].role != RoleAssistant || !strings.Contains(msgs[1].content, "Manage the server") {
+		t.Errorf("expected the help as an assistant message, got %+v", msgs[1])
+	}
+	if root.Output != nil {
+		t.Errorf("expected the output of the root to be restored, got %v", root.Output)
+	}
+}
+
+func TestPasteInsertsNewlines(t *testing.T) {
+	var submitted []string
+	tui := New(Config{OnSubmit: func(text string) { submitted = append(submitted, text) }})
+
+	if cb := tui.handleInput([]byte("line one\nline two\r\nline three\r")); cb != nil {
+		cb()
+	}
+	if got, want := tui.input.text(), "line one\nline two\nline three\n"; got != want {
+		t.Errorf("expected pasted text %q, got %q", want, got)
+	}
+
+	// An Enter straight after the paste is still part of it, the paste time is reset so a slow run can't end the burst.
+	tui.lastPaste = time.Now()
+	if cb := tui.handleInput([]byte("\r")); cb != nil {
+		cb()
+	}
+	if len(submitted) != 0 || len(tui.input.lines) != 5 {
+		t.Errorf("expected Enter during the paste to insert a newline, got %d lines and submitted %q", len(tui.input.lines), submitted)
+	}
+
+	// Once the paste is over Enter submits.
+	tui.lastPaste = time.Time{}
+	if cb := tui.handleInput([]byte("\r")); cb != nil {
+		cb()
+	}
+	if len(submitted) != 1 || submitted[0] != "line one\nline two\nline three" {
+		t.Errorf("expected the pasted text to be submitted, got %q", submitted)
+	}
+}
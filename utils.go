@@ -1,9 +1,37 @@
 package cli
 
 import (
+	"fmt"
+	"net"
+	"net/url"
 	"reflect"
+	"strconv"
 )
 
+var (
+	ipType    = reflect.TypeOf(net.IP(nil))
+	ipNetType = reflect.TypeOf((*net.IPNet)(nil))
+	urlType   = reflect.TypeOf((*url.URL)(nil))
+)
+
+// isSliceType returns true if t holds a list of values, net.IP is a byte slice but holds a single address
+func isSliceType(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t != ipType
+}
+
+// configValueString formats a value read from a configuration file for parsing by a flag, floats are written without an
+// exponent so whole numbers decoded from JSON such as 2000000 can be parsed by integer flags
+func configValueString(v any) string {
+	switch n := v.(type) {
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(n), 'f', -1, 32)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
 // GetTypeText returns a string representation of a type for help text display
 func GetTypeText(value interface{}) string {
 	t := reflect.TypeOf(value)
@@ -13,6 +41,15 @@ func GetTypeText(value interface{}) string {
 		return "value"
 	}
 
+	switch t {
+	case ipType:
+		return "ip"
+	case ipNetType:
+		return "cidr"
+	case urlType:
+		return "url"
+	}
+
 	switch t.Kind() {
 	case reflect.String:
 		return "string"
@@ -31,13 +68,33 @@ func GetTypeText(value interface{}) string {
 			return "ints"
 		} else if t.Elem().Kind() == reflect.Float32 || t.Elem().Kind() == reflect.Float64 {
 			return "floats"
+		} else if t.Elem() == ipType {
+			return "ips"
+		} else if t.Elem() == ipNetType {
+			return "cidrs"
+		} else if t.Elem() == urlType {
+			return "urls"
 		}
 		return "values"
+	case reflect.Map:
+		return "key=value"
 	default:
 		return "value"
 	}
 }
 
+// MaskSecret masks a secret for display, keeping only the first and last 4 characters, e.g. "sk-1***wxyz".
+// Secrets of 8 characters or less are fully masked as "[***]" and an empty secret is shown as "[empty]".
+func MaskSecret(s string) string {
+	if s == "" {
+		return "[empty]"
+	}
+	if len(s) <= 8 {
+		return "[***]"
+	}
+	return s[:4] + "***" + s[len(s)-4:]
+}
+
 // StrToPtr converts a string to a pointer to a string.
 func StrToPtr(v string) *string {
 	return &v
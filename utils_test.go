@@ -53,3 +53,32 @@ func TestStrToPtr(t *testing.T) {
 		t.Error("StrToPtr should return a new pointer, not the address of the argument")
 	}
 }
+
+func TestMaskSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		want   string
+	}{
+		{"empty", "", "[empty]"},
+		{"short", "abc", "[***]"},
+		{"eight characters", "abcdefgh", "[***]"},
+		{"long", "sk-1234567890wxyz", "sk-1***wxyz"},
+		{"nine characters", "abcdefghi", "abcd***fghi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaskSecret(tt.secret); got != tt.want {
+				t.Errorf("MaskSecret(%q) = %q, want %q", tt.secret, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretFlagDefaultTextMasked(t *testing.T) {
+	flag := &StringFlag{Name: "api-key", DefaultValue: "sk-1234567890wxyz", Secret: true}
+	if got := flag.defaultValueText(); got != "sk-1***wxyz" {
+		t.Errorf("expected masked default, got %q", got)
+	}
+}
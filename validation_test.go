@@ -2,8 +2,10 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -208,3 +210,120 @@ func TestArgumentValidationWithHelp(t *testing.T) {
 		t.Fatalf("expected no error when showing help with required argument, got %v", err)
 	}
 }
+
+func TestOnValidationError(t *testing.T) {
+	var hookCmd *Command
+	newCmd := func() *Command {
+		return &Command{
+			Name: "test",
+			OnValidationError: func(c *Command, err error) error {
+				hookCmd = c
+				return fmt.Errorf("%w\nRun '%s --help' for usage", err, c.Name)
+			},
+			Commands: []*Command{
+				{
+					Name: "sub",
+					Flags: []Flag{
+						&IntFlag{
+							Name: "port",
+							ValidateFlag: func(c *Command) error {
+								if c.GetInt("port") > 65535 {
+									return errors.New("port out of range")
+								}
+								return nil
+							},
+						},
+						&StringFlag{Name: "name", Required: true},
+					},
+					Run: func(ctx context.Context, cmd *Command) error { return nil },
+				},
+			},
+		}
+	}
+
+	os.Args = []string{"test", "sub", "--port", "80"}
+	err := newCmd().Execute(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "required flag 'name' not set") || !strings.Contains(err.Error(), "Run 'sub --help' for usage") {
+		t.Fatalf("expected wrapped required flag error, got %v", err)
+	}
+	if hookCmd == nil || hookCmd.Name != "sub" {
+		t.Fatalf("expected hook to receive the matched command, got %v", hookCmd)
+	}
+
+	os.Args = []string{"test", "sub", "--name", "x", "--port", "70000"}
+	err = newCmd().Execute(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "port out of range") || !strings.Contains(err.Error(), "--help") {
+		t.Fatalf("expected wrapped validation error, got %v", err)
+	}
+}
+
+func TestOnValidationError_NilIgnoresFailure(t *testing.T) {
+	var ran bool
+	var args []string
+	cmd := &Command{
+		Name:              "test",
+		MaxArgs:           UnlimitedArgs,
+		OnValidationError: func(c *Command, err error) error { return nil },
+		Flags:             []Flag{&StringFlag{Name: "name", Required: true}},
+		Run: func(ctx context.Context, cmd *Command) error {
+			ran, args = true, cmd.GetArgs()
+			return nil
+		},
+	}
+
+	cmd.SetArgs([]string{"one", "two"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("expected the hook to ignore the failure, got %v", err)
+	}
+	if !ran || len(args) != 2 || args[0] != "one" {
+		t.Errorf("expected the command to run with its arguments, got ran %v, args %v", ran, args)
+	}
+}
+
+func TestAggregateErrors(t *testing.T) {
+	newCmd := func(aggregate bool) *Command {
+		return &Command{
+			Name:            "test",
+			AggregateErrors: aggregate,
+			Flags: []Flag{
+				&StringFlag{Name: "host", Required: true},
+				&StringFlag{Name: "user", Required: true},
+				&StringFlag{Name: "format", Choices: []string{"json", "yaml"}},
+				&IntFlag{
+					Name: "port",
+					ValidateFlag: func(c *Command) error {
+						if c.GetInt("port") > 65535 {
+							return errors.New("port out of range")
+						}
+						return nil
+					},
+				},
+			},
+			Run: func(ctx context.Context, cmd *Command) error { return nil },
+		}
+	}
+
+	args := []string{"--format", "xml", "--port", "70000"}
+
+	cmd := newCmd(false)
+	cmd.SetArgs(args)
+	err := cmd.Execute(context.Background())
+	if err == nil || err.Error() != "required flag 'host' not set" {
+		t.Fatalf("expected only the first error by default, got %v", err)
+	}
+
+	cmd = newCmd(true)
+	cmd.SetArgs(args)
+	err = cmd.Execute(context.Background())
+	want := "required flag 'host' not set\nrequired flag 'user' not set\n" +
+		"invalid value \"xml\" for --format, must be one of: json, yaml\nport out of range"
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected all errors together, got %v", err)
+	}
+
+	cmd = newCmd(true)
+	cmd.SetArgs([]string{"--host", "h", "--user", "u"})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
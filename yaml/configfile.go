@@ -0,0 +1,41 @@
+package cli_yaml
+
+import (
+	"github.com/paularlott/cli"
+
+	"go.yaml.in/yaml/v3"
+)
+
+type yamlConfiguration struct {
+	cli.ConfigFileBase
+}
+
+func NewConfigFile(fileName *string, searchPathFunc cli.SearchPathFunc) cli.ConfigFileSource {
+	cfg := &yamlConfiguration{}
+
+	cfg.InitConfigFile()
+
+	cfg.FileName = fileName
+	cfg.SearchPath = searchPathFunc
+	cfg.Unmarshal = yaml.Unmarshal
+	cfg.Marshal = yaml.Marshal
+
+	return cfg
+}
+
+// NewConfigReader returns a configuration source that reads YAML from data rather than a file, e.g. for tests.
+// FileUsed reports "[memory]" and Save does nothing.
+func NewConfigReader(data []byte) cli.ConfigFileSource {
+	cfg := &yamlConfiguration{}
+
+	cfg.InitConfigFile()
+
+	if data == nil {
+		data = []byte{}
+	}
+	cfg.Content = data
+	cfg.Unmarshal = yaml.Unmarshal
+	cfg.Marshal = yaml.Marshal
+
+	return cfg
+}
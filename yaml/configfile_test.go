@@ -0,0 +1,129 @@
+package cli_yaml
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paularlott/cli"
+)
+
+const testConfig = `name: demo
+server:
+  port: 8080
+  host: localhost
+  tags: [a, b]
+logging:
+  level: info
+backends:
+  - name: one
+    weight: 1
+  - name: two
+    weight: 2
+`
+
+func TestNewConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg := NewConfigFile(&path, nil)
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("LoadData failed: %v", err)
+	}
+	if got := cfg.FileUsed(); got != path {
+		t.Errorf("expected FileUsed to be %q, got %q", path, got)
+	}
+
+	if port, ok := cfg.GetValue("server.port"); !ok || port != 8080 {
+		t.Errorf("expected server.port 8080, got %v", port)
+	}
+	if keys := strings.Join(cli.GetKeysRecursive(cfg, "server"), ","); keys != "server.host,server.port,server.tags" {
+		t.Errorf("unexpected server keys %s", keys)
+	}
+
+	if err := cfg.SetValue("server.port", 9090); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := cfg.SetValue("cache.size", 64); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := cfg.DeleteKey("server.host"); err != nil {
+		t.Fatalf("DeleteKey failed: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reloaded := cli.NewTypedConfigFile(NewConfigFile(&path, nil))
+	if port := reloaded.GetInt("server.port"); port != 9090 {
+		t.Errorf("expected port 9090 after reload, got %d", port)
+	}
+	if size := reloaded.GetInt64("cache.size"); size != 64 {
+		t.Errorf("expected cache.size 64 after reload, got %d", size)
+	}
+	if _, ok := reloaded.GetValue("server.host"); ok {
+		t.Error("expected server.host to be deleted")
+	}
+	if tags := strings.Join(reloaded.GetStringSlice("server.tags"), ","); tags != "a,b" {
+		t.Errorf("expected tags a,b, got %s", tags)
+	}
+
+	backends := reloaded.GetObjectSlice("backends")
+	if len(backends) != 2 || backends[1].GetString("name") != "two" || backends[1].GetUint("weight") != 2 {
+		t.Errorf("unexpected backends %v", backends)
+	}
+}
+
+func TestNewConfigReader(t *testing.T) {
+	cfg := NewConfigReader([]byte(testConfig))
+
+	if err := cfg.LoadData(); err != nil {
+		t.Fatalf("LoadData failed: %v", err)
+	}
+	if got := cfg.FileUsed(); got != "[memory]" {
+		t.Errorf("expected FileUsed to be [memory], got %q", got)
+	}
+	if level, ok := cfg.GetValue("logging.level"); !ok || level != "info" {
+		t.Errorf("expected logging.level info, got %v", level)
+	}
+}
+
+func TestNewConfigReaderInvalid(t *testing.T) {
+	cfg := NewConfigReader([]byte("server: [valid"))
+	if err := cfg.LoadData(); err == nil {
+		t.Error("expected an error for invalid YAML")
+	}
+}
+
+func TestFlagsFromConfig(t *testing.T) {
+	var port int
+	var verbose bool
+	cmd := &cli.Command{
+		Name:       "app",
+		ConfigFile: NewConfigReader([]byte("server:\n  port: 8080\nlog:\n  verbose: false\n")),
+		Flags: []cli.Flag{
+			&cli.IntFlag{Name: "port", ConfigPath: []string{"server.port"}},
+			&cli.BoolFlag{Name: "verbose", DefaultValue: true, ConfigPath: []string{"log.verbose"}},
+		},
+		Run: func(ctx context.Context, cmd *cli.Command) error {
+			port = cmd.GetInt("port")
+			verbose = cmd.GetBool("verbose")
+			return nil
+		},
+	}
+
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if port != 8080 {
+		t.Errorf("expected port 8080 from the config, got %d", port)
+	}
+	if verbose {
+		t.Error("expected verbose = false in the config to override the true default")
+	}
+}